@@ -10,6 +10,7 @@ import (
 	"github.com/amoylab/unla/internal/apiserver/database"
 	apiserverHandler "github.com/amoylab/unla/internal/apiserver/handler"
 	"github.com/amoylab/unla/internal/apiserver/middleware"
+	"github.com/amoylab/unla/internal/apiserver/webhook"
 	"github.com/amoylab/unla/internal/auth/jwt"
 	"github.com/amoylab/unla/internal/common/cnst"
 	"github.com/amoylab/unla/internal/common/config"
@@ -37,6 +38,21 @@ var (
 		},
 	}
 
+	migrateCmd = &cobra.Command{
+		Use:   "migrate [status|up|rollback]",
+		Short: "Manage database schema migrations",
+		Args:  cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			action := "up"
+			if len(args) > 0 {
+				action = args[0]
+			}
+			if err := runMigrateCommand(action); err != nil {
+				fmt.Printf("migrate %s failed: %v\n", action, err)
+				os.Exit(1)
+			}
+		},
+	}
 	rootCmd = &cobra.Command{
 		Use:   "apiserver",
 		Short: "MCP API Server",
@@ -50,6 +66,7 @@ var (
 func init() {
 	rootCmd.PersistentFlags().StringVarP(&configPath, "conf", "c", cnst.ApiServerYaml, "path to configuration file, like /etc/unla/apiserver.yaml")
 	rootCmd.AddCommand(versionCmd)
+	rootCmd.AddCommand(migrateCmd)
 }
 
 // initLogger initializes the application logger
@@ -160,34 +177,59 @@ func initRouter(db database.Database, store storage.Store, ntf notifier.Notifier
 	authG := r.Group("/api/auth")
 	authG.POST("/login", authH.Login)
 
+	// OIDC SSO login (when configured)
+	if cfg.OIDC != nil {
+		oidcH := apiserverHandler.NewOIDC(db, jwtService, cfg.OIDC, logger)
+		authG.GET("/oidc/login", oidcH.HandleLogin)
+		authG.GET("/oidc/callback", oidcH.HandleCallback)
+	}
+
+	// Chat attachments; one instance signs uploads and verifies downloads
+	attachmentHandler, err := apiserverHandler.NewAttachment(&cfg.Attachments, logger)
+	if err != nil {
+		logger.Fatal("failed to initialize attachments", zap.Error(err))
+	}
+
 	// Protected routes
 	protected := r.Group("/api")
-	protected.Use(middleware.JWTAuthMiddleware(jwtService))
+	protected.Use(middleware.AuthMiddleware(jwtService, db))
 	{
 		chatHandler := apiserverHandler.NewChat(db, logger)
 		mcpHandler := apiserverHandler.NewMCP(db, store, ntf, logger)
 		openapiHandler := apiserverHandler.NewOpenAPI(db, store, ntf, logger)
 
-		// Auth routes
-		protected.POST("/auth/change-password", authH.ChangePassword)
-		protected.GET("/auth/user/info", authH.GetUserInfo)
-		protected.GET("/auth/user", authH.GetUserWithTenants)
-		protected.GET("/auth/tenants", authH.ListTenants)
+		// Every route either names the scope an API token needs or denies
+		// tokens outright; an unannotated route would reject all tokens
+		denyTokens := middleware.DenyAPITokens()
+		readScope := middleware.RequireScope("config:read")
+		writeScope := middleware.RequireScope("config:write")
+		chatRead := middleware.RequireScope("chat:read")
+		chatWrite := middleware.RequireScope("chat:write")
+		playgroundScope := middleware.RequireScope("playground:use")
+
+		// Auth routes; account management stays interactive-only
+		protected.POST("/auth/change-password", denyTokens, authH.ChangePassword)
+		protected.GET("/auth/user/info", denyTokens, authH.GetUserInfo)
+		protected.GET("/auth/user", denyTokens, authH.GetUserWithTenants)
+		protected.GET("/auth/tenants", denyTokens, authH.ListTenants)
 
 		// User management routes (admin only)
 		userMgmt := protected.Group("/auth/users")
-		userMgmt.Use(apiserverHandler.AdminAuthMiddleware())
+		userMgmt.Use(denyTokens, apiserverHandler.AdminAuthMiddleware())
 		{
 			userMgmt.GET("", authH.ListUsers)
 			userMgmt.POST("", authH.CreateUser)
 			userMgmt.PUT("", authH.UpdateUser)
 			userMgmt.DELETE("/:username", authH.DeleteUser)
 			userMgmt.GET("/:username", authH.GetUserWithTenants)
+			userMgmt.GET("/deleted/all", authH.ListDeletedUsers)
+			userMgmt.POST("/:username/restore", authH.RestoreUser)
 			userMgmt.PUT("/tenants", authH.UpdateUserTenants)
 		}
 
 		// Tenant management routes (admin only)
 		tenantMgmt := protected.Group("/auth/tenants")
+		tenantMgmt.Use(denyTokens)
 		{
 			tenantMgmt.POST("", authH.CreateTenant)
 			tenantMgmt.GET("/:name", authH.GetTenantInfo)
@@ -198,33 +240,65 @@ func initRouter(db database.Database, store storage.Store, ntf notifier.Notifier
 			tenantMgmt.DELETE("/:name", authH.DeleteTenant)
 		}
 
+		// API token routes
+		tokenHandler := apiserverHandler.NewToken(db, logger)
+		protected.POST("/auth/tokens", denyTokens, tokenHandler.HandleCreateToken)
+		protected.GET("/auth/tokens", denyTokens, tokenHandler.HandleListTokens)
+		protected.DELETE("/auth/tokens/:id", denyTokens, tokenHandler.HandleRevokeToken)
+
 		// MCP config routes
 		mcpGroup := protected.Group("/mcp")
 		{
-			mcpGroup.GET("/configs/names", mcpHandler.HandleGetConfigNames)
-			mcpGroup.GET("/configs/versions", mcpHandler.HandleGetConfigVersions)
-			mcpGroup.POST("/configs/:tenant/:name/versions/:version/active", mcpHandler.HandleSetActiveVersion)
-
-			mcpGroup.GET("/configs", mcpHandler.HandleListMCPServers)
-			mcpGroup.POST("/configs", mcpHandler.HandleMCPServerCreate)
-			mcpGroup.PUT("/configs", mcpHandler.HandleMCPServerUpdate)
-			mcpGroup.DELETE("/configs/:tenant/:name", mcpHandler.HandleMCPServerDelete)
-			mcpGroup.POST("/configs/sync", mcpHandler.HandleMCPServerSync)
+			mcpGroup.GET("/configs/names", readScope, mcpHandler.HandleGetConfigNames)
+			mcpGroup.GET("/configs/versions", readScope, mcpHandler.HandleGetConfigVersions)
+			mcpGroup.POST("/configs/:tenant/:name/versions/:version/active", writeScope, mcpHandler.HandleSetActiveVersion)
+
+			mcpGroup.GET("/configs", readScope, mcpHandler.HandleListMCPServers)
+			mcpGroup.POST("/configs", writeScope, mcpHandler.HandleMCPServerCreate)
+			mcpGroup.PUT("/configs", writeScope, mcpHandler.HandleMCPServerUpdate)
+			mcpGroup.DELETE("/configs/:tenant/:name", writeScope, mcpHandler.HandleMCPServerDelete)
+			mcpGroup.POST("/configs/sync", writeScope, mcpHandler.HandleMCPServerSync)
+			mcpGroup.POST("/configs/preview", readScope, mcpHandler.HandleMCPConfigPreview)
+			mcpGroup.GET("/configs/export", readScope, mcpHandler.HandleConfigExport)
+			mcpGroup.POST("/configs/import", writeScope, mcpHandler.HandleConfigImport)
+			mcpGroup.POST("/configs/:tenant/:name/restore", writeScope, mcpHandler.HandleMCPServerRestore)
 		}
 
-		// OpenAPI routes
-		protected.POST("/openapi/import", openapiHandler.HandleImport)
+		protected.POST("/chat/attachments", chatWrite, attachmentHandler.HandleUpload)
+
+		// Chat completion with automatic tool calling
+		gatewayResolver := apiserverHandler.NewGatewayResolver(cfg.GatewayBaseURL, store)
+		completionHandler, err := apiserverHandler.NewCompletion(&cfg.OpenAI, db, gatewayResolver, logger)
+		if err != nil {
+			logger.Fatal("failed to initialize chat completion", zap.Error(err))
+		}
+		protected.POST("/chat/completions", chatWrite, completionHandler.HandleChatComplete)
+		protected.POST("/chat/sessions/:sessionId/generate-title", chatWrite, completionHandler.HandleGenerateTitle)
+		protected.PUT("/chat/sessions/:sessionId/archive", chatWrite, chatHandler.HandleArchiveChatSession)
 
-		protected.GET("/chat/sessions", chatHandler.HandleGetChatSessions)
-		protected.GET("/chat/sessions/:sessionId/messages", chatHandler.HandleGetChatMessages)
-		protected.DELETE("/chat/sessions/:sessionId", chatHandler.HandleDeleteChatSession)
-		protected.PUT("/chat/sessions/:sessionId/title", chatHandler.HandleUpdateChatSessionTitle)
-		protected.POST("/chat/messages", chatHandler.HandleSaveChatMessage)
+		// MCP playground routes
+		playgroundHandler := apiserverHandler.NewPlayground(gatewayResolver, logger)
+		protected.POST("/mcp/playground/tools", playgroundScope, playgroundHandler.HandleListTools)
+		protected.POST("/mcp/playground/call", playgroundScope, playgroundHandler.HandleCallTool)
+
+		// OpenAPI routes
+		protected.POST("/openapi/import", writeScope, openapiHandler.HandleImport)
+
+		protected.GET("/chat/sessions", chatRead, chatHandler.HandleGetChatSessions)
+		protected.GET("/chat/messages/search", chatRead, chatHandler.HandleSearchChatMessages)
+		protected.GET("/chat/sessions/:sessionId/messages", chatRead, chatHandler.HandleGetChatMessages)
+		protected.DELETE("/chat/sessions/:sessionId", chatWrite, chatHandler.HandleDeleteChatSession)
+		protected.PUT("/chat/sessions/:sessionId/title", chatWrite, chatHandler.HandleUpdateChatSessionTitle)
+		protected.POST("/chat/messages", chatWrite, chatHandler.HandleSaveChatMessage)
 	}
 
 	// Public runtime config endpoint for frontend
 	r.GET("/api/runtime-config", apiserverHandler.HandleRuntimeConfig)
 
+	// Signed attachment downloads are authenticated by their signature;
+	// served by the same handler instance that signs upload URLs
+	r.GET("/api/chat/attachments/:id", attachmentHandler.HandleDownload)
+
 	r.Static("/web", "./web")
 	return r
 }
@@ -284,10 +358,59 @@ func run() {
 	store := initStore(logger, &cfg.Storage)
 
 	// Initialize router and start server
+	webhook.Configure(cfg.Webhooks, logger)
+
 	router := initRouter(db, store, ntf, openaiClient, cfg, logger)
 	startServer(logger, router)
 }
 
+// runMigrateCommand applies, reports or rolls back schema migrations
+func runMigrateCommand(action string) error {
+	cfg, _, err := config.LoadConfig[config.APIServerConfig](configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	db, err := database.NewDatabase(&cfg.Database)
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer db.Close()
+
+	gormDB, err := database.GormDB(db)
+	if err != nil {
+		return err
+	}
+
+	switch action {
+	case "up":
+		if err := database.RunMigrations(gormDB); err != nil {
+			return err
+		}
+		fmt.Println("migrations applied")
+	case "status":
+		statuses, err := database.Status(gormDB)
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			state := "pending"
+			if status.Applied {
+				state = "applied " + status.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Printf("%-40s %s\n", status.ID, state)
+		}
+	case "rollback":
+		if err := database.RollbackLast(gormDB); err != nil {
+			return err
+		}
+		fmt.Println("last migration rolled back")
+	default:
+		return fmt.Errorf("unknown action %q, want status, up or rollback", action)
+	}
+	return nil
+}
+
 func main() {
 	if err := rootCmd.Execute(); err != nil {
 		os.Exit(1)
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
@@ -17,7 +18,7 @@ import (
 	"github.com/amoylab/unla/internal/mcp/storage"
 	"github.com/amoylab/unla/internal/mcp/storage/notifier"
 	pidHelper "github.com/amoylab/unla/pkg/helper"
-	"github.com/amoylab/unla/pkg/logger"
+	pkgLogger "github.com/amoylab/unla/pkg/logger"
 	"github.com/amoylab/unla/pkg/utils"
 	"github.com/amoylab/unla/pkg/version"
 
@@ -47,6 +48,17 @@ var (
 				os.Exit(1)
 			}
 
+			// Prefer the admin endpoint when configured; it works on every
+			// platform, unlike SIGHUP
+			if cfg.AdminPort > 0 {
+				if err := reloadViaAdmin(cfg); err != nil {
+					fmt.Printf("Failed to reload via admin endpoint: %v\n", err)
+					os.Exit(1)
+				}
+				fmt.Println("Reload triggered successfully")
+				return
+			}
+
 			// Use PID from config if not provided via command line
 			if pidFile == "" {
 				pidFile = cfg.PID
@@ -71,7 +83,7 @@ var (
 			}
 
 			// Initialize logger
-			logger, err := logger.NewLogger(&cfg.Logger)
+			logger, err := pkgLogger.NewLogger(&cfg.Logger)
 			if err != nil {
 				fmt.Printf("Failed to initialize logger: %v\n", err)
 				os.Exit(1)
@@ -124,6 +136,28 @@ func init() {
 	rootCmd.AddCommand(testCmd)
 }
 
+// reloadViaAdmin posts to the running instance's admin reload endpoint
+func reloadViaAdmin(cfg *config.MCPGatewayConfig) error {
+	client := &http.Client{Timeout: 30 * time.Second}
+	req, err := http.NewRequest(http.MethodPost,
+		fmt.Sprintf("http://127.0.0.1:%d/reload", cfg.AdminPort), nil)
+	if err != nil {
+		return err
+	}
+	if cfg.AdminToken != "" {
+		req.Header.Set("Authorization", "Bearer "+cfg.AdminToken)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("reload endpoint returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
 func run() {
 	ctx, cancel := context.WithCancel(context.Background())
 
@@ -134,7 +168,7 @@ func run() {
 	}
 
 	// Initialize logger with configuration
-	logger, err := logger.NewLogger(&cfg.Logger)
+	logger, err := pkgLogger.NewLogger(&cfg.Logger)
 	if err != nil {
 		panic(fmt.Sprintf("Failed to initialize logger: %v", err))
 	}
@@ -181,7 +215,7 @@ func run() {
 	}
 
 	// Create server instance
-	server, err := core.NewServer(logger, cfg.Port, store, sessionStore, a)
+	server, err := core.NewServer(logger, cfg, store, sessionStore, a)
 	if err != nil {
 		logger.Fatal("Failed to create server", zap.Error(err))
 	}
@@ -208,6 +242,20 @@ func run() {
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 
+	// SIGUSR2 toggles debug logging at runtime
+	usr2 := make(chan os.Signal, 1)
+	signal.Notify(usr2, syscall.SIGUSR2)
+	go func() {
+		for range usr2 {
+			next := "debug"
+			if pkgLogger.Level() == "debug" {
+				next = "info"
+			}
+			_ = pkgLogger.SetLevel(next)
+			logger.Info("log level toggled via SIGUSR2", zap.String("level", next))
+		}
+	}()
+
 	// periodically reload the configuration as a fallback mechanism for the notifier
 	ticker := time.NewTicker(cfg.ReloadInterval)
 	defer ticker.Stop()
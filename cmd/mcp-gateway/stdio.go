@@ -0,0 +1,185 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/amoylab/unla/internal/common/cnst"
+	"github.com/amoylab/unla/internal/common/config"
+	"github.com/amoylab/unla/internal/core"
+	"github.com/amoylab/unla/internal/core/state"
+	"github.com/amoylab/unla/internal/mcp/storage"
+	"github.com/amoylab/unla/pkg/logger"
+	"github.com/amoylab/unla/pkg/mcp"
+	"github.com/amoylab/unla/pkg/version"
+
+	mcpgo "github.com/mark3labs/mcp-go/mcp"
+	mcpserver "github.com/mark3labs/mcp-go/server"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+var (
+	stdioPrefix string
+
+	serveStdioCmd = &cobra.Command{
+		Use:   "serve-stdio",
+		Short: "Expose a single configured prefix as a stdio MCP server",
+		Long: `Serve the tools of one routed prefix over stdin/stdout so desktop MCP
+clients can use the gateway's aggregated tools without HTTP networking.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := runServeStdio(); err != nil {
+				fmt.Fprintf(os.Stderr, "serve-stdio failed: %v\n", err)
+				os.Exit(1)
+			}
+		},
+	}
+)
+
+func init() {
+	serveStdioCmd.Flags().StringVar(&stdioPrefix, "prefix", "", "routed prefix to expose, e.g. /demo")
+	_ = serveStdioCmd.MarkFlagRequired("prefix")
+	rootCmd.AddCommand(serveStdioCmd)
+}
+
+func runServeStdio() error {
+	ctx := context.Background()
+
+	cfg, _, err := config.LoadConfig[config.MCPGatewayConfig](configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	// stdout carries the MCP protocol, so logs must go elsewhere
+	if cfg.Logger.Output == "stdout" || cfg.Logger.Output == "" {
+		cfg.Logger.Output = "stderr"
+	}
+	logger, err := logger.NewLogger(&cfg.Logger)
+	if err != nil {
+		return fmt.Errorf("failed to initialize logger: %w", err)
+	}
+	defer logger.Sync()
+
+	store, err := storage.NewStore(logger, &cfg.Storage)
+	if err != nil {
+		return fmt.Errorf("failed to initialize storage: %w", err)
+	}
+
+	cfgs, err := store.List(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load MCP configurations: %w", err)
+	}
+	if err := config.ValidateMCPConfigs(cfgs); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	st, err := state.BuildStateFromConfig(ctx, cfgs, nil, logger)
+	if err != nil {
+		return fmt.Errorf("failed to build state: %w", err)
+	}
+
+	protoType := st.GetProtoType(stdioPrefix)
+	if protoType == "" {
+		return fmt.Errorf("no server configured for prefix %s", stdioPrefix)
+	}
+
+	var tools []mcp.ToolSchema
+	switch protoType {
+	case cnst.BackendProtoHttp:
+		tools = st.GetToolSchemas(stdioPrefix)
+	default:
+		transport := st.GetTransport(stdioPrefix)
+		if transport == nil {
+			return fmt.Errorf("no transport available for prefix %s", stdioPrefix)
+		}
+		tools, err = transport.FetchTools(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to fetch tools for prefix %s: %w", stdioPrefix, err)
+		}
+	}
+
+	srv := mcpserver.NewMCPServer(cnst.AppName, version.Get())
+	for _, schema := range tools {
+		rawSchema, err := json.Marshal(schema.InputSchema)
+		if err != nil {
+			return fmt.Errorf("failed to marshal input schema for tool %s: %w", schema.Name, err)
+		}
+		srv.AddTool(mcpgo.Tool{
+			Name:           schema.Name,
+			Description:    schema.Description,
+			RawInputSchema: rawSchema,
+		}, makeStdioToolHandler(logger, st, protoType, schema.Name))
+	}
+
+	logger.Info("serving prefix over stdio",
+		zap.String("prefix", stdioPrefix),
+		zap.Int("tool_count", len(tools)))
+	return mcpserver.ServeStdio(srv)
+}
+
+// makeStdioToolHandler dispatches a stdio tool call into the same execution
+// paths the HTTP server uses
+func makeStdioToolHandler(logger *zap.Logger, st *state.State, protoType cnst.ProtoType, toolName string) mcpserver.ToolHandlerFunc {
+	return func(ctx context.Context, request mcpgo.CallToolRequest) (*mcpgo.CallToolResult, error) {
+		rawArgs, err := json.Marshal(request.Params.Arguments)
+		if err != nil {
+			return nil, fmt.Errorf("invalid tool arguments: %w", err)
+		}
+
+		var result *mcp.CallToolResult
+		switch protoType {
+		case cnst.BackendProtoHttp:
+			tool := st.GetTool(stdioPrefix, toolName)
+			if tool == nil {
+				return nil, fmt.Errorf("tool not found: %s", toolName)
+			}
+			serverCfg := st.GetServerConfig(stdioPrefix)
+			if serverCfg == nil {
+				return nil, fmt.Errorf("server configuration not found for prefix %s", stdioPrefix)
+			}
+			var args map[string]any
+			if err := json.Unmarshal(rawArgs, &args); err != nil {
+				return nil, fmt.Errorf("invalid tool arguments: %w", err)
+			}
+			if args == nil {
+				args = make(map[string]any)
+			}
+			result, err = core.InvokeHTTPTool(ctx, tool, serverCfg.Config, args)
+		default:
+			transport := st.GetTransport(stdioPrefix)
+			if transport == nil {
+				return nil, fmt.Errorf("no transport available for prefix %s", stdioPrefix)
+			}
+			result, err = transport.CallTool(ctx, mcp.CallToolParams{
+				Name:      toolName,
+				Arguments: rawArgs,
+			}, nil)
+		}
+		if err != nil {
+			logger.Error("stdio tool call failed",
+				zap.String("tool", toolName),
+				zap.Error(err))
+			return nil, err
+		}
+
+		return convertToMCPGoResult(result), nil
+	}
+}
+
+// convertToMCPGoResult converts the local CallToolResult into the mcp-go shape
+func convertToMCPGoResult(result *mcp.CallToolResult) *mcpgo.CallToolResult {
+	out := &mcpgo.CallToolResult{IsError: result.IsError}
+	for _, content := range result.Content {
+		switch c := content.(type) {
+		case *mcp.TextContent:
+			out.Content = append(out.Content, mcpgo.TextContent{Type: "text", Text: c.Text})
+		case *mcp.ImageContent:
+			out.Content = append(out.Content, mcpgo.ImageContent{Type: "image", Data: c.Data, MIMEType: c.MimeType})
+		case *mcp.AudioContent:
+			out.Content = append(out.Content, mcpgo.AudioContent{Type: "audio", Data: c.Data, MIMEType: c.MimeType})
+		}
+	}
+	return out
+}
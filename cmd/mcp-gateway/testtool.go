@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/amoylab/unla/internal/common/cnst"
+	"github.com/amoylab/unla/internal/common/config"
+	"github.com/amoylab/unla/internal/core"
+	"github.com/amoylab/unla/internal/core/state"
+	"github.com/amoylab/unla/internal/mcp/storage"
+	pkgLogger "github.com/amoylab/unla/pkg/logger"
+	"github.com/amoylab/unla/pkg/mcp"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	testToolPrefix string
+	testToolName   string
+	testToolArgs   string
+	testToolDryRun bool
+
+	testToolCmd = &cobra.Command{
+		Use:   "test-tool",
+		Short: "Execute a configured tool from the CLI",
+		Long: `Load the configured storage, render the given tool's templates with the
+supplied arguments, and either execute it against the real backend or print
+the outgoing request with --dry-run.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := runTestTool(); err != nil {
+				fmt.Fprintf(os.Stderr, "test-tool failed: %v\n", err)
+				os.Exit(1)
+			}
+		},
+	}
+)
+
+func init() {
+	testToolCmd.Flags().StringVar(&testToolPrefix, "prefix", "", "routed prefix holding the tool")
+	testToolCmd.Flags().StringVar(&testToolName, "tool", "", "tool name to execute")
+	testToolCmd.Flags().StringVar(&testToolArgs, "args", "{}", "tool arguments as JSON")
+	testToolCmd.Flags().BoolVar(&testToolDryRun, "dry-run", false, "print the outgoing request instead of executing it")
+	_ = testToolCmd.MarkFlagRequired("prefix")
+	_ = testToolCmd.MarkFlagRequired("tool")
+	rootCmd.AddCommand(testToolCmd)
+}
+
+func runTestTool() error {
+	ctx := context.Background()
+
+	cfg, _, err := config.LoadConfig[config.MCPGatewayConfig](configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	cfg.Logger.Output = "stderr"
+	logger, err := pkgLogger.NewLogger(&cfg.Logger)
+	if err != nil {
+		return err
+	}
+
+	store, err := storage.NewStore(logger, &cfg.Storage)
+	if err != nil {
+		return fmt.Errorf("failed to initialize storage: %w", err)
+	}
+	cfgs, err := store.List(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load MCP configurations: %w", err)
+	}
+	st, err := state.BuildStateFromConfig(ctx, cfgs, nil, logger)
+	if err != nil {
+		return fmt.Errorf("failed to build state: %w", err)
+	}
+
+	var args map[string]any
+	if err := json.Unmarshal([]byte(testToolArgs), &args); err != nil {
+		return fmt.Errorf("invalid --args JSON: %w", err)
+	}
+
+	protoType := st.GetProtoType(testToolPrefix)
+	switch protoType {
+	case "":
+		return fmt.Errorf("no server configured for prefix %s", testToolPrefix)
+	case cnst.BackendProtoHttp:
+		tool := st.GetTool(testToolPrefix, testToolName)
+		if tool == nil {
+			return fmt.Errorf("tool %q not found on prefix %s", testToolName, testToolPrefix)
+		}
+		serverCfg := st.GetServerConfig(testToolPrefix)
+		if serverCfg == nil {
+			return fmt.Errorf("server configuration not found for prefix %s", testToolPrefix)
+		}
+
+		if testToolDryRun {
+			method, url, headers, body, err := core.DescribeHTTPToolRequest(tool, serverCfg.Config, args)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("%s %s\n", method, url)
+			for name, value := range headers {
+				fmt.Printf("%s: %s\n", name, value)
+			}
+			if body != "" {
+				fmt.Printf("\n%s\n", body)
+			}
+			return nil
+		}
+
+		result, err := core.InvokeHTTPTool(ctx, tool, serverCfg.Config, args)
+		if err != nil {
+			return err
+		}
+		return printCallToolResult(result)
+	default:
+		if testToolDryRun {
+			return fmt.Errorf("--dry-run is only supported for HTTP tools")
+		}
+		transport := st.GetTransport(testToolPrefix)
+		if transport == nil {
+			return fmt.Errorf("no transport available for prefix %s", testToolPrefix)
+		}
+		raw, err := json.Marshal(args)
+		if err != nil {
+			return err
+		}
+		result, err := transport.CallTool(ctx, mcp.CallToolParams{
+			Name:      testToolName,
+			Arguments: raw,
+		}, nil)
+		if err != nil {
+			return err
+		}
+		return printCallToolResult(result)
+	}
+}
+
+// printCallToolResult renders a CallToolResult as indented JSON
+func printCallToolResult(result *mcp.CallToolResult) error {
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(data))
+	return nil
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/amoylab/unla/internal/common/config"
+	"github.com/amoylab/unla/internal/mcp/storage"
+	"github.com/amoylab/unla/internal/template"
+	"github.com/amoylab/unla/pkg/logger"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var validateCmd = &cobra.Command{
+	Use:   "validate [config files...]",
+	Short: "Validate MCP configuration files or the configured storage",
+	Long: `Load MCP configuration files (or the configured storage when no files are
+given), merge them, and check tool schemas, template syntax, duplicate names
+and prefix collisions. Exits non-zero on any validation failure for CI use.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runValidate(args); err != nil {
+			fmt.Fprintf(os.Stderr, "validation failed:\n%v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println("Configuration is valid")
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(validateCmd)
+}
+
+func runValidate(files []string) error {
+	var cfgs []*config.MCPConfig
+
+	if len(files) > 0 {
+		merged := make([]*config.MCPConfig, 0, len(files))
+		for _, file := range files {
+			data, err := os.ReadFile(file)
+			if err != nil {
+				return fmt.Errorf("%s: %w", file, err)
+			}
+			var cfg config.MCPConfig
+			if err := yaml.Unmarshal(data, &cfg); err != nil {
+				return fmt.Errorf("%s: invalid YAML: %w", file, err)
+			}
+			merged = config.MergeConfigs(merged, &cfg)
+		}
+		cfgs = merged
+	} else {
+		gwCfg, _, err := config.LoadConfig[config.MCPGatewayConfig](configPath)
+		if err != nil {
+			return fmt.Errorf("failed to load gateway config: %w", err)
+		}
+		gwCfg.Logger.Output = "stderr"
+		logger, err := logger.NewLogger(&gwCfg.Logger)
+		if err != nil {
+			return fmt.Errorf("failed to initialize logger: %w", err)
+		}
+		store, err := storage.NewStore(logger, &gwCfg.Storage)
+		if err != nil {
+			return fmt.Errorf("failed to initialize storage: %w", err)
+		}
+		cfgs, err = store.List(context.Background())
+		if err != nil {
+			return fmt.Errorf("failed to load MCP configurations: %w", err)
+		}
+	}
+
+	if err := config.ValidateMCPConfigs(cfgs); err != nil {
+		return err
+	}
+
+	return template.ValidateToolTemplates(cfgs)
+}
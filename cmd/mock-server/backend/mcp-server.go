@@ -308,15 +308,9 @@ func handleEchoTool(
 	ctx context.Context,
 	request mcp.CallToolRequest,
 ) (*mcp.CallToolResult, error) {
-	// Convert interface{} to json.RawMessage
-	argumentsBytes, ok := request.Params.Arguments.(json.RawMessage)
-	if !ok {
-		return nil, fmt.Errorf("invalid arguments type")
-	}
-
-	var arguments map[string]interface{}
-	if err := json.Unmarshal(argumentsBytes, &arguments); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal arguments: %w", err)
+	arguments, err := decodeArguments(request.Params.Arguments)
+	if err != nil {
+		return nil, err
 	}
 
 	message, ok := arguments["message"].(string)
@@ -337,15 +331,9 @@ func handleAddTool(
 	ctx context.Context,
 	request mcp.CallToolRequest,
 ) (*mcp.CallToolResult, error) {
-	// Convert interface{} to json.RawMessage
-	argumentsBytes, ok := request.Params.Arguments.(json.RawMessage)
-	if !ok {
-		return nil, fmt.Errorf("invalid arguments type")
-	}
-
-	var arguments map[string]interface{}
-	if err := json.Unmarshal(argumentsBytes, &arguments); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal arguments: %w", err)
+	arguments, err := decodeArguments(request.Params.Arguments)
+	if err != nil {
+		return nil, err
 	}
 
 	a, ok1 := arguments["a"].(float64)
@@ -398,15 +386,9 @@ func handleLongRunningOperationTool(
 	ctx context.Context,
 	request mcp.CallToolRequest,
 ) (*mcp.CallToolResult, error) {
-	// Convert interface{} to json.RawMessage
-	argumentsBytes, ok := request.Params.Arguments.(json.RawMessage)
-	if !ok {
-		return nil, fmt.Errorf("invalid arguments type")
-	}
-
-	var arguments map[string]interface{}
-	if err := json.Unmarshal(argumentsBytes, &arguments); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal arguments: %w", err)
+	arguments, err := decodeArguments(request.Params.Arguments)
+	if err != nil {
+		return nil, err
 	}
 
 	progressToken := request.Params.Meta.ProgressToken
@@ -477,3 +459,22 @@ func handleNotification(
 ) {
 	log.Printf("Received notification: %s", notification.Method)
 }
+
+// decodeArguments accepts both the decoded map and raw JSON forms the
+// client library may deliver for tool arguments
+func decodeArguments(raw any) (map[string]interface{}, error) {
+	switch typed := raw.(type) {
+	case map[string]interface{}:
+		return typed, nil
+	case json.RawMessage:
+		var arguments map[string]interface{}
+		if err := json.Unmarshal(typed, &arguments); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal arguments: %w", err)
+		}
+		return arguments, nil
+	case nil:
+		return map[string]interface{}{}, nil
+	default:
+		return nil, fmt.Errorf("invalid arguments type")
+	}
+}
@@ -9,9 +9,9 @@ import (
 
 	"go.uber.org/zap"
 
-	"github.com/mark3labs/mcp-go/server"
 	"github.com/amoylab/unla/cmd/mock-server/backend"
 	"github.com/amoylab/unla/pkg/version"
+	"github.com/mark3labs/mcp-go/server"
 	"github.com/spf13/cobra"
 )
 
@@ -15,6 +15,8 @@ type Database interface {
 	GetMessages(ctx context.Context, sessionID string) ([]*Message, error)
 	// GetMessagesWithPagination gets messages for a specific session with pagination.
 	GetMessagesWithPagination(ctx context.Context, sessionID string, page, pageSize int) ([]*Message, error)
+	// SearchMessages finds messages whose content matches the query.
+	SearchMessages(ctx context.Context, query string, page, pageSize int) ([]*Message, error)
 	// CreateSession creates a new session with the given sessionId.
 	CreateSession(ctx context.Context, sessionId string) error
 	// CreateSessionWithTitle creates a new session with the given sessionId and title.
@@ -25,14 +27,20 @@ type Database interface {
 	GetSessions(ctx context.Context) ([]*Session, error)
 	// UpdateSessionTitle updates the title of a session.
 	UpdateSessionTitle(ctx context.Context, sessionID string, title string) error
-	// DeleteSession deletes a session by ID.
+	// DeleteSession soft-deletes a session by ID.
 	DeleteSession(ctx context.Context, sessionID string) error
+	// SetSessionArchived archives or unarchives a session.
+	SetSessionArchived(ctx context.Context, sessionID string, archived bool) error
 
 	CreateUser(ctx context.Context, user *User) error
 	GetUserByUsername(ctx context.Context, username string) (*User, error)
 	UpdateUser(ctx context.Context, user *User) error
 	DeleteUser(ctx context.Context, id uint) error
 	ListUsers(ctx context.Context) ([]*User, error)
+	// ListDeletedUsers lists soft-deleted users.
+	ListDeletedUsers(ctx context.Context) ([]*User, error)
+	// RestoreUser clears a user's soft-delete marker.
+	RestoreUser(ctx context.Context, username string) error
 
 	CreateTenant(ctx context.Context, tenant *Tenant) error
 	GetTenantByName(ctx context.Context, name string) (*Tenant, error)
@@ -41,6 +49,17 @@ type Database interface {
 	DeleteTenant(ctx context.Context, id uint) error
 	ListTenants(ctx context.Context) ([]*Tenant, error)
 
+	// CreateAPIToken stores a new API token (hash only).
+	CreateAPIToken(ctx context.Context, token *APIToken) error
+	// GetAPITokenByHash looks up an unrevoked API token by its hash.
+	GetAPITokenByHash(ctx context.Context, hash string) (*APIToken, error)
+	// ListAPITokens lists the API tokens owned by a user.
+	ListAPITokens(ctx context.Context, username string) ([]*APIToken, error)
+	// RevokeAPIToken marks an API token as revoked.
+	RevokeAPIToken(ctx context.Context, id uint, username string) error
+	// TouchAPIToken records when an API token was last used.
+	TouchAPIToken(ctx context.Context, id uint) error
+
 	AddUserToTenant(ctx context.Context, userID, tenantID uint) error
 	RemoveUserFromTenant(ctx context.Context, userID, tenantID uint) error
 	GetUserTenants(ctx context.Context, userID uint) ([]*Tenant, error)
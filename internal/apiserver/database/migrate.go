@@ -0,0 +1,142 @@
+package database
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// SchemaMigration records an applied migration
+type SchemaMigration struct {
+	ID        string    `gorm:"primaryKey;type:varchar(64)"`
+	AppliedAt time.Time `json:"applied_at"`
+}
+
+// Migration is one versioned schema change with an optional rollback
+type Migration struct {
+	ID   string
+	Up   func(db *gorm.DB) error
+	Down func(db *gorm.DB) error
+}
+
+// migrations is the ordered schema history. New changes append a new entry;
+// never edit an applied migration.
+var migrations = []Migration{
+	{
+		ID: "202609010001_baseline",
+		Up: func(db *gorm.DB) error {
+			return db.AutoMigrate(&Message{}, &Session{}, &User{}, &Tenant{}, &UserTenant{}, &APIToken{})
+		},
+		Down: func(db *gorm.DB) error {
+			return fmt.Errorf("the baseline migration cannot be rolled back")
+		},
+	},
+	{
+		ID: "202609010002_soft_delete_users",
+		Up: func(db *gorm.DB) error {
+			// Adds users.deleted_at for soft deletion
+			return db.AutoMigrate(&User{})
+		},
+		Down: func(db *gorm.DB) error {
+			return db.Migrator().DropColumn(&User{}, "deleted_at")
+		},
+	},
+}
+
+// MigrationStatus describes one migration and whether it has been applied
+type MigrationStatus struct {
+	ID        string     `json:"id"`
+	Applied   bool       `json:"applied"`
+	AppliedAt *time.Time `json:"applied_at,omitempty"`
+}
+
+// RunMigrations applies every pending migration in order
+func RunMigrations(db *gorm.DB) error {
+	if err := db.AutoMigrate(&SchemaMigration{}); err != nil {
+		return fmt.Errorf("failed to create migrations table: %w", err)
+	}
+
+	for _, migration := range migrations {
+		var count int64
+		if err := db.Model(&SchemaMigration{}).Where("id = ?", migration.ID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			continue
+		}
+
+		if err := db.Transaction(func(tx *gorm.DB) error {
+			if err := migration.Up(tx); err != nil {
+				return err
+			}
+			return tx.Create(&SchemaMigration{ID: migration.ID, AppliedAt: time.Now()}).Error
+		}); err != nil {
+			return fmt.Errorf("migration %s failed: %w", migration.ID, err)
+		}
+	}
+	return nil
+}
+
+// Status reports every known migration and its applied state
+func Status(db *gorm.DB) ([]MigrationStatus, error) {
+	if err := db.AutoMigrate(&SchemaMigration{}); err != nil {
+		return nil, err
+	}
+
+	var applied []SchemaMigration
+	if err := db.Find(&applied).Error; err != nil {
+		return nil, err
+	}
+	appliedAt := make(map[string]time.Time, len(applied))
+	for _, record := range applied {
+		appliedAt[record.ID] = record.AppliedAt
+	}
+
+	statuses := make([]MigrationStatus, 0, len(migrations))
+	for _, migration := range migrations {
+		status := MigrationStatus{ID: migration.ID}
+		if at, ok := appliedAt[migration.ID]; ok {
+			status.Applied = true
+			status.AppliedAt = &at
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// RollbackLast reverts the most recently applied migration
+func RollbackLast(db *gorm.DB) error {
+	var last SchemaMigration
+	if err := db.Order("applied_at desc").First(&last).Error; err != nil {
+		return fmt.Errorf("no applied migrations to roll back: %w", err)
+	}
+
+	for _, migration := range migrations {
+		if migration.ID != last.ID {
+			continue
+		}
+		return db.Transaction(func(tx *gorm.DB) error {
+			if err := migration.Down(tx); err != nil {
+				return err
+			}
+			return tx.Delete(&SchemaMigration{}, "id = ?", last.ID).Error
+		})
+	}
+	return fmt.Errorf("applied migration %s is unknown to this binary", last.ID)
+}
+
+// GormDB extracts the underlying gorm handle from a Database for
+// migration tooling
+func GormDB(db Database) (*gorm.DB, error) {
+	switch typed := db.(type) {
+	case *MySQL:
+		return typed.db, nil
+	case *Postgres:
+		return typed.db, nil
+	case *SQLite:
+		return typed.db, nil
+	default:
+		return nil, fmt.Errorf("unsupported database implementation %T", db)
+	}
+}
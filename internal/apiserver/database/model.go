@@ -1,12 +1,20 @@
 package database
 
-import "time"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 // Session represents a chat session
 type Session struct {
 	ID        string    `json:"id" gorm:"column:id;type:varchar(64);uniqueIndex"`
 	CreatedAt time.Time `json:"createdAt" gorm:"column:created_at"`
 	Title     string    `json:"title" gorm:"column:title;type:varchar(255)"`
+	// Archived hides a session from the default listing without deleting it
+	Archived bool `json:"archived" gorm:"column:archived;default:false"`
+	// DeletedAt makes session deletion soft and recoverable
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
 }
 
 // Message represents a chat message
@@ -19,6 +27,9 @@ type Message struct {
 	Timestamp        time.Time `json:"timestamp" gorm:"column:timestamp;index"`
 	ToolCalls        string    `json:"toolCalls,omitempty" gorm:"column:tool_calls;type:text"`
 	ToolResult       string    `json:"toolResult,omitempty" gorm:"column:tool_result;type:text"`
+	// Attachments holds a JSON array of attachment ids stored in the
+	// configured blob store
+	Attachments string `json:"attachments,omitempty" gorm:"column:attachments;type:text"`
 }
 
 // UserRole represents the role of a user
@@ -38,6 +49,8 @@ type User struct {
 	IsActive  bool      `json:"isActive" gorm:"not null;default:true"`
 	CreatedAt time.Time `json:"createdAt"`
 	UpdatedAt time.Time `json:"updatedAt"`
+	// DeletedAt makes user deletion soft and recoverable
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
 }
 
 // Tenant represents a tenant in the system
@@ -51,6 +64,19 @@ type Tenant struct {
 	UpdatedAt   time.Time `json:"updatedAt"`
 }
 
+// APIToken represents a long-lived token for programmatic API access.
+// Only the SHA-256 hash of the token is persisted.
+type APIToken struct {
+	ID         uint       `json:"id" gorm:"primaryKey;autoIncrement"`
+	Name       string     `json:"name" gorm:"type:varchar(100);not null"`
+	TokenHash  string     `json:"-" gorm:"type:varchar(64);uniqueIndex;not null"`
+	Scopes     string     `json:"scopes" gorm:"type:varchar(255)"` // comma-separated, e.g. "config:read,config:write"
+	Username   string     `json:"username" gorm:"type:varchar(50);index;not null"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+	RevokedAt  *time.Time `json:"revokedAt,omitempty"`
+}
+
 // UserTenant represents the relationship between a user and a tenant
 type UserTenant struct {
 	ID        uint      `json:"id" gorm:"primaryKey;autoIncrement"`
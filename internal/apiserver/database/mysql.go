@@ -23,17 +23,21 @@ func NewMySQL(cfg *config.DatabaseConfig) (Database, error) {
 		cfg: cfg,
 	}
 
-	gormDB, err := gorm.Open(mysql.Open(db.cfg.GetDSN()), &gorm.Config{})
+	gormDB, err := gorm.Open(mysql.Open(db.cfg.GetDSN()), gormConfig(cfg))
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
 
-	if err := gormDB.AutoMigrate(&Message{}, &Session{}, &User{}, &Tenant{}, &UserTenant{}); err != nil {
+	if err := RunMigrations(gormDB); err != nil {
 		return nil, fmt.Errorf("failed to migrate database: %w", err)
 	}
 
 	db.db = gormDB
 
+	if err := applyPoolSettings(gormDB, cfg); err != nil {
+		return nil, fmt.Errorf("failed to apply pool settings: %w", err)
+	}
+
 	if err := InitDefaultTenant(gormDB); err != nil {
 		return nil, fmt.Errorf("failed to initialize default tenant: %w", err)
 	}
@@ -147,7 +151,8 @@ func (db *MySQL) UpdateUser(ctx context.Context, user *User) error {
 
 // DeleteUser deletes a user by ID
 func (db *MySQL) DeleteUser(ctx context.Context, id uint) error {
-	return db.db.WithContext(ctx).Delete(&User{}, "id = ?", id).Error
+	// Honor an enclosing transaction from the context
+	return getDBFromContext(ctx, db.db).Delete(&User{}, "id = ?", id).Error
 }
 
 // ListUsers retrieves all users
@@ -274,3 +279,102 @@ func (db *MySQL) DeleteUserTenants(ctx context.Context, userID uint) error {
 
 	return dbSession.Where("user_id = ?", userID).Delete(&UserTenant{}).Error
 }
+
+// CreateAPIToken creates a new API token
+func (db *MySQL) CreateAPIToken(ctx context.Context, token *APIToken) error {
+	return db.db.WithContext(ctx).Create(token).Error
+}
+
+// GetAPITokenByHash retrieves an unrevoked API token by its hash
+func (db *MySQL) GetAPITokenByHash(ctx context.Context, hash string) (*APIToken, error) {
+	var token APIToken
+	err := db.db.WithContext(ctx).
+		Where("token_hash = ? AND revoked_at IS NULL", hash).
+		First(&token).Error
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// ListAPITokens retrieves all API tokens owned by a user
+func (db *MySQL) ListAPITokens(ctx context.Context, username string) ([]*APIToken, error) {
+	var tokens []*APIToken
+	err := db.db.WithContext(ctx).
+		Where("username = ?", username).
+		Order("created_at desc").
+		Find(&tokens).Error
+	return tokens, err
+}
+
+// RevokeAPIToken marks an API token as revoked
+func (db *MySQL) RevokeAPIToken(ctx context.Context, id uint, username string) error {
+	now := time.Now()
+	result := db.db.WithContext(ctx).
+		Model(&APIToken{}).
+		Where("id = ? AND username = ? AND revoked_at IS NULL", id, username).
+		Update("revoked_at", now)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// TouchAPIToken records when an API token was last used
+func (db *MySQL) TouchAPIToken(ctx context.Context, id uint) error {
+	return db.db.WithContext(ctx).
+		Model(&APIToken{}).
+		Where("id = ?", id).
+		Update("last_used_at", time.Now()).Error
+}
+
+// SearchMessages finds messages whose content matches the query
+func (db *MySQL) SearchMessages(ctx context.Context, query string, page, pageSize int) ([]*Message, error) {
+	var messages []*Message
+	offset := (page - 1) * pageSize
+	err := db.db.WithContext(ctx).
+		Where("content LIKE ?", "%"+query+"%").
+		Order("timestamp desc").
+		Offset(offset).
+		Limit(pageSize).
+		Find(&messages).Error
+	return messages, err
+}
+
+// SetSessionArchived archives or unarchives a session
+func (db *MySQL) SetSessionArchived(ctx context.Context, sessionID string, archived bool) error {
+	return db.db.WithContext(ctx).
+		Model(&Session{}).
+		Where("id = ?", sessionID).
+		Update("archived", archived).Error
+}
+
+// ListDeletedUsers lists soft-deleted users
+func (db *MySQL) ListDeletedUsers(ctx context.Context) ([]*User, error) {
+	var users []*User
+	err := db.db.WithContext(ctx).
+		Unscoped().
+		Where("deleted_at IS NOT NULL").
+		Order("created_at desc").
+		Find(&users).Error
+	return users, err
+}
+
+// RestoreUser clears a user's soft-delete marker
+func (db *MySQL) RestoreUser(ctx context.Context, username string) error {
+	result := db.db.WithContext(ctx).
+		Unscoped().
+		Model(&User{}).
+		Where("username = ? AND deleted_at IS NOT NULL", username).
+		Update("deleted_at", nil)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -2,10 +2,15 @@ package database
 
 import (
 	"context"
+	"log"
+	"os"
 	"strings"
 	"time"
 
+	"github.com/amoylab/unla/internal/common/config"
+
 	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
 )
 
 // InitDefaultTenant initializes the default tenant if it doesn't exist
@@ -62,3 +67,37 @@ func InitDefaultTenant(db *gorm.DB) error {
 
 	return nil
 }
+
+// applyPoolSettings tunes the sql.DB pool according to the configuration
+func applyPoolSettings(gormDB *gorm.DB, cfg *config.DatabaseConfig) error {
+	sqlDB, err := gormDB.DB()
+	if err != nil {
+		return err
+	}
+	if cfg.MaxOpenConns > 0 {
+		sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+	}
+	if cfg.MaxIdleConns > 0 {
+		sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+	}
+	if cfg.ConnMaxLifetime > 0 {
+		sqlDB.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	}
+	return nil
+}
+
+// gormConfig builds the gorm configuration, enabling slow-query logging
+// when a threshold is set
+func gormConfig(cfg *config.DatabaseConfig) *gorm.Config {
+	gormCfg := &gorm.Config{}
+	if cfg.SlowQueryThreshold > 0 {
+		gormCfg.Logger = gormlogger.New(
+			log.New(os.Stdout, "", log.LstdFlags),
+			gormlogger.Config{
+				SlowThreshold: cfg.SlowQueryThreshold,
+				LogLevel:      gormlogger.Warn,
+			},
+		)
+	}
+	return gormCfg
+}
@@ -0,0 +1,150 @@
+package handler
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/amoylab/unla/internal/common/config"
+	"github.com/amoylab/unla/internal/i18n"
+	"github.com/amoylab/unla/pkg/blob"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Attachment serves chat attachment upload and signed download
+type Attachment struct {
+	store      blob.Store
+	cfg        *config.AttachmentsConfig
+	signSecret string
+	logger     *zap.Logger
+}
+
+// NewAttachment creates the attachment handler with the configured blob
+// backend. One instance must serve both upload and download so signed
+// URLs verify against the same secret.
+func NewAttachment(cfg *config.AttachmentsConfig, logger *zap.Logger) (*Attachment, error) {
+	logger = logger.Named("apiserver.handler.attachment")
+
+	var (
+		store blob.Store
+		err   error
+	)
+	switch cfg.Type {
+	case "", "local":
+		dir := cfg.Dir
+		if dir == "" {
+			dir = "./attachments"
+		}
+		store, err = blob.NewLocalStore(dir)
+	case "s3":
+		store, err = blob.NewS3Store(blob.S3Config{
+			Region:       cfg.S3.Region,
+			Bucket:       cfg.S3.Bucket,
+			Endpoint:     cfg.S3.Endpoint,
+			Prefix:       cfg.S3.Prefix,
+			AccessKey:    cfg.S3.AccessKey,
+			SecretKey:    cfg.S3.SecretKey,
+			SessionToken: cfg.S3.SessionToken,
+			PathStyle:    cfg.S3.PathStyle,
+		})
+	default:
+		return nil, fmt.Errorf("unsupported attachments type: %s", cfg.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	signSecret := cfg.SignSecret
+	if signSecret == "" {
+		// Never fall back to a guessable constant; a random per-process
+		// secret keeps signatures unforgeable at the cost of download
+		// links not surviving a restart
+		raw := make([]byte, 32)
+		if _, err := rand.Read(raw); err != nil {
+			return nil, fmt.Errorf("failed to generate attachment sign secret: %w", err)
+		}
+		signSecret = hex.EncodeToString(raw)
+		logger.Warn("attachments.sign_secret is not set; using a random per-process secret, download URLs will not survive restarts")
+	}
+
+	return &Attachment{
+		store:      store,
+		cfg:        cfg,
+		signSecret: signSecret,
+		logger:     logger,
+	}, nil
+}
+
+// HandleUpload stores a multipart file and returns its id plus a signed
+// download URL
+func (h *Attachment) HandleUpload(c *gin.Context) {
+	file, header, err := c.Request.FormFile("file")
+	if err != nil {
+		i18n.RespondWithError(c, i18n.ErrBadRequest.WithParam("Reason", "file field is required"))
+		return
+	}
+	defer file.Close()
+
+	maxSize := int64(h.cfg.MaxSizeMB)
+	if maxSize <= 0 {
+		maxSize = 10
+	}
+	maxSize *= 1 << 20
+	if header.Size > maxSize {
+		i18n.RespondWithError(c, i18n.ErrBadRequest.WithParam("Reason",
+			fmt.Sprintf("file exceeds the %d byte limit", maxSize)))
+		return
+	}
+
+	contentType := header.Header.Get("Content-Type")
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	id := uuid.New().String()
+	if err := h.store.Put(c.Request.Context(), id, contentType, io.LimitReader(file, maxSize)); err != nil {
+		h.logger.Error("failed to store attachment", zap.Error(err))
+		i18n.RespondWithError(c, i18n.ErrInternalServer)
+		return
+	}
+
+	expires := time.Now().Add(24 * time.Hour)
+	c.JSON(http.StatusOK, gin.H{
+		"id":       id,
+		"fileName": header.Filename,
+		"size":     header.Size,
+		"url":      h.downloadURL(id, expires),
+	})
+}
+
+// downloadURL builds a signed, expiring download path
+func (h *Attachment) downloadURL(id string, expires time.Time) string {
+	return fmt.Sprintf("/api/chat/attachments/%s?expires=%d&sig=%s",
+		id, expires.Unix(), blob.SignDownload(h.signSecret, id, expires))
+}
+
+// HandleDownload serves a stored attachment after validating the signature
+func (h *Attachment) HandleDownload(c *gin.Context) {
+	id := c.Param("id")
+	if !blob.VerifyDownload(h.signSecret, id, c.Query("expires"), c.Query("sig")) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "invalid or expired signature"})
+		return
+	}
+
+	reader, contentType, err := h.store.Get(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "attachment not found"})
+		return
+	}
+	defer reader.Close()
+
+	c.Header("Content-Type", contentType)
+	c.Status(http.StatusOK)
+	_, _ = io.Copy(c.Writer, reader)
+}
@@ -6,12 +6,13 @@ import (
 
 	"context"
 
-	"github.com/gin-gonic/gin"
 	"github.com/amoylab/unla/internal/apiserver/database"
+	"github.com/amoylab/unla/internal/apiserver/webhook"
 	"github.com/amoylab/unla/internal/auth/jwt"
 	"github.com/amoylab/unla/internal/common/config"
 	"github.com/amoylab/unla/internal/common/dto"
 	"github.com/amoylab/unla/internal/i18n"
+	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 	"golang.org/x/crypto/bcrypt"
 )
@@ -350,6 +351,7 @@ func (h *Handler) CreateUser(c *gin.Context) {
 		return
 	}
 
+	webhook.Fire("user.created", gin.H{"username": req.Username})
 	i18n.Created(i18n.SuccessUserCreated).With("id", userID).Send(c)
 }
 
@@ -462,6 +464,7 @@ func (h *Handler) DeleteUser(c *gin.Context) {
 		return
 	}
 
+	webhook.Fire("user.deleted", gin.H{"username": username})
 	i18n.Success(i18n.SuccessUserDeleted).Send(c)
 }
 
@@ -627,3 +630,28 @@ func (h *Handler) UpdateUserTenants(c *gin.Context) {
 
 	i18n.Success(i18n.SuccessUserTenantsUpdated).Send(c)
 }
+
+// ListDeletedUsers lists soft-deleted users for recovery
+func (h *Handler) ListDeletedUsers(c *gin.Context) {
+	users, err := h.db.ListDeletedUsers(c.Request.Context())
+	if err != nil {
+		h.logger.Error("failed to list deleted users", zap.Error(err))
+		i18n.RespondWithError(c, i18n.ErrInternalServer)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": users})
+}
+
+// RestoreUser clears a user's soft-delete marker
+func (h *Handler) RestoreUser(c *gin.Context) {
+	username := c.Param("username")
+	if err := h.db.RestoreUser(c.Request.Context(), username); err != nil {
+		h.logger.Warn("failed to restore user",
+			zap.String("username", username),
+			zap.Error(err))
+		i18n.RespondWithError(c, i18n.ErrNotFound)
+		return
+	}
+	h.logger.Info("user restored", zap.String("username", username))
+	c.JSON(http.StatusOK, gin.H{"status": "restored"})
+}
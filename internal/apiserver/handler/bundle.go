@@ -0,0 +1,153 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"time"
+
+	"github.com/amoylab/unla/internal/common/config"
+	"github.com/amoylab/unla/internal/i18n"
+	"github.com/amoylab/unla/internal/template"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+	"gorm.io/gorm"
+)
+
+// HandleConfigExport streams every MCP configuration as one multi-document
+// YAML bundle suitable for re-import in another environment
+func (h *MCP) HandleConfigExport(c *gin.Context) {
+	configs, err := h.store.List(c.Request.Context())
+	if err != nil {
+		i18n.RespondWithError(c, i18n.ErrInternalServer.WithParam("Reason", err.Error()))
+		return
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	for _, cfg := range configs {
+		if err := encoder.Encode(cfg); err != nil {
+			i18n.RespondWithError(c, i18n.ErrInternalServer.WithParam("Reason", err.Error()))
+			return
+		}
+	}
+	_ = encoder.Close()
+
+	c.Header("Content-Disposition", "attachment; filename=mcp-configs.yaml")
+	c.Data(http.StatusOK, "application/yaml", buf.Bytes())
+}
+
+// HandleConfigImport imports a multi-document YAML bundle. With ?dry_run=true
+// it only reports what would change.
+func (h *MCP) HandleConfigImport(c *gin.Context) {
+	content, err := c.GetRawData()
+	if err != nil {
+		i18n.RespondWithError(c, i18n.ErrBadRequest.WithParam("Reason", err.Error()))
+		return
+	}
+
+	var incoming []*config.MCPConfig
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	for {
+		var cfg config.MCPConfig
+		if err := decoder.Decode(&cfg); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			i18n.RespondWithError(c, i18n.ErrorMCPServerValidation.WithParam("Reason", "Invalid YAML bundle: "+err.Error()))
+			return
+		}
+		if cfg.Name == "" {
+			continue
+		}
+		incoming = append(incoming, &cfg)
+	}
+	if len(incoming) == 0 {
+		i18n.RespondWithError(c, i18n.ErrBadRequest.WithParam("Reason", "bundle contains no configurations"))
+		return
+	}
+
+	// Validate the merged result up front
+	existing, err := h.store.List(c.Request.Context())
+	if err != nil {
+		i18n.RespondWithError(c, i18n.ErrInternalServer.WithParam("Reason", err.Error()))
+		return
+	}
+	merged := existing
+	for _, cfg := range incoming {
+		merged = config.MergeConfigs(merged, cfg)
+	}
+	if err := config.ValidateMCPConfigs(merged); err != nil {
+		i18n.RespondWithError(c, i18n.ErrorMCPServerValidation.WithParam("Reason", err.Error()))
+		return
+	}
+	if err := template.ValidateToolTemplates(merged); err != nil {
+		i18n.RespondWithError(c, i18n.ErrorMCPServerValidation.WithParam("Reason", err.Error()))
+		return
+	}
+
+	// Diff against the stored configs
+	var created, updated, unchanged []string
+	for _, cfg := range incoming {
+		current, err := h.store.Get(c.Request.Context(), cfg.Tenant, cfg.Name)
+		switch {
+		case err != nil && errors.Is(err, gorm.ErrRecordNotFound):
+			created = append(created, cfg.Tenant+"/"+cfg.Name)
+		case err != nil:
+			created = append(created, cfg.Tenant+"/"+cfg.Name)
+		case configsEqual(current, cfg):
+			unchanged = append(unchanged, cfg.Tenant+"/"+cfg.Name)
+		default:
+			updated = append(updated, cfg.Tenant+"/"+cfg.Name)
+		}
+	}
+
+	dryRun := c.Query("dry_run") == "true"
+	result := gin.H{
+		"dry_run":   dryRun,
+		"created":   created,
+		"updated":   updated,
+		"unchanged": unchanged,
+	}
+	if dryRun {
+		c.JSON(http.StatusOK, result)
+		return
+	}
+
+	applied := 0
+	for _, cfg := range incoming {
+		_, err := h.store.Get(c.Request.Context(), cfg.Tenant, cfg.Name)
+		if err != nil {
+			err = h.store.Create(c.Request.Context(), cfg)
+		} else {
+			err = h.store.Update(c.Request.Context(), cfg)
+		}
+		if err != nil {
+			h.logger.Error("failed to import configuration",
+				zap.String("name", cfg.Name),
+				zap.Error(err))
+			i18n.RespondWithError(c, i18n.ErrInternalServer.WithParam("Reason", err.Error()))
+			return
+		}
+		if err := h.notifier.NotifyUpdate(c.Request.Context(), cfg); err != nil {
+			h.logger.Warn("failed to notify gateway about imported config",
+				zap.String("name", cfg.Name),
+				zap.Error(err))
+		}
+		applied++
+	}
+	result["applied"] = applied
+	c.JSON(http.StatusOK, result)
+}
+
+// configsEqual compares configs ignoring bookkeeping timestamps
+func configsEqual(a, b *config.MCPConfig) bool {
+	ac, bc := *a, *b
+	ac.CreatedAt, bc.CreatedAt = time.Time{}, time.Time{}
+	ac.UpdatedAt, bc.UpdatedAt = time.Time{}, time.Time{}
+	return reflect.DeepEqual(ac, bc)
+}
@@ -2,6 +2,7 @@ package handler
 
 import (
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/amoylab/unla/internal/apiserver/database"
@@ -192,6 +193,7 @@ func (h *Chat) HandleSaveChatMessage(c *gin.Context) {
 		Timestamp        string `json:"timestamp" binding:"required"`
 		ToolCalls        string `json:"toolCalls,omitempty"`
 		ToolResult       string `json:"toolResult,omitempty"`
+		Attachments      string `json:"attachments,omitempty"`
 	}
 
 	if err := c.ShouldBindJSON(&request); err != nil {
@@ -288,6 +290,7 @@ func (h *Chat) HandleSaveChatMessage(c *gin.Context) {
 		Timestamp:        timestamp,
 		ToolCalls:        request.ToolCalls,
 		ToolResult:       request.ToolResult,
+		Attachments:      request.Attachments,
 	}
 
 	err = h.db.SaveMessage(c.Request.Context(), message)
@@ -309,3 +312,95 @@ func (h *Chat) HandleSaveChatMessage(c *gin.Context) {
 
 	i18n.Success(i18n.SuccessChatMessageSaved).Send(c)
 }
+
+// HandleSearchChatMessages searches message content across sessions with
+// pagination and a highlighted snippet around the first match
+func (h *Chat) HandleSearchChatMessages(c *gin.Context) {
+	query := c.Query("q")
+	if query == "" {
+		i18n.RespondWithError(c, i18n.ErrBadRequest.WithParam("Reason", "q query parameter is required"))
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if page < 1 {
+		page = 1
+	}
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "20"))
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	messages, err := h.db.SearchMessages(c.Request.Context(), query, page, pageSize)
+	if err != nil {
+		h.logger.Error("message search failed", zap.Error(err))
+		i18n.RespondWithError(c, i18n.ErrInternalServer)
+		return
+	}
+
+	results := make([]gin.H, 0, len(messages))
+	for _, msg := range messages {
+		results = append(results, gin.H{
+			"id":         msg.ID,
+			"session_id": msg.SessionID,
+			"sender":     msg.Sender,
+			"timestamp":  msg.Timestamp,
+			"snippet":    searchSnippet(msg.Content, query),
+		})
+	}
+	c.JSON(200, gin.H{"query": query, "page": page, "results": results})
+}
+
+// searchSnippet extracts a window around the first case-insensitive match
+// and marks it for UI highlighting
+func searchSnippet(content, query string) string {
+	lower := strings.ToLower(content)
+	idx := strings.Index(lower, strings.ToLower(query))
+	if idx < 0 {
+		if len(content) > 160 {
+			return content[:160] + "..."
+		}
+		return content
+	}
+
+	start := idx - 60
+	if start < 0 {
+		start = 0
+	}
+	end := idx + len(query) + 60
+	if end > len(content) {
+		end = len(content)
+	}
+	snippet := content[start:idx] + "<mark>" + content[idx:idx+len(query)] + "</mark>" + content[idx+len(query):end]
+	if start > 0 {
+		snippet = "..." + snippet
+	}
+	if end < len(content) {
+		snippet += "..."
+	}
+	return snippet
+}
+
+// HandleArchiveChatSession archives or unarchives a session
+func (h *Chat) HandleArchiveChatSession(c *gin.Context) {
+	sessionID := c.Param("sessionId")
+	var req struct {
+		Archived bool `json:"archived"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		i18n.RespondWithError(c, i18n.ErrBadRequest.WithParam("Reason", err.Error()))
+		return
+	}
+
+	exists, err := h.db.SessionExists(c.Request.Context(), sessionID)
+	if err != nil || !exists {
+		i18n.RespondWithError(c, i18n.ErrNotFound)
+		return
+	}
+	if err := h.db.SetSessionArchived(c.Request.Context(), sessionID, req.Archived); err != nil {
+		h.logger.Error("failed to update session archive state", zap.Error(err))
+		i18n.RespondWithError(c, i18n.ErrInternalServer)
+		return
+	}
+	c.JSON(200, gin.H{"archived": req.Archived})
+}
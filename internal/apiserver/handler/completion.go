@@ -0,0 +1,187 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/amoylab/unla/internal/apiserver/database"
+	"github.com/amoylab/unla/internal/common/cnst"
+	"github.com/amoylab/unla/internal/common/config"
+	"github.com/amoylab/unla/internal/core/mcpproxy"
+	"github.com/amoylab/unla/internal/i18n"
+	"github.com/amoylab/unla/pkg/llm"
+	"github.com/amoylab/unla/pkg/mcp"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// maxToolRounds bounds the automatic tool-calling loop
+const maxToolRounds = 5
+
+// Completion runs provider-backed chat with an automatic tool-calling loop
+// against a gateway prefix's MCP tools
+type Completion struct {
+	provider llm.Provider
+	db       database.Database
+	resolver *GatewayResolver
+	logger   *zap.Logger
+}
+
+// NewCompletion creates a new chat completion handler
+func NewCompletion(cfg *config.OpenAIConfig, db database.Database, resolver *GatewayResolver, logger *zap.Logger) (*Completion, error) {
+	provider, err := llm.NewProvider(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &Completion{
+		provider: provider,
+		db:       db,
+		resolver: resolver,
+		logger:   logger.Named("apiserver.handler.completion"),
+	}, nil
+}
+
+// HandleGenerateTitle asks the configured model for a short session title
+// based on the first messages and stores it
+func (h *Completion) HandleGenerateTitle(c *gin.Context) {
+	sessionID := c.Param("sessionId")
+	messages, err := h.db.GetMessagesWithPagination(c.Request.Context(), sessionID, 1, 4)
+	if err != nil || len(messages) == 0 {
+		i18n.RespondWithError(c, i18n.ErrNotFound)
+		return
+	}
+
+	var transcript string
+	for _, msg := range messages {
+		transcript += msg.Sender + ": " + msg.Content + "\n"
+	}
+
+	completion, err := h.provider.Complete(c.Request.Context(), []llm.Message{
+		{Role: "system", Content: "Reply with a concise title (max 6 words) for the conversation. No quotes."},
+		{Role: "user", Content: transcript},
+	}, nil)
+	if err != nil {
+		h.logger.Error("title generation failed", zap.Error(err))
+		i18n.RespondWithError(c, i18n.ErrInternalServer)
+		return
+	}
+
+	title := strings.TrimSpace(completion.Content)
+	if len(title) > 120 {
+		title = title[:120]
+	}
+	if err := h.db.UpdateSessionTitle(c.Request.Context(), sessionID, title); err != nil {
+		i18n.RespondWithError(c, i18n.ErrInternalServer)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"title": title})
+}
+
+// HandleChatComplete runs the conversation until the model stops requesting
+// tools, streaming progress as SSE events: tool_call, tool_result, content
+// and done
+func (h *Completion) HandleChatComplete(c *gin.Context) {
+	var req struct {
+		// GatewayPrefix names the configured gateway prefix whose tools
+		// the model may call; empty disables tool calling. The endpoint
+		// URL is resolved server-side, never taken from the request.
+		GatewayPrefix string        `json:"gatewayPrefix"`
+		Messages      []llm.Message `json:"messages" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		i18n.RespondWithError(c, i18n.ErrBadRequest.WithParam("Reason", err.Error()))
+		return
+	}
+
+	var (
+		tools     []mcp.ToolSchema
+		transport mcpproxy.Transport
+	)
+	if req.GatewayPrefix != "" {
+		url, err := h.resolver.Resolve(c.Request.Context(), req.GatewayPrefix)
+		if err != nil {
+			i18n.RespondWithError(c, i18n.ErrBadRequest.WithParam("Reason", err.Error()))
+			return
+		}
+		transport, err = mcpproxy.NewTransport(zap.NewNop(), config.MCPServerConfig{
+			Type:   string(mcpproxy.TypeStreamable),
+			Name:   "chat",
+			URL:    url,
+			Policy: cnst.PolicyOnDemand,
+		})
+		if err != nil {
+			i18n.RespondWithError(c, i18n.ErrBadRequest.WithParam("Reason", err.Error()))
+			return
+		}
+		defer transport.Stop(c.Request.Context())
+
+		tools, err = transport.FetchTools(c.Request.Context())
+		if err != nil {
+			c.JSON(http.StatusBadGateway, gin.H{"error": fmt.Sprintf("failed to fetch gateway tools: %v", err)})
+			return
+		}
+	}
+
+	c.Writer.Header().Set("Content-Type", "text/event-stream")
+	c.Writer.Header().Set("Cache-Control", "no-cache")
+	emit := func(event string, payload any) {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return
+		}
+		fmt.Fprintf(c.Writer, "event: %s\ndata: %s\n\n", event, data)
+		c.Writer.Flush()
+	}
+
+	messages := req.Messages
+	for round := 0; round < maxToolRounds; round++ {
+		completion, err := h.provider.Complete(c.Request.Context(), messages, tools)
+		if err != nil {
+			h.logger.Error("chat completion failed", zap.Error(err))
+			emit("error", gin.H{"error": err.Error()})
+			return
+		}
+
+		if len(completion.ToolCalls) == 0 {
+			emit("content", gin.H{"text": completion.Content})
+			emit("done", gin.H{})
+			return
+		}
+
+		messages = append(messages, llm.Message{
+			Role:      "assistant",
+			Content:   completion.Content,
+			ToolCalls: completion.ToolCalls,
+		})
+		for _, call := range completion.ToolCalls {
+			emit("tool_call", call)
+
+			resultText := ""
+			result, err := transport.CallTool(c.Request.Context(), mcp.CallToolParams{
+				Name:      call.Name,
+				Arguments: json.RawMessage(call.Arguments),
+			}, nil)
+			if err != nil {
+				resultText = "tool call failed: " + err.Error()
+			} else {
+				for _, content := range result.Content {
+					if text, ok := content.(*mcp.TextContent); ok {
+						resultText += text.Text
+					}
+				}
+			}
+
+			emit("tool_result", gin.H{"id": call.ID, "name": call.Name, "result": resultText})
+			messages = append(messages, llm.Message{
+				Role:       "tool",
+				Content:    resultText,
+				ToolCallID: call.ID,
+			})
+		}
+	}
+
+	emit("error", gin.H{"error": "tool call limit reached"})
+}
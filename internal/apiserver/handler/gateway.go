@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/amoylab/unla/internal/mcp/storage"
+)
+
+// GatewayResolver maps a configured router prefix to the gateway's
+// streamable MCP endpoint. The playground and chat handlers resolve
+// targets through it instead of accepting raw URLs from the browser, so
+// the apiserver can only be pointed at prefixes that actually exist on
+// the gateway.
+type GatewayResolver struct {
+	baseURL string
+	store   storage.Store
+}
+
+// NewGatewayResolver creates a resolver against the given gateway base
+// URL; empty defaults to the local gateway
+func NewGatewayResolver(baseURL string, store storage.Store) *GatewayResolver {
+	if baseURL == "" {
+		baseURL = "http://localhost:5235"
+	}
+	return &GatewayResolver{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		store:   store,
+	}
+}
+
+// Resolve returns the streamable endpoint URL for a configured prefix,
+// or an error when no router uses that prefix
+func (r *GatewayResolver) Resolve(ctx context.Context, prefix string) (string, error) {
+	if prefix == "" || !strings.HasPrefix(prefix, "/") {
+		return "", fmt.Errorf("invalid gateway prefix %q", prefix)
+	}
+
+	cfgs, err := r.store.List(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to list gateway configs: %w", err)
+	}
+	for _, cfg := range cfgs {
+		for _, router := range cfg.Routers {
+			if router.Prefix == prefix {
+				return r.baseURL + prefix + "/mcp", nil
+			}
+		}
+	}
+	return "", fmt.Errorf("no gateway router configured for prefix %q", prefix)
+}
@@ -8,14 +8,16 @@ import (
 	"strings"
 	"time"
 
-	"github.com/gin-gonic/gin"
 	"github.com/amoylab/unla/internal/apiserver/database"
+	"github.com/amoylab/unla/internal/apiserver/webhook"
 	"github.com/amoylab/unla/internal/auth/jwt"
 	"github.com/amoylab/unla/internal/common/config"
 	"github.com/amoylab/unla/internal/common/dto"
 	"github.com/amoylab/unla/internal/i18n"
 	"github.com/amoylab/unla/internal/mcp/storage"
 	"github.com/amoylab/unla/internal/mcp/storage/notifier"
+	"github.com/amoylab/unla/internal/template"
+	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 	"gopkg.in/yaml.v3"
 )
@@ -163,6 +165,22 @@ func (h *MCP) HandleMCPServerUpdate(c *gin.Context) {
 		return
 	}
 
+	// Optimistic concurrency: when the client sends If-Match, it must hold
+	// the UpdatedAt of the revision it is editing
+	if ifMatch := c.GetHeader("If-Match"); ifMatch != "" {
+		if ifMatch != oldCfg.UpdatedAt.UTC().Format(time.RFC3339Nano) {
+			h.logger.Warn("optimistic concurrency conflict",
+				zap.String("server_name", cfg.Name),
+				zap.String("if_match", ifMatch))
+			c.JSON(http.StatusPreconditionFailed, gin.H{
+				"error":   "precondition_failed",
+				"message": "configuration was modified by someone else; reload and retry",
+				"current": oldCfg.UpdatedAt.UTC().Format(time.RFC3339Nano),
+			})
+			return
+		}
+	}
+
 	if oldCfg.Name != cfg.Name {
 		h.logger.Warn("server name mismatch",
 			zap.String("config_name", cfg.Name))
@@ -204,6 +222,12 @@ func (h *MCP) HandleMCPServerUpdate(c *gin.Context) {
 		return
 	}
 
+	if err := template.ValidateToolTemplates(configs); err != nil {
+		h.logger.Error("template validation failed", zap.Error(err))
+		i18n.RespondWithError(c, i18n.ErrorMCPServerValidation.WithParam("Reason", err.Error()))
+		return
+	}
+
 	if err := h.store.Update(c.Request.Context(), &cfg); err != nil {
 		h.logger.Error("failed to update MCP server",
 			zap.String("server_name", cfg.Name),
@@ -219,6 +243,7 @@ func (h *MCP) HandleMCPServerUpdate(c *gin.Context) {
 		return
 	}
 
+	webhook.Fire("config.updated", gin.H{"tenant": cfg.Tenant, "name": cfg.Name})
 	h.logger.Info("MCP server updated successfully",
 		zap.String("server_name", cfg.Name))
 	i18n.Success(i18n.SuccessMCPServerUpdated).With("status", "success").Send(c)
@@ -439,6 +464,12 @@ func (h *MCP) HandleMCPServerCreate(c *gin.Context) {
 		return
 	}
 
+	if err := template.ValidateToolTemplates(configs); err != nil {
+		h.logger.Error("template validation failed", zap.Error(err))
+		i18n.RespondWithError(c, i18n.ErrorMCPServerValidation.WithParam("Reason", err.Error()))
+		return
+	}
+
 	if err := h.store.Create(c.Request.Context(), &cfg); err != nil {
 		h.logger.Error("failed to create MCP server",
 			zap.String("server_name", cfg.Name),
@@ -454,6 +485,7 @@ func (h *MCP) HandleMCPServerCreate(c *gin.Context) {
 		return
 	}
 
+	webhook.Fire("config.created", gin.H{"tenant": cfg.Tenant, "name": cfg.Name})
 	h.logger.Info("MCP server created successfully",
 		zap.String("server_name", cfg.Name))
 	i18n.Created(i18n.SuccessMCPServerCreated).With("status", "success").Send(c)
@@ -514,6 +546,7 @@ func (h *MCP) HandleMCPServerDelete(c *gin.Context) {
 		return
 	}
 
+	webhook.Fire("config.deleted", gin.H{"tenant": c.Param("tenant"), "name": c.Param("name")})
 	h.logger.Info("MCP server deleted successfully",
 		zap.String("server_name", name))
 	i18n.Success(i18n.SuccessMCPServerDeleted).With("status", "success").Send(c)
@@ -825,3 +858,77 @@ func (h *MCP) HandleGetConfigNames(c *gin.Context) {
 		"data": names,
 	})
 }
+
+// HandleMCPConfigPreview validates a candidate configuration against the
+// stored set and returns the merged result without persisting anything
+func (h *MCP) HandleMCPConfigPreview(c *gin.Context) {
+	content, err := c.GetRawData()
+	if err != nil {
+		i18n.RespondWithError(c, i18n.ErrBadRequest.WithParam("Reason", "Failed to read request body: "+err.Error()))
+		return
+	}
+
+	var cfg config.MCPConfig
+	if err := yaml.Unmarshal(content, &cfg); err != nil {
+		i18n.RespondWithError(c, i18n.ErrorMCPServerValidation.WithParam("Reason", "Invalid YAML content: "+err.Error()))
+		return
+	}
+
+	configs, err := h.store.List(c.Request.Context())
+	if err != nil {
+		i18n.RespondWithError(c, i18n.ErrInternalServer.WithParam("Reason", err.Error()))
+		return
+	}
+	merged := config.MergeConfigs(configs, &cfg)
+
+	var validationMessage string
+	valid := true
+	if err := config.ValidateMCPConfigs(merged); err != nil {
+		valid = false
+		validationMessage = err.Error()
+	} else if err := template.ValidateToolTemplates(merged); err != nil {
+		valid = false
+		validationMessage = err.Error()
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"valid":  valid,
+		"error":  validationMessage,
+		"merged": merged,
+	})
+}
+
+// HandleMCPServerRestore clears the soft-delete marker of a configuration
+func (h *MCP) HandleMCPServerRestore(c *gin.Context) {
+	tenant := c.Param("tenant")
+	name := c.Param("name")
+
+	cfg, err := h.store.Get(c.Request.Context(), tenant, name, true)
+	if err != nil || cfg == nil {
+		i18n.RespondWithError(c, i18n.ErrorMCPServerNotFound.WithParam("Name", name))
+		return
+	}
+	if cfg.DeletedAt.IsZero() {
+		i18n.RespondWithError(c, i18n.ErrBadRequest.WithParam("Reason", "configuration is not deleted"))
+		return
+	}
+
+	if _, err := h.checkTenantPermission(c, cfg.Tenant, cfg); err != nil {
+		i18n.RespondWithError(c, err)
+		return
+	}
+
+	cfg.DeletedAt = time.Time{}
+	if err := h.store.Update(c.Request.Context(), cfg); err != nil {
+		h.logger.Error("failed to restore configuration",
+			zap.String("name", name),
+			zap.Error(err))
+		i18n.RespondWithError(c, i18n.ErrInternalServer.WithParam("Reason", err.Error()))
+		return
+	}
+
+	if err := h.notifier.NotifyUpdate(c.Request.Context(), cfg); err != nil {
+		h.logger.Warn("failed to notify gateway about restored config", zap.Error(err))
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "restored"})
+}
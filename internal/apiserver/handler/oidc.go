@@ -0,0 +1,323 @@
+package handler
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/amoylab/unla/internal/apiserver/database"
+	"github.com/amoylab/unla/internal/auth/jwt"
+	"github.com/amoylab/unla/internal/common/config"
+	"github.com/amoylab/unla/internal/i18n"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// OIDC handles SSO login through an external OIDC provider
+type OIDC struct {
+	db         database.Database
+	jwtService *jwt.Service
+	cfg        *config.OIDCLoginConfig
+	logger     *zap.Logger
+
+	mu        sync.Mutex
+	states    map[string]time.Time
+	discovery *oidcDiscovery
+	validator *jwt.Validator
+}
+
+// oidcDiscovery holds the provider endpoints from the discovery document
+type oidcDiscovery struct {
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	UserinfoEndpoint      string `json:"userinfo_endpoint"`
+	JwksURI               string `json:"jwks_uri"`
+}
+
+// NewOIDC creates a new OIDC login handler
+func NewOIDC(db database.Database, jwtService *jwt.Service, cfg *config.OIDCLoginConfig, logger *zap.Logger) *OIDC {
+	return &OIDC{
+		db:         db,
+		jwtService: jwtService,
+		cfg:        cfg,
+		logger:     logger.Named("apiserver.handler.oidc"),
+		states:     make(map[string]time.Time),
+	}
+}
+
+var oidcHTTPClient = &http.Client{Timeout: 10 * time.Second}
+
+// discover fetches and caches the provider's discovery document
+func (h *OIDC) discover() (*oidcDiscovery, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.discovery != nil {
+		return h.discovery, nil
+	}
+
+	endpoint := strings.TrimSuffix(h.cfg.Issuer, "/") + "/.well-known/openid-configuration"
+	resp, err := oidcHTTPClient.Get(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch OIDC discovery document: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("OIDC discovery failed: status %d", resp.StatusCode)
+	}
+
+	var doc oidcDiscovery
+	if err := json.NewDecoder(resp.Body).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to decode OIDC discovery document: %w", err)
+	}
+	h.discovery = &doc
+	// The id_token must verify against the provider's JWKS (or the client
+	// secret for HS256), carry our client_id as audience, the configured
+	// issuer, and an unexpired exp
+	h.validator = jwt.NewValidator(jwt.ValidationConfig{
+		JWKSURL:  doc.JwksURI,
+		Secret:   h.cfg.ClientSecret,
+		Issuer:   h.cfg.Issuer,
+		Audience: h.cfg.ClientID,
+	})
+	return &doc, nil
+}
+
+// newState issues a short-lived anti-CSRF state value
+func (h *OIDC) newState() (string, error) {
+	raw := make([]byte, 16)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	state := hex.EncodeToString(raw)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	now := time.Now()
+	for s, issued := range h.states {
+		if now.Sub(issued) > 10*time.Minute {
+			delete(h.states, s)
+		}
+	}
+	h.states[state] = now
+	return state, nil
+}
+
+// consumeState validates and invalidates a state value
+func (h *OIDC) consumeState(state string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	issued, ok := h.states[state]
+	if !ok {
+		return false
+	}
+	delete(h.states, state)
+	return time.Since(issued) <= 10*time.Minute
+}
+
+// HandleLogin redirects the browser to the provider's authorization endpoint
+func (h *OIDC) HandleLogin(c *gin.Context) {
+	discovery, err := h.discover()
+	if err != nil {
+		h.logger.Error("OIDC discovery failed", zap.Error(err))
+		i18n.RespondWithError(c, i18n.ErrInternalServer)
+		return
+	}
+
+	state, err := h.newState()
+	if err != nil {
+		i18n.RespondWithError(c, i18n.ErrInternalServer)
+		return
+	}
+
+	scopes := h.cfg.Scopes
+	if len(scopes) == 0 {
+		scopes = []string{"openid", "profile", "email"}
+	}
+
+	query := url.Values{
+		"response_type": {"code"},
+		"client_id":     {h.cfg.ClientID},
+		"redirect_uri":  {h.cfg.RedirectURI},
+		"scope":         {strings.Join(scopes, " ")},
+		"state":         {state},
+	}
+	c.Redirect(http.StatusFound, discovery.AuthorizationEndpoint+"?"+query.Encode())
+}
+
+// HandleCallback exchanges the authorization code, provisions the user if
+// needed, and issues a local JWT
+func (h *OIDC) HandleCallback(c *gin.Context) {
+	if errParam := c.Query("error"); errParam != "" {
+		i18n.RespondWithError(c, i18n.ErrUnauthorized)
+		return
+	}
+	if !h.consumeState(c.Query("state")) {
+		i18n.RespondWithError(c, i18n.ErrBadRequest.WithParam("Reason", "invalid state"))
+		return
+	}
+	code := c.Query("code")
+	if code == "" {
+		i18n.RespondWithError(c, i18n.ErrBadRequest.WithParam("Reason", "missing code"))
+		return
+	}
+
+	claims, err := h.exchangeCode(code)
+	if err != nil {
+		h.logger.Error("OIDC code exchange failed", zap.Error(err))
+		i18n.RespondWithError(c, i18n.ErrUnauthorized)
+		return
+	}
+
+	user, err := h.provisionUser(c, claims)
+	if err != nil {
+		h.logger.Error("failed to provision OIDC user", zap.Error(err))
+		i18n.RespondWithError(c, i18n.ErrInternalServer)
+		return
+	}
+
+	token, err := h.jwtService.GenerateToken(user.ID, user.Username, string(user.Role))
+	if err != nil {
+		i18n.RespondWithError(c, i18n.ErrInternalServer)
+		return
+	}
+
+	h.logger.Info("OIDC login succeeded",
+		zap.String("username", user.Username),
+		zap.String("role", string(user.Role)))
+
+	i18n.Success(i18n.SuccessLogin).
+		With("token", token).
+		With("user", gin.H{
+			"id":       user.ID,
+			"username": user.Username,
+			"role":     string(user.Role),
+		}).
+		Send(c)
+}
+
+// exchangeCode trades the authorization code for tokens and returns the
+// id_token claims
+func (h *OIDC) exchangeCode(code string) (map[string]any, error) {
+	discovery, err := h.discover()
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {h.cfg.RedirectURI},
+		"client_id":     {h.cfg.ClientID},
+		"client_secret": {h.cfg.ClientSecret},
+	}
+	resp, err := oidcHTTPClient.PostForm(discovery.TokenEndpoint, form)
+	if err != nil {
+		return nil, fmt.Errorf("token request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("token request failed: status %d", resp.StatusCode)
+	}
+
+	var tokenResp struct {
+		IDToken     string `json:"id_token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tokenResp); err != nil {
+		return nil, fmt.Errorf("failed to decode token response: %w", err)
+	}
+	if tokenResp.IDToken == "" {
+		return nil, errors.New("token response carries no id_token")
+	}
+
+	h.mu.Lock()
+	validator := h.validator
+	h.mu.Unlock()
+	claims, err := validator.Validate(tokenResp.IDToken)
+	if err != nil {
+		return nil, fmt.Errorf("id_token validation failed: %w", err)
+	}
+	return claims, nil
+}
+
+// provisionUser finds or creates the local user for the OIDC identity and
+// maps provider groups to the local role
+func (h *OIDC) provisionUser(c *gin.Context, claims map[string]any) (*database.User, error) {
+	usernameClaim := h.cfg.UsernameClaim
+	if usernameClaim == "" {
+		usernameClaim = "preferred_username"
+	}
+	username, _ := claims[usernameClaim].(string)
+	if username == "" {
+		username, _ = claims["email"].(string)
+	}
+	if username == "" {
+		return nil, errors.New("id_token carries no usable username claim")
+	}
+
+	role := database.RoleNormal
+	groupsClaim := h.cfg.GroupsClaim
+	if groupsClaim == "" {
+		groupsClaim = "groups"
+	}
+	if rawGroups, ok := claims[groupsClaim].([]any); ok {
+		for _, rawGroup := range rawGroups {
+			group, _ := rawGroup.(string)
+			for _, adminGroup := range h.cfg.AdminGroups {
+				if group == adminGroup {
+					role = database.RoleAdmin
+				}
+			}
+		}
+	}
+
+	user, err := h.db.GetUserByUsername(c.Request.Context(), username)
+	if err == nil {
+		if user.Role != role {
+			user.Role = role
+			if err := h.db.UpdateUser(c.Request.Context(), user); err != nil {
+				return nil, err
+			}
+		}
+		return user, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	// First login: provision the user with an unusable random password
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, err
+	}
+	hashed, err := bcrypt.GenerateFromPassword(raw, bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+
+	user = &database.User{
+		Username:  username,
+		Password:  string(hashed),
+		Role:      role,
+		IsActive:  true,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	if err := h.db.CreateUser(c.Request.Context(), user); err != nil {
+		return nil, err
+	}
+	h.logger.Info("provisioned new user from OIDC login",
+		zap.String("username", username),
+		zap.String("role", string(role)))
+	return user, nil
+}
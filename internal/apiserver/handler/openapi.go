@@ -1,13 +1,13 @@
 package handler
 
 import (
-        "github.com/amoylab/unla/internal/common/config"
-	"github.com/gin-gonic/gin"
 	"github.com/amoylab/unla/internal/apiserver/database"
+	"github.com/amoylab/unla/internal/common/config"
 	"github.com/amoylab/unla/internal/i18n"
 	"github.com/amoylab/unla/internal/mcp/storage"
 	"github.com/amoylab/unla/internal/mcp/storage/notifier"
 	"github.com/amoylab/unla/pkg/openapi"
+	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 )
 
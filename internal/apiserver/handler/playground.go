@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/amoylab/unla/internal/common/cnst"
+	"github.com/amoylab/unla/internal/common/config"
+	"github.com/amoylab/unla/internal/core/mcpproxy"
+	"github.com/amoylab/unla/internal/i18n"
+	"github.com/amoylab/unla/pkg/mcp"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// Playground lets config authors exercise a gateway prefix as an MCP
+// client from the web UI
+type Playground struct {
+	resolver *GatewayResolver
+	logger   *zap.Logger
+}
+
+// NewPlayground creates a new playground handler
+func NewPlayground(resolver *GatewayResolver, logger *zap.Logger) *Playground {
+	return &Playground{
+		resolver: resolver,
+		logger:   logger.Named("apiserver.handler.playground"),
+	}
+}
+
+// playgroundTransport builds a one-shot client for the target endpoint
+func playgroundTransport(url string) (mcpproxy.Transport, error) {
+	return mcpproxy.NewTransport(zap.NewNop(), config.MCPServerConfig{
+		Type:   string(mcpproxy.TypeStreamable),
+		Name:   "playground",
+		URL:    url,
+		Policy: cnst.PolicyOnDemand,
+	})
+}
+
+// HandleListTools connects to a configured gateway prefix and lists its
+// tools; the endpoint URL is resolved server-side, never taken from the
+// request
+func (h *Playground) HandleListTools(c *gin.Context) {
+	var req struct {
+		Prefix string `json:"prefix" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		i18n.RespondWithError(c, i18n.ErrBadRequest.WithParam("Reason", err.Error()))
+		return
+	}
+
+	url, err := h.resolver.Resolve(c.Request.Context(), req.Prefix)
+	if err != nil {
+		i18n.RespondWithError(c, i18n.ErrBadRequest.WithParam("Reason", err.Error()))
+		return
+	}
+
+	transport, err := playgroundTransport(url)
+	if err != nil {
+		i18n.RespondWithError(c, i18n.ErrBadRequest.WithParam("Reason", err.Error()))
+		return
+	}
+	defer transport.Stop(c.Request.Context())
+
+	tools, err := transport.FetchTools(c.Request.Context())
+	if err != nil {
+		h.logger.Warn("playground tools/list failed",
+			zap.String("prefix", req.Prefix),
+			zap.Error(err))
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"tools": tools})
+}
+
+// HandleCallTool executes one tool against the given endpoint and returns
+// the raw result
+func (h *Playground) HandleCallTool(c *gin.Context) {
+	var req struct {
+		Prefix    string          `json:"prefix" binding:"required"`
+		Name      string          `json:"name" binding:"required"`
+		Arguments json.RawMessage `json:"arguments"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		i18n.RespondWithError(c, i18n.ErrBadRequest.WithParam("Reason", err.Error()))
+		return
+	}
+	if len(req.Arguments) == 0 {
+		req.Arguments = json.RawMessage("{}")
+	}
+
+	url, err := h.resolver.Resolve(c.Request.Context(), req.Prefix)
+	if err != nil {
+		i18n.RespondWithError(c, i18n.ErrBadRequest.WithParam("Reason", err.Error()))
+		return
+	}
+
+	transport, err := playgroundTransport(url)
+	if err != nil {
+		i18n.RespondWithError(c, i18n.ErrBadRequest.WithParam("Reason", err.Error()))
+		return
+	}
+	defer transport.Stop(c.Request.Context())
+
+	result, err := transport.CallTool(c.Request.Context(), mcp.CallToolParams{
+		Name:      req.Name,
+		Arguments: req.Arguments,
+	}, nil)
+	if err != nil {
+		h.logger.Warn("playground tools/call failed",
+			zap.String("prefix", req.Prefix),
+			zap.String("tool", req.Name),
+			zap.Error(err))
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"result": result})
+}
@@ -5,8 +5,8 @@ import (
 	"os"
 	"strconv"
 
-	"github.com/gin-gonic/gin"
 	"github.com/amoylab/unla/pkg/version"
+	"github.com/gin-gonic/gin"
 )
 
 // HandleRuntimeConfig serves frontend runtime config as JSON
@@ -35,19 +35,18 @@ func HandleRuntimeConfig(c *gin.Context) {
 		"VITE_WS_BASE_URL":          getEnvOrDefault("VITE_WS_BASE_URL", "/api/ws"),
 		"VITE_MCP_GATEWAY_BASE_URL": getEnvOrDefault("VITE_MCP_GATEWAY_BASE_URL", "/mcp"),
 		"VITE_BASE_URL":             getEnvOrDefault("VITE_BASE_URL", "/"),
-		
+
 		// Add new properties matching our TypeScript interface
-		"apiBaseUrl":                getEnvOrDefault("VITE_API_BASE_URL", "/api"),
-		"debugMode":                 debugMode,
-		"version":                   versionStr,
+		"apiBaseUrl": getEnvOrDefault("VITE_API_BASE_URL", "/api"),
+		"debugMode":  debugMode,
+		"version":    versionStr,
 		"features": gin.H{
 			"enableExperimental": enableExperimental,
 		},
-		"LLM_CONFIG_ADMIN_ONLY":     getEnvOrDefault("LLM_CONFIG_ADMIN_ONLY", "N"),
+		"LLM_CONFIG_ADMIN_ONLY": getEnvOrDefault("LLM_CONFIG_ADMIN_ONLY", "N"),
 	})
 }
 
-
 // getEnvOrDefault returns the value of the environment variable or a default if not set
 func getEnvOrDefault(key, defaultVal string) string {
 	if val := os.Getenv(key); val != "" {
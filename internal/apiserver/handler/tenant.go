@@ -5,10 +5,10 @@ import (
 
 	"github.com/amoylab/unla/internal/i18n"
 
-	"github.com/gin-gonic/gin"
 	"github.com/amoylab/unla/internal/apiserver/database"
 	"github.com/amoylab/unla/internal/auth/jwt"
 	"github.com/amoylab/unla/internal/common/dto"
+	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 )
 
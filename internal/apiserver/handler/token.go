@@ -0,0 +1,145 @@
+package handler
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/amoylab/unla/internal/apiserver/database"
+	"github.com/amoylab/unla/internal/auth/jwt"
+	"github.com/amoylab/unla/internal/i18n"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// APITokenPrefix distinguishes API tokens from JWT bearer tokens
+const APITokenPrefix = "unla_"
+
+// Token handles API token management
+type Token struct {
+	db     database.Database
+	logger *zap.Logger
+}
+
+// NewToken creates a new token handler
+func NewToken(db database.Database, logger *zap.Logger) *Token {
+	return &Token{
+		db:     db,
+		logger: logger.Named("apiserver.handler.token"),
+	}
+}
+
+// HashAPIToken returns the stored hash for a raw API token
+func HashAPIToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// HandleCreateToken creates a new API token. The raw token is returned
+// exactly once and only its hash is persisted.
+func (h *Token) HandleCreateToken(c *gin.Context) {
+	claims, exists := c.Get("claims")
+	if !exists {
+		i18n.RespondWithError(c, i18n.ErrUnauthorized)
+		return
+	}
+	jwtClaims := claims.(*jwt.Claims)
+
+	var req struct {
+		Name   string   `json:"name" binding:"required"`
+		Scopes []string `json:"scopes"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		i18n.Error(i18n.ErrBadRequest.WithParam("Reason", err.Error())).Send(c)
+		return
+	}
+
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		i18n.RespondWithError(c, i18n.ErrInternalServer)
+		return
+	}
+	token := APITokenPrefix + hex.EncodeToString(raw)
+
+	record := &database.APIToken{
+		Name:      req.Name,
+		TokenHash: HashAPIToken(token),
+		Scopes:    strings.Join(req.Scopes, ","),
+		Username:  jwtClaims.Username,
+		CreatedAt: time.Now(),
+	}
+	if err := h.db.CreateAPIToken(c.Request.Context(), record); err != nil {
+		h.logger.Error("failed to create API token",
+			zap.String("username", jwtClaims.Username),
+			zap.Error(err))
+		i18n.RespondWithError(c, i18n.ErrInternalServer)
+		return
+	}
+
+	h.logger.Info("API token created",
+		zap.String("username", jwtClaims.Username),
+		zap.String("name", req.Name),
+		zap.Uint("id", record.ID))
+
+	c.JSON(200, gin.H{
+		"id":     record.ID,
+		"name":   record.Name,
+		"scopes": req.Scopes,
+		// Returned once; it cannot be recovered later
+		"token": token,
+	})
+}
+
+// HandleListTokens lists the caller's API tokens (hashes excluded)
+func (h *Token) HandleListTokens(c *gin.Context) {
+	claims, exists := c.Get("claims")
+	if !exists {
+		i18n.RespondWithError(c, i18n.ErrUnauthorized)
+		return
+	}
+	jwtClaims := claims.(*jwt.Claims)
+
+	tokens, err := h.db.ListAPITokens(c.Request.Context(), jwtClaims.Username)
+	if err != nil {
+		h.logger.Error("failed to list API tokens",
+			zap.String("username", jwtClaims.Username),
+			zap.Error(err))
+		i18n.RespondWithError(c, i18n.ErrInternalServer)
+		return
+	}
+	c.JSON(200, gin.H{"data": tokens})
+}
+
+// HandleRevokeToken revokes one of the caller's API tokens
+func (h *Token) HandleRevokeToken(c *gin.Context) {
+	claims, exists := c.Get("claims")
+	if !exists {
+		i18n.RespondWithError(c, i18n.ErrUnauthorized)
+		return
+	}
+	jwtClaims := claims.(*jwt.Claims)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		i18n.Error(i18n.ErrBadRequest.WithParam("Reason", "invalid token id")).Send(c)
+		return
+	}
+
+	if err := h.db.RevokeAPIToken(c.Request.Context(), uint(id), jwtClaims.Username); err != nil {
+		h.logger.Error("failed to revoke API token",
+			zap.String("username", jwtClaims.Username),
+			zap.Uint64("id", id),
+			zap.Error(err))
+		i18n.RespondWithError(c, i18n.ErrNotFound)
+		return
+	}
+
+	h.logger.Info("API token revoked",
+		zap.String("username", jwtClaims.Username),
+		zap.Uint64("id", id))
+	c.JSON(200, gin.H{"status": "revoked"})
+}
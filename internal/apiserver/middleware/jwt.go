@@ -1,13 +1,95 @@
 package middleware
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"net/http"
 	"strings"
 
-	"github.com/gin-gonic/gin"
+	"github.com/amoylab/unla/internal/apiserver/database"
 	"github.com/amoylab/unla/internal/auth/jwt"
+	"github.com/gin-gonic/gin"
 )
 
+// apiTokenPrefix distinguishes long-lived API tokens from JWTs
+const apiTokenPrefix = "unla_"
+
+// AuthMiddleware validates either a JWT or a long-lived API token from the
+// Authorization header. API token callers get claims synthesized from the
+// token's owner, plus the token's scopes stored under "token_scopes".
+func AuthMiddleware(jwtService *jwt.Service, db database.Database) gin.HandlerFunc {
+	jwtMiddleware := JWTAuthMiddleware(jwtService)
+	return func(c *gin.Context) {
+		authHeader := c.GetHeader("Authorization")
+		token, isBearer := strings.CutPrefix(authHeader, "Bearer ")
+		if !isBearer || !strings.HasPrefix(token, apiTokenPrefix) {
+			jwtMiddleware(c)
+			return
+		}
+
+		sum := sha256.Sum256([]byte(token))
+		record, err := db.GetAPITokenByHash(c.Request.Context(), hex.EncodeToString(sum[:]))
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		user, err := db.GetUserByUsername(c.Request.Context(), record.Username)
+		if err != nil || !user.IsActive {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		c.Set("claims", &jwt.Claims{
+			UserID:   user.ID,
+			Username: user.Username,
+			Role:     string(user.Role),
+		})
+		// Always present for token callers, so scope checks deny by
+		// default; a token without scopes grants nothing
+		var scopes []string
+		if record.Scopes != "" {
+			scopes = strings.Split(record.Scopes, ",")
+		}
+		c.Set("token_scopes", scopes)
+		// Best effort; the request must not fail on a bookkeeping write
+		_ = db.TouchAPIToken(c.Request.Context(), record.ID)
+		c.Next()
+	}
+}
+
+// RequireScope enforces API token scopes on a route group. JWT callers
+// (no token scopes in context) are unrestricted; API token callers must
+// carry the required scope.
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		scopesVal, exists := c.Get("token_scopes")
+		if !exists {
+			c.Next()
+			return
+		}
+		for _, s := range scopesVal.([]string) {
+			if s == scope {
+				c.Next()
+				return
+			}
+		}
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "missing scope: " + scope})
+	}
+}
+
+// DenyAPITokens rejects API token callers outright; account and token
+// management stay interactive-only regardless of granted scopes.
+func DenyAPITokens() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, isToken := c.Get("token_scopes"); isToken {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "API tokens cannot access this endpoint"})
+			return
+		}
+		c.Next()
+	}
+}
+
 // JWTAuthMiddleware creates a middleware that validates JWT tokens
 func JWTAuthMiddleware(jwtService *jwt.Service) gin.HandlerFunc {
 	return func(c *gin.Context) {
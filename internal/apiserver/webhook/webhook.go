@@ -0,0 +1,121 @@
+// Package webhook delivers apiserver events (config and user changes) to
+// registered HTTP endpoints with HMAC signatures and retries.
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/amoylab/unla/internal/common/config"
+
+	"go.uber.org/zap"
+)
+
+const (
+	deliveryAttempts = 3
+	deliveryTimeout  = 10 * time.Second
+)
+
+var (
+	mu         sync.RWMutex
+	hooks      []config.WebhookConfig
+	logger     *zap.Logger
+	httpClient = &http.Client{Timeout: deliveryTimeout}
+)
+
+// Configure installs the webhook targets; call once at startup
+func Configure(cfgs []config.WebhookConfig, log *zap.Logger) {
+	mu.Lock()
+	defer mu.Unlock()
+	hooks = cfgs
+	logger = log.Named("apiserver.webhook")
+}
+
+// Fire delivers an event to every webhook subscribed to it. Delivery is
+// asynchronous with retries; failures are logged, never surfaced to the
+// request that triggered the event.
+func Fire(event string, payload any) {
+	mu.RLock()
+	targets := hooks
+	log := logger
+	mu.RUnlock()
+	if len(targets) == 0 {
+		return
+	}
+
+	body, err := json.Marshal(map[string]any{
+		"event":     event,
+		"timestamp": time.Now().UTC(),
+		"payload":   payload,
+	})
+	if err != nil {
+		return
+	}
+
+	for _, hook := range targets {
+		if !subscribed(&hook, event) {
+			continue
+		}
+		go deliver(log, hook, event, body)
+	}
+}
+
+// subscribed reports whether a hook wants this event; an empty filter
+// receives everything
+func subscribed(hook *config.WebhookConfig, event string) bool {
+	if len(hook.Events) == 0 {
+		return true
+	}
+	for _, wanted := range hook.Events {
+		if wanted == event {
+			return true
+		}
+	}
+	return false
+}
+
+// deliver posts the event with retries and exponential backoff
+func deliver(log *zap.Logger, hook config.WebhookConfig, event string, body []byte) {
+	signature := ""
+	if hook.Secret != "" {
+		mac := hmac.New(sha256.New, []byte(hook.Secret))
+		mac.Write(body)
+		signature = hex.EncodeToString(mac.Sum(nil))
+	}
+
+	backoff := time.Second
+	for attempt := 1; attempt <= deliveryAttempts; attempt++ {
+		req, err := http.NewRequest(http.MethodPost, hook.URL, bytes.NewReader(body))
+		if err != nil {
+			return
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Webhook-Event", event)
+		if signature != "" {
+			req.Header.Set("X-Webhook-Signature", "sha256="+signature)
+		}
+
+		resp, err := httpClient.Do(req)
+		if err == nil {
+			resp.Body.Close()
+			if resp.StatusCode < 300 {
+				return
+			}
+		}
+		if log != nil {
+			log.Warn("webhook delivery failed",
+				zap.String("url", hook.URL),
+				zap.String("event", event),
+				zap.Int("attempt", attempt),
+				zap.Error(err))
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
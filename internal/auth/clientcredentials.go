@@ -0,0 +1,100 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/amoylab/unla/internal/common/config"
+)
+
+// tokenRefreshMargin renews tokens this long before they expire
+const tokenRefreshMargin = 30 * time.Second
+
+var upstreamTokenHTTPClient = &http.Client{Timeout: 15 * time.Second}
+
+// upstreamTokenSources caches token sources per token URL + client id
+var upstreamTokenSources sync.Map
+
+// UpstreamTokenSource fetches and caches OAuth2 client-credentials tokens
+// for calls to an upstream backend
+type UpstreamTokenSource struct {
+	cfg config.UpstreamAuthConfig
+
+	mu      sync.Mutex
+	token   string
+	expires time.Time
+}
+
+// GetUpstreamTokenSource returns the shared token source for a backend's
+// client-credentials configuration
+func GetUpstreamTokenSource(cfg *config.UpstreamAuthConfig) *UpstreamTokenSource {
+	key := cfg.TokenURL + "|" + cfg.ClientID
+	if cached, ok := upstreamTokenSources.Load(key); ok {
+		return cached.(*UpstreamTokenSource)
+	}
+	source := &UpstreamTokenSource{cfg: *cfg}
+	if cached, loaded := upstreamTokenSources.LoadOrStore(key, source); loaded {
+		return cached.(*UpstreamTokenSource)
+	}
+	return source
+}
+
+// Token returns a valid access token, refreshing it before expiry
+func (s *UpstreamTokenSource) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token != "" && time.Until(s.expires) > tokenRefreshMargin {
+		return s.token, nil
+	}
+
+	form := url.Values{
+		"grant_type":    {"client_credentials"},
+		"client_id":     {s.cfg.ClientID},
+		"client_secret": {s.cfg.ClientSecret},
+	}
+	if len(s.cfg.Scopes) > 0 {
+		form.Set("scope", strings.Join(s.cfg.Scopes, " "))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.cfg.TokenURL,
+		strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := upstreamTokenHTTPClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("token request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("token request failed: status %d", resp.StatusCode)
+	}
+
+	var tokenResp struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tokenResp); err != nil {
+		return "", fmt.Errorf("failed to decode token response: %w", err)
+	}
+	if tokenResp.AccessToken == "" {
+		return "", fmt.Errorf("token response carries no access_token")
+	}
+
+	s.token = tokenResp.AccessToken
+	expiresIn := tokenResp.ExpiresIn
+	if expiresIn <= 0 {
+		expiresIn = 3600
+	}
+	s.expires = time.Now().Add(time.Duration(expiresIn) * time.Second)
+	return s.token, nil
+}
@@ -0,0 +1,156 @@
+package jwt
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ValidationConfig represents the configuration for validating externally
+// issued JWT/OIDC tokens
+type ValidationConfig struct {
+	JWKSURL  string        `yaml:"jwks_url"`  // JWKS endpoint for RS256 tokens
+	Secret   string        `yaml:"secret"`    // shared secret for HS256 tokens
+	Issuer   string        `yaml:"issuer"`    // expected iss claim; empty skips the check
+	Audience string        `yaml:"audience"`  // expected aud claim; empty skips the check
+	CacheTTL time.Duration `yaml:"cache_ttl"` // how long fetched JWKS keys are cached
+}
+
+// Validator validates JWT tokens against a JWKS endpoint or a shared secret
+type Validator struct {
+	cfg ValidationConfig
+
+	mu          sync.RWMutex
+	keys        map[string]*rsa.PublicKey
+	keysFetched time.Time
+}
+
+// NewValidator creates a new token validator
+func NewValidator(cfg ValidationConfig) *Validator {
+	if cfg.CacheTTL <= 0 {
+		cfg.CacheTTL = 5 * time.Minute
+	}
+	return &Validator{
+		cfg:  cfg,
+		keys: make(map[string]*rsa.PublicKey),
+	}
+}
+
+// Validate parses and verifies a token, returning its claims
+func (v *Validator) Validate(tokenString string) (jwt.MapClaims, error) {
+	opts := []jwt.ParserOption{jwt.WithExpirationRequired()}
+	if v.cfg.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(v.cfg.Issuer))
+	}
+	if v.cfg.Audience != "" {
+		opts = append(opts, jwt.WithAudience(v.cfg.Audience))
+	}
+
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(tokenString, claims, v.keyFunc, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+	return claims, nil
+}
+
+// keyFunc resolves the verification key for a token
+func (v *Validator) keyFunc(token *jwt.Token) (interface{}, error) {
+	switch token.Method.(type) {
+	case *jwt.SigningMethodHMAC:
+		if v.cfg.Secret == "" {
+			return nil, fmt.Errorf("HS256 token received but no secret configured")
+		}
+		return []byte(v.cfg.Secret), nil
+	case *jwt.SigningMethodRSA:
+		if v.cfg.JWKSURL == "" {
+			return nil, fmt.Errorf("RS256 token received but no jwks_url configured")
+		}
+		kid, _ := token.Header["kid"].(string)
+		return v.publicKey(kid)
+	default:
+		return nil, fmt.Errorf("unsupported signing method: %s", token.Method.Alg())
+	}
+}
+
+// publicKey returns the cached RSA key for kid, refreshing the JWKS if the
+// cache is stale or the kid is unknown
+func (v *Validator) publicKey(kid string) (*rsa.PublicKey, error) {
+	v.mu.RLock()
+	key, ok := v.keys[kid]
+	fresh := time.Since(v.keysFetched) < v.cfg.CacheTTL
+	v.mu.RUnlock()
+	if ok && fresh {
+		return key, nil
+	}
+
+	if err := v.refreshKeys(); err != nil {
+		return nil, err
+	}
+
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	key, ok = v.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("no JWKS key found for kid %q", kid)
+	}
+	return key, nil
+}
+
+var jwksHTTPClient = &http.Client{Timeout: 10 * time.Second}
+
+// refreshKeys fetches the JWKS document and rebuilds the key cache
+func (v *Validator) refreshKeys() error {
+	resp, err := jwksHTTPClient.Get(v.cfg.JWKSURL)
+	if err != nil {
+		return fmt.Errorf("failed to fetch JWKS: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to fetch JWKS: status %d", resp.StatusCode)
+	}
+
+	var doc struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		} `json:"keys"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&doc); err != nil {
+		return fmt.Errorf("failed to decode JWKS: %w", err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey, len(doc.Keys))
+	for _, k := range doc.Keys {
+		if k.Kty != "RSA" {
+			continue
+		}
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			continue
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			continue
+		}
+		keys[k.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+
+	v.mu.Lock()
+	v.keys = keys
+	v.keysFetched = time.Now()
+	v.mu.Unlock()
+	return nil
+}
@@ -18,4 +18,6 @@ type AuthMode string
 
 const (
 	AuthModeOAuth2 AuthMode = "oauth2"
+	AuthModeAPIKey AuthMode = "apikey"
+	AuthModeOIDC   AuthMode = "oidc"
 )
@@ -9,14 +9,63 @@ import (
 
 type (
 	APIServerConfig struct {
-		Database   DatabaseConfig   `yaml:"database"`
-		OpenAI     OpenAIConfig     `yaml:"openai"`
-		Storage    StorageConfig    `yaml:"storage"`
-		Notifier   NotifierConfig   `yaml:"notifier"`
-		Logger     LoggerConfig     `yaml:"logger"`
-		JWT        JWTConfig        `yaml:"jwt"`
-		SuperAdmin SuperAdminConfig `yaml:"super_admin"`
-		I18n       I18nConfig       `yaml:"i18n"`
+		Database    DatabaseConfig    `yaml:"database"`
+		OpenAI      OpenAIConfig      `yaml:"openai"`
+		Storage     StorageConfig     `yaml:"storage"`
+		Notifier    NotifierConfig    `yaml:"notifier"`
+		Logger      LoggerConfig      `yaml:"logger"`
+		JWT         JWTConfig         `yaml:"jwt"`
+		SuperAdmin  SuperAdminConfig  `yaml:"super_admin"`
+		I18n        I18nConfig        `yaml:"i18n"`
+		OIDC        *OIDCLoginConfig  `yaml:"oidc"`
+		Attachments AttachmentsConfig `yaml:"attachments"`
+		// GatewayBaseURL is where the playground and chat reach the MCP
+		// gateway; default http://localhost:5235
+		GatewayBaseURL string          `yaml:"gateway_base_url"`
+		Webhooks       []WebhookConfig `yaml:"webhooks"`
+	}
+
+	// WebhookConfig registers an endpoint notified about apiserver events
+	WebhookConfig struct {
+		URL    string   `yaml:"url"`
+		Secret string   `yaml:"secret"` // HMAC-SHA256 signing secret
+		Events []string `yaml:"events"` // empty subscribes to everything
+	}
+
+	// AttachmentsConfig configures chat attachment storage
+	AttachmentsConfig struct {
+		Type      string `yaml:"type"`        // "local" (default) or "s3"
+		Dir       string `yaml:"dir"`         // local blob directory; default ./attachments
+		MaxSizeMB int    `yaml:"max_size_mb"` // per-file limit; default 10
+		// SignSecret is the HMAC secret for download URLs; empty generates
+		// a random per-process secret, so links stop working on restart
+		SignSecret string              `yaml:"sign_secret"`
+		S3         AttachmentsS3Config `yaml:"s3"`
+	}
+
+	// AttachmentsS3Config configures the S3 attachment backend
+	AttachmentsS3Config struct {
+		Region       string `yaml:"region"`
+		Bucket       string `yaml:"bucket"`
+		Endpoint     string `yaml:"endpoint"` // empty uses the AWS endpoint
+		Prefix       string `yaml:"prefix"`   // optional key prefix
+		AccessKey    string `yaml:"access_key"`
+		SecretKey    string `yaml:"secret_key"`
+		SessionToken string `yaml:"session_token"`
+		PathStyle    bool   `yaml:"path_style"` // required by most S3-compatible stores
+	}
+
+	// OIDCLoginConfig configures SSO login via an external OIDC provider
+	// using the authorization code flow
+	OIDCLoginConfig struct {
+		Issuer        string   `yaml:"issuer"` // provider base URL, used for discovery
+		ClientID      string   `yaml:"client_id"`
+		ClientSecret  string   `yaml:"client_secret"`
+		RedirectURI   string   `yaml:"redirect_uri"`   // this apiserver's callback URL
+		Scopes        []string `yaml:"scopes"`         // defaults to openid profile email
+		UsernameClaim string   `yaml:"username_claim"` // defaults to preferred_username
+		GroupsClaim   string   `yaml:"groups_claim"`   // defaults to groups
+		AdminGroups   []string `yaml:"admin_groups"`   // members of these groups become admins
 	}
 
 	// I18nConfig represents the internationalization configuration
@@ -32,12 +81,22 @@ type (
 		Password string `yaml:"password"` // password
 		DBName   string `yaml:"dbname"`   // database name
 		SSLMode  string `yaml:"sslmode"`  // disable (for postgres)
+
+		// Connection pool tuning; zero values keep the driver defaults
+		MaxOpenConns    int           `yaml:"max_open_conns"`
+		MaxIdleConns    int           `yaml:"max_idle_conns"`
+		ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime"`
+		// SlowQueryThreshold logs queries slower than this; 0 disables
+		SlowQueryThreshold time.Duration `yaml:"slow_query_threshold"`
 	}
 
 	OpenAIConfig struct {
 		APIKey  string `yaml:"api_key"`
 		Model   string `yaml:"model"`
 		BaseURL string `yaml:"base_url"`
+		// Provider selects the chat backend: openai (default; also covers
+		// Azure OpenAI and Ollama via base_url) or anthropic
+		Provider string `yaml:"provider"`
 	}
 
 	JWTConfig struct {
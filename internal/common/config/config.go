@@ -31,12 +31,204 @@ type (
 		Notifier       NotifierConfig   `yaml:"notifier"`
 		Session        SessionConfig    `yaml:"session"`
 		Auth           AuthConfig       `yaml:"auth"`
+		RateLimit      RateLimitConfig  `yaml:"rate_limit"`
+		// ToolsListPageSize caps the number of tools returned per
+		// tools/list page; 0 disables pagination
+		ToolsListPageSize int `yaml:"tools_list_page_size"`
+		// DrainTimeout bounds how long shutdown waits for in-flight tool
+		// calls before closing connections; default 30s
+		DrainTimeout time.Duration `yaml:"drain_timeout"`
+		// AdminPort serves the inner health/readiness/admin endpoints;
+		// 0 disables the admin listener
+		AdminPort int `yaml:"admin_port"`
+		// AdminHost is the interface the admin listener binds; default
+		// 127.0.0.1 so the admin API is not exposed beyond the host
+		AdminHost string `yaml:"admin_host"`
+		// AdminToken, when set, is required as a bearer token on all
+		// mutating admin endpoints
+		AdminToken string          `yaml:"admin_token"`
+		AccessLog  AccessLogConfig `yaml:"access_log"`
+		Audit      AuditConfig     `yaml:"audit"`
+		// UntrustedTenants lists tenants whose configs render templates
+		// without access to the env and secret functions
+		UntrustedTenants []string `yaml:"untrusted_tenants"`
+		// TenantHeader, when set, requires incoming MCP requests to carry
+		// this header matching the tenant that owns the requested prefix
+		TenantHeader string             `yaml:"tenant_header"`
+		Quota        QuotaConfig        `yaml:"quota"`
+		CommandTools CommandToolsConfig `yaml:"command_tools"`
+		TLS          *TLSConfig         `yaml:"tls"`
+		// KeepaliveInterval sends comment pings on idle SSE streams so
+		// intermediaries don't drop them; default 30s, negative disables
+		KeepaliveInterval time.Duration `yaml:"keepalive_interval"`
+		Policy            *PolicyConfig `yaml:"policy"`
+		Limits            LimitsConfig  `yaml:"limits"`
+		// IPFilter applies to every prefix; per-router filters refine it
+		IPFilter *IPFilterConfig `yaml:"ip_filter"`
+		// TrustedProxies are the proxy addresses whose X-Forwarded-For is
+		// honored when resolving the client IP
+		TrustedProxies []string `yaml:"trusted_proxies"`
+		// HealthCheckInterval probes proxied backends periodically and
+		// exposes the results on the admin port; 0 disables probing
+		HealthCheckInterval time.Duration `yaml:"health_check_interval"`
+		// ToolListCacheTTL caches proxied tool lists; stale lists are
+		// served while a background refresh runs. 0 disables caching.
+		ToolListCacheTTL time.Duration `yaml:"tool_list_cache_ttl"`
+		// LastGoodConfigPath persists the last successfully loaded config
+		// set so restarts survive a storage outage; empty disables it
+		LastGoodConfigPath string `yaml:"last_good_config_path"`
+		// Listeners serve MCP traffic on additional addresses beyond the
+		// main port, each optionally restricted to a prefix subset
+		Listeners []ListenerConfig `yaml:"listeners"`
+	}
+
+	// ListenerConfig is one extra listener. Address is either
+	// "host:port" for TCP or "unix:///path/to.sock" for a unix socket.
+	ListenerConfig struct {
+		Address string `yaml:"address"`
+		// Prefixes restricts which routed prefixes this listener serves;
+		// empty serves everything
+		Prefixes []string `yaml:"prefixes"`
+		// DisableAuth skips the prefixes' auth checks for traffic on this
+		// listener, e.g. a unix socket only trusted sidecars can reach
+		DisableAuth bool `yaml:"disable_auth"`
+	}
+
+	// LimitsConfig guards against oversized or deeply nested payloads
+	LimitsConfig struct {
+		// MaxBodyBytes caps request bodies; default 4MiB, negative disables
+		MaxBodyBytes int64 `yaml:"max_body_bytes"`
+		// MaxJSONDepth caps JSON nesting in JSON-RPC payloads; default 100
+		MaxJSONDepth int `yaml:"max_json_depth"`
+		// TemplateMaxOutputBytes caps rendered template output; default 4MiB
+		TemplateMaxOutputBytes int64 `yaml:"template_max_output_bytes"`
+		// TemplateTimeout bounds template execution; default 5s
+		TemplateTimeout time.Duration `yaml:"template_timeout"`
+	}
+
+	// PolicyConfig delegates tool-call authorization to an OPA endpoint.
+	// The URL should point at the policy document's data API, e.g.
+	// http://opa:8181/v1/data/unla/allow
+	PolicyConfig struct {
+		OPAURL string `yaml:"opa_url"`
+		// FailOpen allows calls when the policy endpoint is unreachable;
+		// by default evaluation failures deny
+		FailOpen bool `yaml:"fail_open"`
+	}
+
+	// TLSConfig makes the gateway listener serve HTTPS (and HTTP/2).
+	// Certificates are re-read from disk when the files change, and setting
+	// client_ca_file enables mutual TLS.
+	TLSConfig struct {
+		CertFile     string      `yaml:"cert_file"`
+		KeyFile      string      `yaml:"key_file"`
+		ClientCAFile string      `yaml:"client_ca_file"`
+		ACME         *ACMEConfig `yaml:"acme"`
+	}
+
+	// ACMEConfig obtains and renews certificates automatically via the
+	// HTTP-01 challenge. The cache directory should be a shared volume when
+	// multiple replicas serve the same domains.
+	ACMEConfig struct {
+		Domains  []string `yaml:"domains"`
+		Email    string   `yaml:"email"`
+		CacheDir string   `yaml:"cache_dir"` // default ./acme-cache
+		// HTTPPort serves the HTTP-01 challenge; default 80
+		HTTPPort int `yaml:"http_port"`
+		// DirectoryURL overrides the ACME directory, e.g. for staging
+		DirectoryURL string `yaml:"directory_url"`
+	}
+
+	// CommandToolsConfig sandboxes local command tools; only binaries on
+	// the allowlist may be executed
+	CommandToolsConfig struct {
+		AllowedBinaries []string `yaml:"allowed_binaries"`
+	}
+
+	// QuotaConfig limits tool call volume per day/month
+	QuotaConfig struct {
+		Enabled bool        `yaml:"enabled"`
+		Storage string      `yaml:"storage"` // "memory" or "redis"
+		Redis   RedisConfig `yaml:"redis"`
+		Limits  []QuotaRule `yaml:"limits"`
+	}
+
+	// QuotaRule limits calls matching the given dimensions; empty
+	// dimensions match everything. A zero Daily/Monthly leaves that
+	// window unlimited.
+	QuotaRule struct {
+		Tenant  string `yaml:"tenant"`
+		APIKey  string `yaml:"api_key"`
+		Tool    string `yaml:"tool"`
+		Daily   int64  `yaml:"daily"`
+		Monthly int64  `yaml:"monthly"`
+	}
+
+	// AuditConfig configures the durable audit trail of tool invocations
+	AuditConfig struct {
+		Enabled bool `yaml:"enabled"`
+		// FilePath receives the JSON audit records; default ./audit.log
+		FilePath string `yaml:"file_path"`
+		// RetentionDays rotates out audit records older than this; 0 keeps
+		// them forever
+		RetentionDays int `yaml:"retention_days"`
+		// RedactedFields are argument names masked in audit records, in
+		// addition to the built-in sensitive names
+		RedactedFields []string `yaml:"redacted_fields"`
+	}
+
+	// AccessLogConfig configures the structured access log for MCP traffic,
+	// kept separate from the application log
+	AccessLogConfig struct {
+		Enabled bool `yaml:"enabled"`
+		// FilePath receives the JSON access log; stdout when empty
+		FilePath string `yaml:"file_path"`
+		// SampleRate logs this fraction of requests; 1.0 (or 0, the
+		// default) logs everything
+		SampleRate float64 `yaml:"sample_rate"`
+	}
+
+	// RateLimitConfig represents the rate limiting configuration
+	RateLimitConfig struct {
+		Enabled bool            `yaml:"enabled"`
+		Storage string          `yaml:"storage"` // "memory" or "redis"
+		Redis   RedisConfig     `yaml:"redis"`   // Redis configuration for multi-instance deployments
+		Rate    float64         `yaml:"rate"`    // default tokens per second; 0 disables the default limit
+		Burst   int             `yaml:"burst"`   // default bucket size
+		Rules   []RateLimitRule `yaml:"rules"`
+	}
+
+	// RateLimitRule overrides the default limit for a prefix or a single tool.
+	// The most specific matching rule wins: prefix+tool, then prefix.
+	RateLimitRule struct {
+		Prefix string  `yaml:"prefix"`
+		Tool   string  `yaml:"tool"` // empty matches all tools under the prefix
+		Rate   float64 `yaml:"rate"`
+		Burst  int     `yaml:"burst"`
 	}
 
 	// SessionConfig represents the session storage configuration
 	SessionConfig struct {
-		Type  string             `yaml:"type"`  // "memory" or "redis"
-		Redis SessionRedisConfig `yaml:"redis"` // Redis configuration
+		Type   string              `yaml:"type"`   // "memory" or "redis"
+		Redis  SessionRedisConfig  `yaml:"redis"`  // Redis configuration
+		Memory SessionMemoryConfig `yaml:"memory"` // memory store limits
+		// QueueSize is each connection's outbound event buffer; default 100
+		QueueSize int `yaml:"queue_size"`
+		// BackpressurePolicy decides what happens when a consumer is too
+		// slow and its queue fills: "drop" (default, drop the new message),
+		// "drop_oldest" (evict the oldest buffered message) or "block"
+		// (wait up to 5s, then fail)
+		BackpressurePolicy string `yaml:"backpressure_policy"`
+	}
+
+	// SessionMemoryConfig bounds the in-memory session store so single
+	// instance deployments without Redis don't leak sessions
+	SessionMemoryConfig struct {
+		// MaxSessions evicts the least recently used session beyond this
+		// bound; default 10000
+		MaxSessions int `yaml:"max_sessions"`
+		// TTL expires sessions idle for longer than this; default 24h
+		TTL time.Duration `yaml:"ttl"`
 	}
 
 	// SessionRedisConfig represents the Redis configuration for session storage
@@ -50,6 +242,9 @@ type (
 		Topic       string        `yaml:"topic"`
 		Prefix      string        `yaml:"prefix"`
 		TTL         time.Duration `yaml:"ttl"` // TTL for session data in Redis
+		// EventDelivery selects "pubsub" (default, fire-and-forget) or
+		// "streams" (Redis Streams, at-least-once with replay)
+		EventDelivery string `yaml:"event_delivery"`
 	}
 
 	// LoggerConfig represents the logger configuration
@@ -70,8 +265,19 @@ type (
 
 	// AuthConfig defines the authentication configuration
 	AuthConfig struct {
-		OAuth2 *OAuth2Config `yaml:"oauth2"`
-		CORS   *CORSConfig   `yaml:"cors,omitempty"`
+		OAuth2 *OAuth2Config        `yaml:"oauth2"`
+		JWT    *JWTValidationConfig `yaml:"jwt"`
+		CORS   *CORSConfig          `yaml:"cors,omitempty"`
+	}
+
+	// JWTValidationConfig configures validation of externally issued
+	// JWT/OIDC tokens on prefixes with auth mode oidc
+	JWTValidationConfig struct {
+		JWKSURL  string        `yaml:"jwks_url"`
+		Secret   string        `yaml:"secret"`
+		Issuer   string        `yaml:"issuer"`
+		Audience string        `yaml:"audience"`
+		CacheTTL time.Duration `yaml:"cache_ttl"`
 	}
 	OAuth2Config struct {
 		Issuer  string              `yaml:"issuer"`
@@ -123,6 +329,26 @@ func LoadConfig[T Type](filename string) (*T, string, error) {
 	return &cfg, cfgPath, nil
 }
 
+// envOverrides holds values from the optional overrides file named by
+// UNLA_ENV_OVERRIDES; they take precedence over the process environment
+var envOverrides map[string]string
+
+func init() {
+	if path := os.Getenv("UNLA_ENV_OVERRIDES"); path != "" {
+		if overrides, err := godotenv.Read(path); err == nil {
+			envOverrides = overrides
+		}
+	}
+}
+
+// ResolveEnv replaces ${VAR:default} placeholders against the optional
+// overrides file (UNLA_ENV_OVERRIDES) and the process environment;
+// exported so stored configs can be resolved the same way as the service
+// configuration file
+func ResolveEnv(content []byte) []byte {
+	return resolveEnv(content)
+}
+
 // resolveEnv replaces environment variable placeholders in YAML content
 func resolveEnv(content []byte) []byte {
 	regex := regexp.MustCompile(`\$\{(\w+)(?::([^}]*))?\}`)
@@ -136,6 +362,9 @@ func resolveEnv(content []byte) []byte {
 			defaultValue = string(matches[2])
 		}
 
+		if value, exists := envOverrides[envKey]; exists {
+			return []byte(value)
+		}
 		if value, exists := os.LookupEnv(envKey); exists {
 			return []byte(value)
 		}
@@ -19,24 +19,54 @@ type (
 	}
 
 	MCPConfig struct {
-		Name       string            `json:"name" yaml:"name"`
-		Tenant     string            `json:"tenant" yaml:"tenant"`
-		CreatedAt  time.Time         `json:"createdAt" yaml:"createdAt"`
-		UpdatedAt  time.Time         `json:"updatedAt" yaml:"updatedAt"`
-		DeletedAt  time.Time         `json:"deletedAt,omitempty" yaml:"deletedAt,omitempty"` // non-zero indicates that all information has been deleted
-		Routers    []RouterConfig    `json:"routers,omitempty" yaml:"routers,omitempty"`
-		Servers    []ServerConfig    `json:"servers,omitempty" yaml:"servers,omitempty"`
-		Tools      []ToolConfig      `json:"tools,omitempty" yaml:"tools,omitempty"`
-		Prompts    []PromptConfig    `json:"prompts,omitempty" yaml:"prompts,omitempty"`
-		McpServers []MCPServerConfig `json:"mcpServers,omitempty" yaml:"mcpServers,omitempty"` // proxy mcp servers
+		Name              string                   `json:"name" yaml:"name"`
+		Tenant            string                   `json:"tenant" yaml:"tenant"`
+		CreatedAt         time.Time                `json:"createdAt" yaml:"createdAt"`
+		UpdatedAt         time.Time                `json:"updatedAt" yaml:"updatedAt"`
+		DeletedAt         time.Time                `json:"deletedAt,omitempty" yaml:"deletedAt,omitempty"` // non-zero indicates that all information has been deleted
+		Routers           []RouterConfig           `json:"routers,omitempty" yaml:"routers,omitempty"`
+		Servers           []ServerConfig           `json:"servers,omitempty" yaml:"servers,omitempty"`
+		Tools             []ToolConfig             `json:"tools,omitempty" yaml:"tools,omitempty"`
+		Prompts           []PromptConfig           `json:"prompts,omitempty" yaml:"prompts,omitempty"`
+		Resources         []ResourceConfig         `json:"resources,omitempty" yaml:"resources,omitempty"`
+		ResourceTemplates []ResourceTemplateConfig `json:"resourceTemplates,omitempty" yaml:"resourceTemplates,omitempty"`
+		McpServers        []MCPServerConfig        `json:"mcpServers,omitempty" yaml:"mcpServers,omitempty"` // proxy mcp servers
 	}
 
 	RouterConfig struct {
-		Server    string      `json:"server" yaml:"server"`
-		Prefix    string      `json:"prefix" yaml:"prefix"`
-		SSEPrefix string      `json:"ssePrefix" yaml:"ssePrefix"`
-		CORS      *CORSConfig `json:"cors,omitempty" yaml:"cors,omitempty"`
-		Auth      *Auth       `json:"auth,omitempty" yaml:"auth,omitempty"`
+		Server     string            `json:"server" yaml:"server"`
+		Prefix     string            `json:"prefix" yaml:"prefix"`
+		SSEPrefix  string            `json:"ssePrefix" yaml:"ssePrefix"`
+		CORS       *CORSConfig       `json:"cors,omitempty" yaml:"cors,omitempty"`
+		Auth       *Auth             `json:"auth,omitempty" yaml:"auth,omitempty"`
+		ToolFilter *ToolFilterConfig `json:"toolFilter,omitempty" yaml:"toolFilter,omitempty"`
+		IPFilter   *IPFilterConfig   `json:"ipFilter,omitempty" yaml:"ipFilter,omitempty"`
+		// Hosts serve this prefix at the root of the given virtual hosts,
+		// e.g. mcp.team-a.example.com/mcp
+		Hosts []string `json:"hosts,omitempty" yaml:"hosts,omitempty"`
+	}
+
+	// IPFilterConfig restricts access by client address. Deny wins; an
+	// empty allow list permits everything not denied. Entries are CIDRs or
+	// single addresses.
+	IPFilterConfig struct {
+		Allow []string `json:"allow,omitempty" yaml:"allow,omitempty"`
+		Deny  []string `json:"deny,omitempty" yaml:"deny,omitempty"`
+	}
+
+	// ToolFilterConfig restricts which tools a prefix exposes; per-consumer
+	// overrides are keyed by API key. Deny wins over allow; an empty allow
+	// list permits everything not denied.
+	ToolFilterConfig struct {
+		Allow     []string                  `json:"allow,omitempty" yaml:"allow,omitempty"`
+		Deny      []string                  `json:"deny,omitempty" yaml:"deny,omitempty"`
+		Consumers map[string]ConsumerFilter `json:"consumers,omitempty" yaml:"consumers,omitempty"`
+	}
+
+	// ConsumerFilter overrides the prefix filter for one API key
+	ConsumerFilter struct {
+		Allow []string `json:"allow,omitempty" yaml:"allow,omitempty"`
+		Deny  []string `json:"deny,omitempty" yaml:"deny,omitempty"`
 	}
 
 	CORSConfig struct {
@@ -54,10 +84,34 @@ type (
 	}
 
 	ServerConfig struct {
-		Name         string            `json:"name" yaml:"name"`
-		Description  string            `json:"description" yaml:"description"`
-		AllowedTools []string          `json:"allowedTools,omitempty" yaml:"allowedTools,omitempty"`
-		Config       map[string]string `json:"config,omitempty" yaml:"config,omitempty"`
+		Name         string              `json:"name" yaml:"name"`
+		Description  string              `json:"description" yaml:"description"`
+		AllowedTools []string            `json:"allowedTools,omitempty" yaml:"allowedTools,omitempty"`
+		Config       map[string]string   `json:"config,omitempty" yaml:"config,omitempty"`
+		UpstreamAuth *UpstreamAuthConfig `json:"upstreamAuth,omitempty" yaml:"upstreamAuth,omitempty"`
+		// ArgValidation checks tools/call arguments against the tool's
+		// input schema: "strict" rejects violations, "warn" logs them,
+		// "off" (default) skips the check
+		ArgValidation string            `json:"argValidation,omitempty" yaml:"argValidation,omitempty"`
+		ToolNaming    *ToolNamingConfig `json:"toolNaming,omitempty" yaml:"toolNaming,omitempty"`
+	}
+
+	// ToolNamingConfig controls the names tools are exposed under so
+	// aggregated backends cannot collide silently
+	ToolNamingConfig struct {
+		// Namespace prefixes every tool with "<server>." unless renamed
+		Namespace bool `json:"namespace" yaml:"namespace"`
+		// Rename maps original tool names to exposed names
+		Rename map[string]string `json:"rename,omitempty" yaml:"rename,omitempty"`
+	}
+
+	// UpstreamAuthConfig fetches OAuth2 client-credentials tokens for calls
+	// to a backend and injects them as a Bearer Authorization header
+	UpstreamAuthConfig struct {
+		TokenURL     string   `json:"tokenUrl" yaml:"tokenUrl"`
+		ClientID     string   `json:"clientId" yaml:"clientId"`
+		ClientSecret string   `json:"clientSecret" yaml:"clientSecret"`
+		Scopes       []string `json:"scopes,omitempty" yaml:"scopes,omitempty"`
 	}
 
 	ToolConfig struct {
@@ -65,12 +119,141 @@ type (
 		Description  string            `json:"description,omitempty" yaml:"description,omitempty"`
 		Method       string            `json:"method" yaml:"method"`
 		Endpoint     string            `json:"endpoint" yaml:"endpoint"`
+		Timeout      time.Duration     `json:"timeout,omitempty" yaml:"timeout,omitempty"` // bounds a single invocation; 0 means no bound
 		Proxy        *ProxyConfig      `json:"proxy,omitempty" yaml:"proxy,omitempty"`
 		Headers      map[string]string `json:"headers,omitempty" yaml:"headers,omitempty"`
 		Args         []ArgConfig       `json:"args,omitempty" yaml:"args,omitempty"`
 		RequestBody  string            `json:"requestBody"  yaml:"requestBody"`
 		ResponseBody string            `json:"responseBody" yaml:"responseBody"`
 		InputSchema  map[string]any    `json:"inputSchema,omitempty" yaml:"inputSchema,omitempty"`
+		OutputSchema map[string]any    `json:"outputSchema,omitempty" yaml:"outputSchema,omitempty"`
+		// OutputSchemaMode controls validation of results against
+		// OutputSchema: "strict" fails the call, "warn" (default) logs,
+		// "off" skips validation
+		OutputSchemaMode string                     `json:"outputSchemaMode,omitempty" yaml:"outputSchemaMode,omitempty"`
+		Annotations      *mcp.ToolAnnotationsSchema `json:"annotations,omitempty" yaml:"annotations,omitempty"`
+		// RedactedArgs are argument names masked in logs and audit records
+		// for this tool, in addition to the built-in sensitive names
+		RedactedArgs []string                 `json:"redactedArgs,omitempty" yaml:"redactedArgs,omitempty"`
+		Transform    *ResponseTransformConfig `json:"transform,omitempty" yaml:"transform,omitempty"`
+		GRPC         *GRPCToolConfig          `json:"grpc,omitempty" yaml:"grpc,omitempty"`         // set for gRPC-backed tools
+		GraphQL      *GraphQLToolConfig       `json:"graphql,omitempty" yaml:"graphql,omitempty"`   // set for GraphQL-backed tools
+		Database     *DatabaseToolConfig      `json:"database,omitempty" yaml:"database,omitempty"` // set for SQL-backed tools
+		Command      *CommandToolConfig       `json:"command,omitempty" yaml:"command,omitempty"`   // set for local command tools
+		Lambda       *LambdaToolConfig        `json:"lambda,omitempty" yaml:"lambda,omitempty"`     // set for AWS Lambda tools
+		Pipeline     *PipelineToolConfig      `json:"pipeline,omitempty" yaml:"pipeline,omitempty"` // set for composite tools
+		Mock         *MockToolConfig          `json:"mock,omitempty" yaml:"mock,omitempty"`         // set for canned-response tools
+	}
+
+	// MockToolConfig returns canned responses for testing without live
+	// upstreams
+	MockToolConfig struct {
+		// Response is a template rendered with the tool arguments
+		Response string `json:"response,omitempty" yaml:"response,omitempty"`
+		// File loads the response body from a fixture file instead
+		File string `json:"file,omitempty" yaml:"file,omitempty"`
+		// LatencyMs adds artificial delay before responding
+		LatencyMs int `json:"latencyMs,omitempty" yaml:"latencyMs,omitempty"`
+		// ErrorRate returns an IsError result for this fraction of calls
+		ErrorRate float64 `json:"errorRate,omitempty" yaml:"errorRate,omitempty"`
+	}
+
+	// PipelineToolConfig chains calls to other tools on the same server
+	// with templated data flow between steps
+	PipelineToolConfig struct {
+		Steps []PipelineStep `json:"steps" yaml:"steps"`
+		// Result is a template rendering the final output; it sees the
+		// original args plus every step's output under .Steps
+		Result string `json:"result" yaml:"result"`
+	}
+
+	// PipelineStep is one call in a composite tool
+	PipelineStep struct {
+		// Name keys this step's output in later templates
+		Name string `json:"name" yaml:"name"`
+		// Tool is the target tool on the same server
+		Tool string `json:"tool" yaml:"tool"`
+		// Arguments are templates rendered with the original args and
+		// previous step outputs
+		Arguments map[string]string `json:"arguments,omitempty" yaml:"arguments,omitempty"`
+		// Condition skips the step unless it renders to "true"; empty
+		// always runs
+		Condition string `json:"condition,omitempty" yaml:"condition,omitempty"`
+	}
+
+	// LambdaToolConfig invokes an AWS Lambda function with the tool
+	// arguments as the JSON payload. HTTP-triggered GCP Cloud Functions are
+	// covered by plain HTTP tools. Credentials fall back to the standard
+	// AWS_* environment variables when empty.
+	LambdaToolConfig struct {
+		Region       string `json:"region" yaml:"region"`
+		Function     string `json:"function" yaml:"function"` // function name or ARN
+		AccessKey    string `json:"accessKey,omitempty" yaml:"accessKey,omitempty"`
+		SecretKey    string `json:"secretKey,omitempty" yaml:"secretKey,omitempty"`
+		SessionToken string `json:"sessionToken,omitempty" yaml:"sessionToken,omitempty"`
+		// Async uses the Event invocation type instead of RequestResponse
+		Async bool `json:"async" yaml:"async"`
+		// Endpoint overrides the AWS endpoint, e.g. for LocalStack
+		Endpoint string `json:"endpoint,omitempty" yaml:"endpoint,omitempty"`
+	}
+
+	// CommandToolConfig wraps a local CLI as a tool. The binary must appear
+	// in the gateway's command_tools.allowed_binaries list; arguments are
+	// rendered through the template engine and passed without a shell.
+	CommandToolConfig struct {
+		Binary  string            `json:"binary" yaml:"binary"`
+		Args    []string          `json:"args,omitempty" yaml:"args,omitempty"` // templated argument list
+		WorkDir string            `json:"workDir,omitempty" yaml:"workDir,omitempty"`
+		Env     map[string]string `json:"env,omitempty" yaml:"env,omitempty"`         // templated environment
+		Timeout time.Duration     `json:"timeout,omitempty" yaml:"timeout,omitempty"` // default 30s
+		// MaxOutputBytes caps captured stdout; default 1MiB
+		MaxOutputBytes int64 `json:"maxOutputBytes,omitempty" yaml:"maxOutputBytes,omitempty"`
+	}
+
+	// DatabaseToolConfig exposes a parameterized SQL query as a tool.
+	// Arguments are bound as placeholders (never interpolated), queries are
+	// read-only unless explicitly allowed, and result sets are capped.
+	DatabaseToolConfig struct {
+		Driver string `json:"driver" yaml:"driver"` // postgres, mysql or sqlite
+		DSN    string `json:"dsn" yaml:"dsn"`
+		Query  string `json:"query" yaml:"query"` // uses driver-native placeholders
+		// Params names the tool arguments bound to the placeholders, in order
+		Params []string `json:"params,omitempty" yaml:"params,omitempty"`
+		// AllowWrite permits non-SELECT statements; off by default
+		AllowWrite bool `json:"allowWrite" yaml:"allowWrite"`
+		// MaxRows caps the result set; default 100
+		MaxRows int `json:"maxRows" yaml:"maxRows"`
+	}
+
+	// GraphQLToolConfig targets a GraphQL endpoint. The tool arguments are
+	// passed as query variables; errors in the GraphQL response surface as
+	// IsError tool results.
+	GraphQLToolConfig struct {
+		Endpoint string            `json:"endpoint" yaml:"endpoint"`
+		Query    string            `json:"query" yaml:"query"` // query or mutation document
+		Headers  map[string]string `json:"headers,omitempty" yaml:"headers,omitempty"`
+	}
+
+	// ResponseTransformConfig post-processes HTTP tool responses before
+	// they are returned to the client
+	ResponseTransformConfig struct {
+		// ExtractPath selects a field from the JSON response by dot path,
+		// e.g. "data.items"; the extracted value replaces the whole body
+		ExtractPath string `json:"extractPath,omitempty" yaml:"extractPath,omitempty"`
+		// StatusErrors maps HTTP status codes to friendly IsError results,
+		// e.g. 404: "not found"
+		StatusErrors map[int]string `json:"statusErrors,omitempty" yaml:"statusErrors,omitempty"`
+		// MaxBytes truncates the final text result; 0 disables truncation
+		MaxBytes int `json:"maxBytes,omitempty" yaml:"maxBytes,omitempty"`
+	}
+
+	// GRPCToolConfig targets a gRPC method instead of an HTTP endpoint.
+	// The method descriptor is resolved via server reflection and the tool
+	// arguments are mapped onto the request message by field name.
+	GRPCToolConfig struct {
+		Target    string `json:"target" yaml:"target"`       // host:port of the gRPC service
+		Method    string `json:"method" yaml:"method"`       // full method, e.g. pkg.Service/Method
+		Plaintext bool   `json:"plaintext" yaml:"plaintext"` // disable TLS
 	}
 
 	MCPServerConfig struct {
@@ -80,8 +263,63 @@ type (
 		Args         []string              `json:"args,omitempty" yaml:"args,omitempty"`       // for stdio
 		Env          map[string]string     `json:"env,omitempty" yaml:"env,omitempty"`         // for stdio
 		URL          string                `json:"url,omitempty" yaml:"url,omitempty"`         // for sse and streamable-http
+		URLs         []string              `json:"urls,omitempty" yaml:"urls,omitempty"`       // replica URLs; overrides URL when set
 		Policy       cnst.MCPStartupPolicy `json:"policy" yaml:"policy"`                       // onStart or onDemand
 		Preinstalled bool                  `json:"preinstalled" yaml:"preinstalled"`           // whether to install this MCP server when mcp-gateway starts
+		Timeout      time.Duration         `json:"timeout,omitempty" yaml:"timeout,omitempty"` // bounds a single tool call; 0 means no bound
+		Resilience   *ResilienceConfig     `json:"resilience,omitempty" yaml:"resilience,omitempty"`
+		UpstreamAuth *UpstreamAuthConfig   `json:"upstreamAuth,omitempty" yaml:"upstreamAuth,omitempty"`
+		Headers      *HeaderPolicyConfig   `json:"headers,omitempty" yaml:"headers,omitempty"`
+		Canary       *CanaryConfig         `json:"canary,omitempty" yaml:"canary,omitempty"`
+		// ToolOverrides adjust descriptions, schemas and annotations of
+		// tools fetched from the proxied backend, keyed by tool name
+		ToolOverrides map[string]ToolOverrideConfig `json:"toolOverrides,omitempty" yaml:"toolOverrides,omitempty"`
+	}
+
+	// ToolOverrideConfig replaces selected fields of a proxied tool
+	ToolOverrideConfig struct {
+		Description string                     `json:"description,omitempty" yaml:"description,omitempty"`
+		InputSchema map[string]any             `json:"inputSchema,omitempty" yaml:"inputSchema,omitempty"`
+		Annotations *mcp.ToolAnnotationsSchema `json:"annotations,omitempty" yaml:"annotations,omitempty"`
+	}
+
+	// CanaryConfig routes a percentage of sessions to a canary replica.
+	// Assignment is sticky per session so one conversation never flips
+	// between versions.
+	CanaryConfig struct {
+		URL     string `json:"url" yaml:"url"`
+		Percent int    `json:"percent" yaml:"percent"` // 0-100
+	}
+
+	// HeaderPolicyConfig controls which client headers reach an upstream
+	// MCP backend. Nothing is forwarded unless listed, so credentials like
+	// Authorization never leak to third-party servers by accident.
+	HeaderPolicyConfig struct {
+		// Allowed headers are copied from the incoming request
+		Allowed []string `json:"allowed,omitempty" yaml:"allowed,omitempty"`
+		// Static headers are always injected
+		Static map[string]string `json:"static,omitempty" yaml:"static,omitempty"`
+		// Rename maps incoming header names to upstream names
+		Rename map[string]string `json:"rename,omitempty" yaml:"rename,omitempty"`
+	}
+
+	// ResilienceConfig controls retries, timeouts and circuit breaking for
+	// calls to an upstream backend
+	ResilienceConfig struct {
+		// Retries is the number of additional attempts for failed calls;
+		// only enable it for idempotent backends
+		Retries int `json:"retries" yaml:"retries"`
+		// RetryBackoff is the initial backoff between attempts, doubled
+		// per retry; default 100ms
+		RetryBackoff time.Duration `json:"retryBackoff" yaml:"retryBackoff"`
+		// Timeout bounds a single upstream call; 0 means no bound
+		Timeout time.Duration `json:"timeout" yaml:"timeout"`
+		// BreakerThreshold trips the circuit breaker after this many
+		// consecutive failures; 0 disables the breaker
+		BreakerThreshold int `json:"breakerThreshold" yaml:"breakerThreshold"`
+		// BreakerCooldown is how long the breaker stays open before a
+		// trial call is allowed; default 30s
+		BreakerCooldown time.Duration `json:"breakerCooldown" yaml:"breakerCooldown"`
 	}
 
 	ArgConfig struct {
@@ -122,12 +360,18 @@ type (
 	// Auth represents authentication configuration
 	Auth struct {
 		Mode cnst.AuthMode `json:"mode" yaml:"mode"`
+		// APIKeys lists the keys accepted for this prefix when Mode is
+		// apikey; multiple active keys allow zero-downtime rotation
+		APIKeys []string `json:"apiKeys,omitempty" yaml:"apiKeys,omitempty"`
+		// Header is the request header carrying the key; defaults to
+		// X-API-Key, with "Authorization: Bearer <key>" always accepted
+		Header string `json:"header,omitempty" yaml:"header,omitempty"`
 	}
 
 	PromptConfig struct {
-		Name        string              `json:"name" yaml:"name"`
-		Description string              `json:"description" yaml:"description"`
-		Arguments   []PromptArgument    `json:"arguments" yaml:"arguments"`
+		Name           string           `json:"name" yaml:"name"`
+		Description    string           `json:"description" yaml:"description"`
+		Arguments      []PromptArgument `json:"arguments" yaml:"arguments"`
 		PromptResponse []PromptResponse `json:"promptResponse,omitempty" yaml:"promptResponse,omitempty"`
 	}
 
@@ -146,6 +390,25 @@ type (
 		Type string `json:"type" yaml:"type"`
 		Text string `json:"text" yaml:"text"`
 	}
+
+	// ResourceConfig defines a static resource served for a prefix
+	ResourceConfig struct {
+		URI         string `json:"uri" yaml:"uri"`
+		Name        string `json:"name" yaml:"name"`
+		Description string `json:"description,omitempty" yaml:"description,omitempty"`
+		MimeType    string `json:"mimeType,omitempty" yaml:"mimeType,omitempty"`
+		Text        string `json:"text,omitempty" yaml:"text,omitempty"` // inline text content, rendered through the template engine
+	}
+
+	// ResourceTemplateConfig defines a templated resource whose URI contains
+	// {variable} placeholders that are exposed to the content template as args
+	ResourceTemplateConfig struct {
+		URITemplate string `json:"uriTemplate" yaml:"uriTemplate"`
+		Name        string `json:"name" yaml:"name"`
+		Description string `json:"description,omitempty" yaml:"description,omitempty"`
+		MimeType    string `json:"mimeType,omitempty" yaml:"mimeType,omitempty"`
+		Text        string `json:"text,omitempty" yaml:"text,omitempty"` // content template rendered with the extracted URI variables
+	}
 )
 
 // ToToolSchema converts a ToolConfig to a ToolSchema
@@ -194,6 +457,28 @@ func (t *ToolConfig) ToToolSchema() mcp.ToolSchema {
 			Properties: properties,
 			Required:   required,
 		},
+		OutputSchema: t.OutputSchema,
+		Annotations:  t.Annotations,
+	}
+}
+
+// ToResourceSchema converts a ResourceConfig to a ResourceSchema
+func (r *ResourceConfig) ToResourceSchema() mcp.ResourceSchema {
+	return mcp.ResourceSchema{
+		URI:         r.URI,
+		Name:        r.Name,
+		Description: r.Description,
+		MimeType:    r.MimeType,
+	}
+}
+
+// ToResourceTemplateSchema converts a ResourceTemplateConfig to a ResourceTemplateSchema
+func (r *ResourceTemplateConfig) ToResourceTemplateSchema() mcp.ResourceTemplateSchema {
+	return mcp.ResourceTemplateSchema{
+		URITemplate: r.URITemplate,
+		Name:        r.Name,
+		Description: r.Description,
+		MimeType:    r.MimeType,
 	}
 }
 
@@ -218,9 +503,9 @@ func (t *PromptConfig) ToPromptSchema() mcp.PromptSchema {
 		})
 	}
 	return mcp.PromptSchema{
-		Name:          t.Name,
-		Description:   t.Description,
-		Arguments:     args,
+		Name:           t.Name,
+		Description:    t.Description,
+		Arguments:      args,
 		PromptResponse: responses,
 	}
-}
\ No newline at end of file
+}
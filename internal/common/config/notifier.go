@@ -3,11 +3,27 @@ package config
 type (
 	// NotifierConfig represents the configuration for notifier
 	NotifierConfig struct {
-		Role   string       `yaml:"role"` // receiver, sender, or both
-		Type   string       `yaml:"type"`
-		Signal SignalConfig `yaml:"signal"`
-		API    APIConfig    `yaml:"api"`
-		Redis  RedisConfig  `yaml:"redis"`
+		Role       string                  `yaml:"role"` // receiver, sender, or both
+		Type       string                  `yaml:"type"`
+		Signal     SignalConfig            `yaml:"signal"`
+		API        APIConfig               `yaml:"api"`
+		Redis      RedisConfig             `yaml:"redis"`
+		Kubernetes KubernetesStorageConfig `yaml:"kubernetes"` // for the kubernetes watch notifier
+		Composite  CompositeConfig         `yaml:"composite"`  // for the composite notifier
+		Fsnotify   FsnotifyConfig          `yaml:"fsnotify"`   // for the file watcher notifier
+	}
+
+	// FsnotifyConfig watches a config directory for changes
+	FsnotifyConfig struct {
+		// Path defaults to the disk storage path when empty
+		Path string `yaml:"path"`
+	}
+
+	// CompositeConfig selects which notifiers a composite combines; the
+	// legacy default (signal + api + redis when configured) applies when
+	// Types is empty
+	CompositeConfig struct {
+		Types []string `yaml:"types"`
 	}
 
 	// SignalConfig represents the configuration for signal-based notifier
@@ -4,11 +4,13 @@ import "time"
 
 type (
 	StorageConfig struct {
-		Type                 string            `yaml:"type"`                   // disk or db
-		RevisionHistoryLimit int               `yaml:"revision_history_limit"` // number of versions to keep
-		Database             DatabaseConfig    `yaml:"database"`               // database configuration for db type
-		Disk                 DiskStorageConfig `yaml:"disk"`                   // disk configuration for disk type
-		API                  APIStorageConfig  `yaml:"api"`                    // disk configuration for api type
+		Type                 string                  `yaml:"type"`                   // disk, db, api or git
+		RevisionHistoryLimit int                     `yaml:"revision_history_limit"` // number of versions to keep
+		Database             DatabaseConfig          `yaml:"database"`               // database configuration for db type
+		Disk                 DiskStorageConfig       `yaml:"disk"`                   // disk configuration for disk type
+		API                  APIStorageConfig        `yaml:"api"`                    // disk configuration for api type
+		Git                  GitStorageConfig        `yaml:"git"`                    // git configuration for git type
+		Kubernetes           KubernetesStorageConfig `yaml:"kubernetes"`             // kubernetes configuration for kubernetes type
 	}
 
 	DiskStorageConfig struct {
@@ -20,4 +22,20 @@ type (
 		ConfigJSONPath string        `yaml:"configJSONPath"` // configJSONPath for config in http response
 		Timeout        time.Duration `yaml:"timeout"`        // timeout for http request
 	}
+
+	KubernetesStorageConfig struct {
+		Namespace     string `yaml:"namespace"`      // namespace holding the ConfigMaps; default "default"
+		LabelSelector string `yaml:"label_selector"` // selects the ConfigMaps carrying MCP configs
+		APIServer     string `yaml:"api_server"`     // API server URL; in-cluster env when empty
+		TokenFile     string `yaml:"token_file"`     // bearer token file; in-cluster path when empty
+		CAFile        string `yaml:"ca_file"`        // CA bundle file; in-cluster path when empty
+	}
+
+	GitStorageConfig struct {
+		URL          string        `yaml:"url"`           // repository to clone
+		Branch       string        `yaml:"branch"`        // branch to track; default branch when empty
+		Path         string        `yaml:"path"`          // subdirectory inside the repository holding the configs
+		LocalDir     string        `yaml:"local_dir"`     // checkout location; a temp directory when empty
+		PullInterval time.Duration `yaml:"pull_interval"` // how often to pull; 0 disables periodic pulls
+	}
 )
@@ -160,6 +160,27 @@ func ValidateMCPConfigs(configs []*MCPConfig) error {
 		}
 	}
 
+	// Check for duplicate virtual hosts (global check); two routers
+	// claiming the same host would shadow each other at routing time
+	hostMap := make(map[string][]Location)
+	for _, cfg := range configs {
+		for _, router := range cfg.Routers {
+			for _, host := range router.Hosts {
+				hostMap[host] = append(hostMap[host], Location{
+					File: cfg.Name,
+				})
+			}
+		}
+	}
+	for host, locations := range hostMap {
+		if len(locations) > 1 {
+			errors = append(errors, &ValidationError{
+				Message:   fmt.Sprintf("duplicate host %q found in router configurations", host),
+				Locations: locations,
+			})
+		}
+	}
+
 	// Validate each config individually
 	for _, cfg := range configs {
 		errors = append(errors, validateSingleConfig(cfg)...)
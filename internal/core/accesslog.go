@@ -0,0 +1,81 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"math/rand"
+	"os"
+	"time"
+
+	"github.com/amoylab/unla/internal/common/config"
+	"github.com/amoylab/unla/pkg/mcp"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// newAccessLogger builds the dedicated JSON access logger
+func newAccessLogger(cfg *config.AccessLogConfig) (*zap.Logger, error) {
+	syncer := zapcore.AddSync(os.Stdout)
+	if cfg.FilePath != "" {
+		file, err := os.OpenFile(cfg.FilePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return nil, err
+		}
+		syncer = zapcore.AddSync(file)
+	}
+
+	encoderCfg := zap.NewProductionEncoderConfig()
+	encoderCfg.TimeKey = "ts"
+	core := zapcore.NewCore(zapcore.NewJSONEncoder(encoderCfg), syncer, zapcore.InfoLevel)
+	return zap.New(core), nil
+}
+
+// accessLogMiddleware records one JSON line per MCP request: prefix, session,
+// JSON-RPC method, tool name, duration, status and response size
+func (s *Server) accessLogMiddleware() gin.HandlerFunc {
+	sampleRate := s.cfg.AccessLog.SampleRate
+	return func(c *gin.Context) {
+		if sampleRate > 0 && sampleRate < 1 && rand.Float64() >= sampleRate {
+			c.Next()
+			return
+		}
+
+		// Peek the JSON-RPC envelope without consuming the body
+		var rpcMethod, toolName string
+		if c.Request.Body != nil && c.Request.Method == "POST" {
+			body, err := io.ReadAll(c.Request.Body)
+			if err == nil {
+				c.Request.Body = io.NopCloser(bytes.NewReader(body))
+				var req struct {
+					Method string `json:"method"`
+					Params struct {
+						Name string `json:"name"`
+					} `json:"params"`
+				}
+				if json.Unmarshal(body, &req) == nil {
+					rpcMethod = req.Method
+					if req.Method == mcp.ToolsCall {
+						toolName = req.Params.Name
+					}
+				}
+			}
+		}
+
+		start := time.Now()
+		c.Next()
+
+		s.accessLog.Info("access",
+			zap.String("path", c.Request.URL.Path),
+			zap.String("method", c.Request.Method),
+			zap.String("rpc_method", rpcMethod),
+			zap.String("tool", toolName),
+			zap.String("session_id", c.Writer.Header().Get(mcp.HeaderMcpSessionID)),
+			zap.String("remote_addr", c.ClientIP()),
+			zap.Int("status", c.Writer.Status()),
+			zap.Int("bytes", c.Writer.Size()),
+			zap.Duration("duration", time.Since(start)))
+	}
+}
@@ -0,0 +1,332 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/amoylab/unla/internal/mcp/session"
+	"github.com/amoylab/unla/pkg/logger"
+	"github.com/amoylab/unla/pkg/mcp"
+	"github.com/amoylab/unla/pkg/version"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// startAdminServer serves the inner admin endpoints (health and readiness
+// probes) on the configured admin port
+func (s *Server) startAdminServer() {
+	if s.cfg.AdminPort <= 0 {
+		return
+	}
+
+	router := gin.New()
+	router.Use(gin.Recovery())
+	s.registerAdminRoutes(router)
+
+	go func() {
+		host := s.cfg.AdminHost
+		if host == "" {
+			host = "127.0.0.1"
+		}
+		addr := fmt.Sprintf("%s:%d", host, s.cfg.AdminPort)
+		s.logger.Info("starting admin server", zap.String("addr", addr))
+		if err := router.Run(addr); err != nil {
+			s.logger.Error("failed to start admin server", zap.Error(err))
+		}
+	}()
+}
+
+// registerAdminRoutes registers the inner-port endpoints; every mutating
+// endpoint goes through the admin token check
+func (s *Server) registerAdminRoutes(router *gin.Engine) {
+	router.GET("/healthz", s.handleHealthz)
+	router.GET("/readyz", s.handleReadyz)
+	router.GET("/sessions", s.handleListSessions)
+	router.GET("/loglevel", s.handleGetLogLevel)
+	router.GET("/backends", s.handleBackendHealth)
+	router.GET("/status", s.handleStatus)
+	router.GET("/metrics", s.handleMetrics)
+
+	mutating := router.Group("", s.adminAuth())
+	mutating.DELETE("/sessions/:id", s.handleTerminateSession)
+	mutating.DELETE("/sessions", s.handleTerminateSessionsByPrefix)
+	mutating.POST("/broadcast", s.handleBroadcast)
+	mutating.PUT("/loglevel", s.handleSetLogLevel)
+	mutating.POST("/reload", s.handleAdminReload)
+}
+
+// adminAuth requires the configured admin token as a bearer token on
+// mutating endpoints; without a configured token only the loopback bind
+// protects them
+func (s *Server) adminAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token := s.cfg.AdminToken; token != "" {
+			if c.GetHeader("Authorization") != "Bearer "+token {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+				return
+			}
+		}
+		c.Next()
+	}
+}
+
+// handleAdminReload triggers a configuration reload; this is the
+// cross-platform alternative to SIGHUP (e.g. on Windows)
+func (s *Server) handleAdminReload(c *gin.Context) {
+	s.ReloadConfigs(c.Request.Context())
+	c.JSON(http.StatusOK, gin.H{
+		"status":         "reloaded",
+		"last_reload_ok": s.lastReloadOK,
+	})
+}
+
+// handleMetrics exposes gauges in the Prometheus text format. The counts
+// are cheap enough to gather per scrape, so no client library is needed.
+func (s *Server) handleMetrics(c *gin.Context) {
+	sessionCount := 0
+	if counter, ok := s.sessions.(interface{ Count() int }); ok {
+		sessionCount = counter.Count()
+	} else if conns, err := s.sessions.List(c.Request.Context()); err == nil {
+		sessionCount = len(conns)
+	}
+	state := s.currentState()
+
+	var sb strings.Builder
+	writeGauge := func(name, help string, value int) {
+		fmt.Fprintf(&sb, "# HELP %s %s\n# TYPE %s gauge\n%s %d\n", name, help, name, name, value)
+	}
+	writeGauge("unla_sessions", "Number of live sessions.", sessionCount)
+	writeGauge("unla_servers", "Number of configured servers.", state.GetServerCount())
+	writeGauge("unla_tools", "Number of configured tools.", state.GetToolCount())
+	writeGauge("unla_routers", "Number of configured routers.", state.GetRouterCount())
+
+	c.Data(http.StatusOK, "text/plain; version=0.0.4; charset=utf-8", []byte(sb.String()))
+}
+
+// handleStatus reports instance information for the status CLI
+func (s *Server) handleStatus(c *gin.Context) {
+	state := s.currentState()
+	conns, _ := s.sessions.List(c.Request.Context())
+
+	status := gin.H{
+		"version":       version.Get(),
+		"uptime":        time.Since(s.startTime).Round(time.Second).String(),
+		"server_count":  state.GetServerCount(),
+		"tool_count":    state.GetToolCount(),
+		"router_count":  state.GetRouterCount(),
+		"session_count": len(conns),
+		"log_level":     logger.Level(),
+	}
+	if !s.lastReloadAt.IsZero() {
+		status["last_reload_at"] = s.lastReloadAt
+		status["last_reload_ok"] = s.lastReloadOK
+	}
+	c.JSON(http.StatusOK, status)
+}
+
+// handleGetLogLevel reports the current runtime log level
+func (s *Server) handleGetLogLevel(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"level": logger.Level()})
+}
+
+// handleSetLogLevel changes the runtime log level
+func (s *Server) handleSetLogLevel(c *gin.Context) {
+	var req struct {
+		Level string `json:"level" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := logger.SetLevel(req.Level); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	s.logger.Info("log level changed", zap.String("level", req.Level))
+	c.JSON(http.StatusOK, gin.H{"level": req.Level})
+}
+
+// handleTerminateSession force-closes one session
+func (s *Server) handleTerminateSession(c *gin.Context) {
+	id := c.Param("id")
+	if err := s.sessions.Unregister(c.Request.Context(), id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	s.stats.delete(id)
+	s.clientRoots.delete(id)
+	s.logger.Info("session terminated by admin", zap.String("session_id", id))
+	c.JSON(http.StatusOK, gin.H{"status": "terminated"})
+}
+
+// handleTerminateSessionsByPrefix force-closes every session on a prefix
+func (s *Server) handleTerminateSessionsByPrefix(c *gin.Context) {
+	prefix := c.Query("prefix")
+	if prefix == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "prefix query parameter is required"})
+		return
+	}
+
+	conns, err := s.sessions.List(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	terminated := 0
+	for _, conn := range conns {
+		if conn.Meta().Prefix != prefix {
+			continue
+		}
+		id := conn.Meta().ID
+		if err := s.sessions.Unregister(c.Request.Context(), id); err != nil {
+			s.logger.Warn("failed to terminate session",
+				zap.String("session_id", id),
+				zap.Error(err))
+			continue
+		}
+		s.stats.delete(id)
+		s.clientRoots.delete(id)
+		terminated++
+	}
+	s.logger.Info("sessions terminated by admin",
+		zap.String("prefix", prefix),
+		zap.Int("count", terminated))
+	c.JSON(http.StatusOK, gin.H{"terminated": terminated})
+}
+
+// handleBroadcast sends an administrative notice to all connected sessions
+// (optionally only those on ?prefix=)
+func (s *Server) handleBroadcast(c *gin.Context) {
+	var req struct {
+		Message string `json:"message" binding:"required"`
+		Level   string `json:"level"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Level == "" {
+		req.Level = "warning"
+	}
+
+	data, err := json.Marshal(struct {
+		JSONRPC string `json:"jsonrpc"`
+		Method  string `json:"method"`
+		Params  any    `json:"params"`
+	}{
+		JSONRPC: mcp.JSPNRPCVersion,
+		Method:  mcp.NotificationMessage,
+		Params: map[string]any{
+			"level":  req.Level,
+			"logger": "gateway",
+			"data":   req.Message,
+		},
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	conns, err := s.sessions.List(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	prefix := c.Query("prefix")
+	notified := 0
+	for _, conn := range conns {
+		if prefix != "" && conn.Meta().Prefix != prefix {
+			continue
+		}
+		if err := conn.Send(c.Request.Context(), &session.Message{Event: "message", Data: data}); err != nil {
+			continue
+		}
+		notified++
+	}
+	c.JSON(http.StatusOK, gin.H{"notified": notified})
+}
+
+// handleListSessions lists active sessions with metadata and activity
+// counters; optional ?prefix= filters by routed prefix
+func (s *Server) handleListSessions(c *gin.Context) {
+	conns, err := s.sessions.List(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	prefixFilter := c.Query("prefix")
+	sessions := make([]gin.H, 0, len(conns))
+	for _, conn := range conns {
+		meta := conn.Meta()
+		if prefixFilter != "" && meta.Prefix != prefixFilter {
+			continue
+		}
+		stat := s.stats.get(meta.ID)
+		sessions = append(sessions, gin.H{
+			"id":             meta.ID,
+			"prefix":         meta.Prefix,
+			"type":           meta.Type,
+			"created_at":     meta.CreatedAt,
+			"remote_addr":    meta.RemoteAddr,
+			"client_name":    meta.ClientName,
+			"client_version": meta.ClientVersion,
+			"tool_calls":     stat.ToolCalls,
+			"last_activity":  stat.LastActivity,
+		})
+	}
+	c.JSON(http.StatusOK, gin.H{"sessions": sessions})
+}
+
+// handleHealthz reports process liveness
+func (s *Server) handleHealthz(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// handleReadyz reports whether the gateway can serve traffic: storage and
+// session store reachable and at least the current config state loaded
+func (s *Server) handleReadyz(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	status := http.StatusOK
+	result := gin.H{
+		"status":   "ok",
+		"draining": s.draining.Load(),
+	}
+
+	if _, err := s.store.List(ctx); err != nil {
+		status = http.StatusServiceUnavailable
+		result["status"] = "unavailable"
+		result["storage"] = err.Error()
+	} else {
+		result["storage"] = "ok"
+	}
+
+	if _, err := s.sessions.List(ctx); err != nil {
+		status = http.StatusServiceUnavailable
+		result["status"] = "unavailable"
+		result["session_store"] = err.Error()
+	} else {
+		result["session_store"] = "ok"
+	}
+
+	if s.draining.Load() {
+		status = http.StatusServiceUnavailable
+		result["status"] = "draining"
+	}
+
+	state := s.currentState()
+	result["server_count"] = state.GetServerCount()
+	result["tool_count"] = state.GetToolCount()
+	result["router_count"] = state.GetRouterCount()
+
+	c.JSON(status, result)
+}
@@ -0,0 +1,144 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/amoylab/unla/internal/common/config"
+
+	"go.uber.org/zap"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// builtinSensitiveFields are always redacted from audited arguments
+var builtinSensitiveFields = []string{"password", "secret", "token", "apikey", "api_key", "authorization"}
+
+// AuditEntry is one durable record of a tool invocation
+type AuditEntry struct {
+	Time      time.Time      `json:"time"`
+	SessionID string         `json:"session_id"`
+	Prefix    string         `json:"prefix"`
+	Tool      string         `json:"tool"`
+	Caller    string         `json:"caller,omitempty"`
+	Arguments map[string]any `json:"arguments,omitempty"`
+	Status    string         `json:"status"` // "ok" or "error"
+	Error     string         `json:"error,omitempty"`
+	Duration  time.Duration  `json:"duration_ns"`
+}
+
+// AuditSink persists audit entries
+type AuditSink interface {
+	Record(ctx context.Context, entry *AuditEntry) error
+	Close() error
+}
+
+// fileAuditSink writes JSON lines with retention-based rotation
+type fileAuditSink struct {
+	writer *lumberjack.Logger
+}
+
+var _ AuditSink = (*fileAuditSink)(nil)
+
+// newFileAuditSink creates the default file-based audit sink
+func newFileAuditSink(cfg *config.AuditConfig) *fileAuditSink {
+	path := cfg.FilePath
+	if path == "" {
+		path = "./audit.log"
+	}
+	return &fileAuditSink{
+		writer: &lumberjack.Logger{
+			Filename: path,
+			MaxSize:  100, // MB
+			MaxAge:   cfg.RetentionDays,
+		},
+	}
+}
+
+// Record implements AuditSink.Record
+func (s *fileAuditSink) Record(_ context.Context, entry *AuditEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	_, err = s.writer.Write(append(data, '\n'))
+	return err
+}
+
+// Close implements AuditSink.Close
+func (s *fileAuditSink) Close() error {
+	return s.writer.Close()
+}
+
+// redactArguments masks sensitive argument values before they are persisted
+func redactArguments(args map[string]any, extraFields []string) map[string]any {
+	if args == nil {
+		return nil
+	}
+
+	sensitive := func(key string) bool {
+		lower := strings.ToLower(key)
+		for _, field := range builtinSensitiveFields {
+			if strings.Contains(lower, field) {
+				return true
+			}
+		}
+		for _, field := range extraFields {
+			if strings.EqualFold(key, field) {
+				return true
+			}
+		}
+		return false
+	}
+
+	redacted := make(map[string]any, len(args))
+	for k, v := range args {
+		if sensitive(k) {
+			redacted[k] = "[REDACTED]"
+		} else {
+			redacted[k] = v
+		}
+	}
+	return redacted
+}
+
+// redactionFieldsFor combines the audit-level and per-tool redaction rules
+func (s *Server) redactionFieldsFor(prefix, tool string) []string {
+	fields := s.cfg.Audit.RedactedFields
+	if toolCfg := s.currentState().GetTool(prefix, tool); toolCfg != nil && len(toolCfg.RedactedArgs) > 0 {
+		fields = append(append([]string{}, fields...), toolCfg.RedactedArgs...)
+	}
+	return fields
+}
+
+// auditToolCall records a completed tool invocation to the audit sink
+func (s *Server) auditToolCall(ctx context.Context, sessionID, prefix, tool, caller string, rawArgs json.RawMessage, callErr error, duration time.Duration) {
+	if s.auditSink == nil {
+		return
+	}
+
+	var args map[string]any
+	_ = json.Unmarshal(rawArgs, &args)
+
+	entry := &AuditEntry{
+		Time:      time.Now(),
+		SessionID: sessionID,
+		Prefix:    prefix,
+		Tool:      tool,
+		Caller:    caller,
+		Arguments: redactArguments(args, s.redactionFieldsFor(prefix, tool)),
+		Status:    "ok",
+		Duration:  duration,
+	}
+	if callErr != nil {
+		entry.Status = "error"
+		entry.Error = callErr.Error()
+	}
+
+	if err := s.auditSink.Record(ctx, entry); err != nil {
+		s.logger.Warn("failed to record audit entry",
+			zap.String("tool", tool),
+			zap.Error(err))
+	}
+}
@@ -1,14 +1,86 @@
 package core
 
 import (
+	"crypto/subtle"
+	"encoding/json"
 	"net/http"
 	"net/url"
 	"strings"
 
-	"github.com/gin-gonic/gin"
+	"github.com/amoylab/unla/internal/common/config"
 	"github.com/amoylab/unla/internal/common/errorx"
+	"github.com/gin-gonic/gin"
 )
 
+// isValidAPIKey checks the request against the prefix's configured key set.
+// The key is read from the configured header (X-API-Key by default) or from
+// an Authorization bearer token.
+func isValidAPIKey(auth *config.Auth, req *http.Request) bool {
+	header := auth.Header
+	if header == "" {
+		header = "X-API-Key"
+	}
+
+	key := req.Header.Get(header)
+	if key == "" {
+		if authHeader := req.Header.Get("Authorization"); strings.HasPrefix(authHeader, "Bearer ") {
+			key = strings.TrimPrefix(authHeader, "Bearer ")
+		}
+	}
+	if key == "" {
+		return false
+	}
+
+	for _, candidate := range auth.APIKeys {
+		if subtle.ConstantTimeCompare([]byte(candidate), []byte(key)) == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// validateOIDCToken validates the bearer token on prefixes with auth mode
+// oidc. On success the validated claims are injected as request headers so
+// templates can forward user identity to backends; on failure a 401 is sent
+// and false is returned.
+func (s *Server) validateOIDCToken(c *gin.Context) bool {
+	unauthorized := func(description string) bool {
+		c.Header("WWW-Authenticate", `Bearer realm="OIDC", error="invalid_token", error_description="`+description+`"`)
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":             "invalid_token",
+			"error_description": description,
+		})
+		return false
+	}
+
+	// Never trust identity headers supplied by the client
+	c.Request.Header.Del("X-Jwt-Subject")
+	c.Request.Header.Del("X-Jwt-Claims")
+
+	if s.jwtValidator == nil {
+		return unauthorized("OIDC validation is not configured")
+	}
+
+	authHeader := c.GetHeader("Authorization")
+	if !strings.HasPrefix(authHeader, "Bearer ") {
+		return unauthorized("Missing bearer token")
+	}
+
+	claims, err := s.jwtValidator.Validate(strings.TrimPrefix(authHeader, "Bearer "))
+	if err != nil {
+		return unauthorized("Invalid or expired token")
+	}
+
+	// Expose validated identity to the template context via request headers
+	if sub, err := claims.GetSubject(); err == nil && sub != "" {
+		c.Request.Header.Set("X-Jwt-Subject", sub)
+	}
+	if claimsJSON, err := json.Marshal(claims); err == nil {
+		c.Request.Header.Set("X-Jwt-Claims", string(claimsJSON))
+	}
+	return true
+}
+
 // handleOAuthServerMetadata handles the OAuth server metadata endpoint
 func (s *Server) handleOAuthServerMetadata(c *gin.Context) {
 	metadata := s.auth.ServerMetadata(c.Request)
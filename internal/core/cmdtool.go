@@ -0,0 +1,104 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"time"
+
+	"github.com/amoylab/unla/internal/common/config"
+	"github.com/amoylab/unla/internal/template"
+	"github.com/amoylab/unla/pkg/mcp"
+)
+
+const (
+	defaultCommandTimeout   = 30 * time.Second
+	defaultCommandMaxOutput = 1 << 20 // 1MiB
+)
+
+// invokeCommandTool runs an allow-listed local binary with templated
+// arguments and environment, bounded by a timeout and an output cap
+func invokeCommandTool(ctx context.Context, tool *config.ToolConfig, args map[string]any, allowedBinaries []string, restricted bool) (*mcp.CallToolResult, error) {
+	cfg := tool.Command
+
+	allowed := false
+	for _, binary := range allowedBinaries {
+		if binary == cfg.Binary {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return nil, fmt.Errorf("binary %q is not in the command tool allowlist", cfg.Binary)
+	}
+
+	tmplCtx := template.NewContext()
+	tmplCtx.Args = args
+	tmplCtx.Restricted = restricted
+
+	cmdArgs := make([]string, len(cfg.Args))
+	for i, argTmpl := range cfg.Args {
+		rendered, err := template.RenderTemplate(argTmpl, tmplCtx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render command argument: %w", err)
+		}
+		cmdArgs[i] = rendered
+	}
+
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = defaultCommandTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, cfg.Binary, cmdArgs...)
+	cmd.Dir = cfg.WorkDir
+	for k, v := range cfg.Env {
+		rendered, err := template.RenderTemplate(v, tmplCtx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render command env: %w", err)
+		}
+		cmd.Env = append(cmd.Env, k+"="+rendered)
+	}
+
+	maxOutput := cfg.MaxOutputBytes
+	if maxOutput <= 0 {
+		maxOutput = defaultCommandMaxOutput
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &limitedWriter{buf: &stdout, limit: maxOutput}
+	cmd.Stderr = &limitedWriter{buf: &stderr, limit: maxOutput}
+
+	err := cmd.Run()
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, fmt.Errorf("command timed out after %s", timeout)
+	}
+	if err != nil {
+		return mcp.NewCallToolResultError(fmt.Sprintf("command failed: %v\n%s", err, stderr.String())), nil
+	}
+
+	return mcp.NewCallToolResultText(stdout.String()), nil
+}
+
+// limitedWriter discards everything beyond the configured limit
+type limitedWriter struct {
+	buf     *bytes.Buffer
+	limit   int64
+	written int64
+}
+
+func (w *limitedWriter) Write(p []byte) (int, error) {
+	remaining := w.limit - w.written
+	if remaining > 0 {
+		chunk := p
+		if int64(len(chunk)) > remaining {
+			chunk = chunk[:remaining]
+		}
+		w.buf.Write(chunk)
+		w.written += int64(len(chunk))
+	}
+	// Report full consumption so the child process is never blocked
+	return len(p), nil
+}
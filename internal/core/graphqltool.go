@@ -0,0 +1,65 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/amoylab/unla/internal/common/config"
+	"github.com/amoylab/unla/pkg/mcp"
+)
+
+var graphqlHTTPClient = &http.Client{Timeout: 30 * time.Second}
+
+// invokeGraphQLTool executes the configured query/mutation with the tool
+// arguments as variables and converts GraphQL errors into IsError results
+func invokeGraphQLTool(ctx context.Context, tool *config.ToolConfig, args map[string]any) (*mcp.CallToolResult, error) {
+	cfg := tool.GraphQL
+
+	payload, err := json.Marshal(map[string]any{
+		"query":     cfg.Query,
+		"variables": args,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode GraphQL request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, cfg.Endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GraphQL request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range cfg.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := graphqlHTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("GraphQL request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode GraphQL response: %w", err)
+	}
+
+	if len(result.Errors) > 0 {
+		messages := make([]string, len(result.Errors))
+		for i, gqlErr := range result.Errors {
+			messages[i] = gqlErr.Message
+		}
+		data, _ := json.Marshal(messages)
+		return mcp.NewCallToolResultError(fmt.Sprintf("GraphQL errors: %s", data)), nil
+	}
+
+	return mcp.NewCallToolResultText(string(result.Data)), nil
+}
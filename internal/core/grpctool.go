@@ -0,0 +1,176 @@
+package core
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/amoylab/unla/internal/common/config"
+	"github.com/amoylab/unla/pkg/mcp"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	reflectpb "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// grpcMethodCache caches resolved method descriptors per target+method
+var grpcMethodCache sync.Map
+
+// grpcConnCache reuses client connections per target+credential mode
+var grpcConnCache sync.Map
+
+// grpcConn returns a cached client connection for the target
+func grpcConn(cfg *config.GRPCToolConfig) (*grpc.ClientConn, error) {
+	cacheKey := fmt.Sprintf("%s|%t", cfg.Target, cfg.Plaintext)
+	if cached, ok := grpcConnCache.Load(cacheKey); ok {
+		return cached.(*grpc.ClientConn), nil
+	}
+
+	creds := credentials.NewTLS(&tls.Config{})
+	if cfg.Plaintext {
+		creds = insecure.NewCredentials()
+	}
+	conn, err := grpc.NewClient(cfg.Target, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to gRPC target: %w", err)
+	}
+
+	if cached, loaded := grpcConnCache.LoadOrStore(cacheKey, conn); loaded {
+		_ = conn.Close()
+		return cached.(*grpc.ClientConn), nil
+	}
+	return conn, nil
+}
+
+// invokeGRPCTool calls a gRPC method resolved via server reflection, mapping
+// the tool arguments onto the request message and returning the response as
+// JSON text
+func invokeGRPCTool(ctx context.Context, tool *config.ToolConfig, args map[string]any) (*mcp.CallToolResult, error) {
+	cfg := tool.GRPC
+
+	conn, err := grpcConn(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	method, err := resolveGRPCMethod(ctx, conn, cfg.Target, cfg.Method)
+	if err != nil {
+		return nil, err
+	}
+
+	reqMsg := dynamicpb.NewMessage(method.Input())
+	argsJSON, err := json.Marshal(args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode tool arguments: %w", err)
+	}
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(argsJSON, reqMsg); err != nil {
+		return nil, fmt.Errorf("failed to map arguments onto request message: %w", err)
+	}
+
+	respMsg := dynamicpb.NewMessage(method.Output())
+	fullMethod := "/" + strings.Trim(cfg.Method, "/")
+	if err := conn.Invoke(ctx, fullMethod, reqMsg, respMsg); err != nil {
+		return nil, fmt.Errorf("gRPC call failed: %w", err)
+	}
+
+	respJSON, err := protojson.Marshal(respMsg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode gRPC response: %w", err)
+	}
+	return mcp.NewCallToolResultText(string(respJSON)), nil
+}
+
+// resolveGRPCMethod looks up the method descriptor through server reflection,
+// caching the result per target
+func resolveGRPCMethod(ctx context.Context, conn *grpc.ClientConn, target, method string) (protoreflect.MethodDescriptor, error) {
+	cacheKey := target + "|" + method
+	if cached, ok := grpcMethodCache.Load(cacheKey); ok {
+		return cached.(protoreflect.MethodDescriptor), nil
+	}
+
+	name := strings.Trim(method, "/")
+	slash := strings.LastIndex(name, "/")
+	if slash < 0 {
+		return nil, fmt.Errorf("invalid gRPC method %q, want service/method", method)
+	}
+	serviceName, methodName := name[:slash], name[slash+1:]
+
+	files, err := fetchDescriptors(ctx, conn, serviceName)
+	if err != nil {
+		return nil, err
+	}
+
+	desc, err := files.FindDescriptorByName(protoreflect.FullName(serviceName))
+	if err != nil {
+		return nil, fmt.Errorf("service %q not found via reflection: %w", serviceName, err)
+	}
+	service, ok := desc.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("%q is not a service", serviceName)
+	}
+	methodDesc := service.Methods().ByName(protoreflect.Name(methodName))
+	if methodDesc == nil {
+		return nil, fmt.Errorf("method %q not found on service %q", methodName, serviceName)
+	}
+	if methodDesc.IsStreamingClient() || methodDesc.IsStreamingServer() {
+		return nil, fmt.Errorf("streaming method %q is not supported", method)
+	}
+
+	grpcMethodCache.Store(cacheKey, methodDesc)
+	return methodDesc, nil
+}
+
+// fetchDescriptors retrieves the file descriptors containing a symbol via
+// the server reflection service
+func fetchDescriptors(ctx context.Context, conn *grpc.ClientConn, symbol string) (*protoregistry.Files, error) {
+	client := reflectpb.NewServerReflectionClient(conn)
+	stream, err := client.ServerReflectionInfo(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open reflection stream: %w", err)
+	}
+	defer stream.CloseSend()
+
+	if err := stream.Send(&reflectpb.ServerReflectionRequest{
+		MessageRequest: &reflectpb.ServerReflectionRequest_FileContainingSymbol{
+			FileContainingSymbol: symbol,
+		},
+	}); err != nil {
+		return nil, fmt.Errorf("reflection request failed: %w", err)
+	}
+
+	resp, err := stream.Recv()
+	if err != nil && err != io.EOF {
+		return nil, fmt.Errorf("reflection response failed: %w", err)
+	}
+	fdResp := resp.GetFileDescriptorResponse()
+	if fdResp == nil {
+		return nil, fmt.Errorf("reflection returned no descriptors for %q", symbol)
+	}
+
+	fdSet := &descriptorpb.FileDescriptorSet{}
+	for _, raw := range fdResp.FileDescriptorProto {
+		fd := &descriptorpb.FileDescriptorProto{}
+		if err := proto.Unmarshal(raw, fd); err != nil {
+			return nil, fmt.Errorf("failed to parse file descriptor: %w", err)
+		}
+		fdSet.File = append(fdSet.File, fd)
+	}
+
+	files, err := protodesc.NewFiles(fdSet)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build descriptor registry: %w", err)
+	}
+	return files, nil
+}
@@ -70,10 +70,27 @@ func (h *TextHandler) Handle(resp *http.Response, tool *config.ToolConfig, tmplC
 		// the text handler is the last handler in the chain
 		return nil, fmt.Errorf("response type cannot be handled")
 	}
+
+	// Map configured status codes to friendly error results
+	if tool.Transform != nil {
+		if message, ok := tool.Transform.StatusErrors[resp.StatusCode]; ok {
+			return mcp.NewCallToolResultError(message), nil
+		}
+	}
+
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
+
+	// Extract the configured field before templating so templates and the
+	// raw fallback both see the trimmed payload
+	if tool.Transform != nil && tool.Transform.ExtractPath != "" {
+		if extracted, ok := extractJSONPath(respBody, tool.Transform.ExtractPath); ok {
+			respBody = extracted
+		}
+	}
+
 	var rendered string
 	if tool.ResponseBody == "" {
 		rendered = ""
@@ -86,13 +103,75 @@ func (h *TextHandler) Handle(resp *http.Response, tool *config.ToolConfig, tmplC
 		respData = preprocessResponseData(respData)
 		tmplCtx.Response.Data = respData
 		tmplCtx.Response.Body = string(respBody)
+		tmplCtx.Response.StatusCode = resp.StatusCode
+		tmplCtx.Response.Headers = make(map[string]string, len(resp.Header))
+		for name, values := range resp.Header {
+			if len(values) > 0 {
+				tmplCtx.Response.Headers[name] = values[0]
+			}
+		}
 
 		rendered, err = template.RenderTemplate(tool.ResponseBody, tmplCtx)
 		if err != nil {
 			return nil, fmt.Errorf("failed to render response body template: %w", err)
 		}
 	}
-	return mcp.NewCallToolResultText(rendered), nil
+
+	if tool.Transform != nil && tool.Transform.MaxBytes > 0 && len(rendered) > tool.Transform.MaxBytes {
+		rendered = rendered[:tool.Transform.MaxBytes] + "... [truncated]"
+	}
+
+	result := mcp.NewCallToolResultText(rendered)
+
+	// Attach and optionally validate structured content for tools that
+	// declare an output schema
+	if tool.OutputSchema != nil && tool.OutputSchemaMode != "off" {
+		source := rendered
+		if source == "" {
+			source = string(respBody)
+		}
+		var structured any
+		if err := json.Unmarshal([]byte(source), &structured); err != nil {
+			if tool.OutputSchemaMode == "strict" {
+				return nil, fmt.Errorf("tool %s declares an outputSchema but returned non-JSON output", tool.Name)
+			}
+		} else {
+			if violations := validateAgainstSchema(structured, tool.OutputSchema, ""); len(violations) > 0 {
+				if tool.OutputSchemaMode == "strict" {
+					return nil, fmt.Errorf("tool %s output does not match its outputSchema: %s",
+						tool.Name, strings.Join(violations, "; "))
+				}
+				// warn mode: keep the result, callers see it in the log
+			}
+			result.StructuredContent = structured
+		}
+	}
+
+	return result, nil
+}
+
+// extractJSONPath selects a nested field from a JSON document by dot path,
+// returning the re-encoded value and whether the path resolved
+func extractJSONPath(body []byte, path string) ([]byte, bool) {
+	var doc any
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return nil, false
+	}
+	for _, segment := range strings.Split(path, ".") {
+		obj, ok := doc.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		doc, ok = obj[segment]
+		if !ok {
+			return nil, false
+		}
+	}
+	extracted, err := json.Marshal(doc)
+	if err != nil {
+		return nil, false
+	}
+	return extracted, true
 }
 
 // ImageHandler is a handler for image responses
@@ -0,0 +1,92 @@
+package core
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// backendStatus is the latest probe result for one prefix's backend
+type backendStatus struct {
+	Healthy     bool      `json:"healthy"`
+	LastChecked time.Time `json:"last_checked"`
+	Error       string    `json:"error,omitempty"`
+}
+
+// backendHealth stores probe results per prefix
+type backendHealth struct {
+	mu     sync.RWMutex
+	status map[string]backendStatus
+}
+
+func newBackendHealth() *backendHealth {
+	return &backendHealth{status: make(map[string]backendStatus)}
+}
+
+func (h *backendHealth) set(prefix string, status backendStatus) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.status[prefix] = status
+}
+
+func (h *backendHealth) snapshot() map[string]backendStatus {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	copied := make(map[string]backendStatus, len(h.status))
+	for prefix, status := range h.status {
+		copied[prefix] = status
+	}
+	return copied
+}
+
+// startHealthChecker probes every proxied backend on the configured
+// interval; disabled when the interval is zero
+func (s *Server) startHealthChecker(ctx context.Context) {
+	interval := s.cfg.HealthCheckInterval
+	if interval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-s.shutdownCh:
+				return
+			case <-ticker.C:
+				s.probeBackends(ctx)
+			}
+		}
+	}()
+}
+
+// probeBackends checks each transport by fetching its tool list with a
+// bounded context
+func (s *Server) probeBackends(ctx context.Context) {
+	for prefix, transport := range s.currentState().GetTransports() {
+		probeCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		_, err := transport.FetchTools(probeCtx)
+		cancel()
+
+		status := backendStatus{Healthy: err == nil, LastChecked: time.Now()}
+		if err != nil {
+			status.Error = err.Error()
+			s.logger.Warn("backend health probe failed",
+				zap.String("prefix", prefix),
+				zap.Error(err))
+		}
+		s.health.set(prefix, status)
+	}
+}
+
+// handleBackendHealth serves the latest probe results
+func (s *Server) handleBackendHealth(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"backends": s.health.snapshot()})
+}
@@ -0,0 +1,97 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/amoylab/unla/internal/common/config"
+	"github.com/amoylab/unla/internal/template"
+	"github.com/amoylab/unla/pkg/mcp"
+)
+
+// InvokeHTTPTool executes an HTTP-backed tool outside the gin server, e.g.
+// from the stdio serving mode or CLI tooling. It runs the same template,
+// argument and response pipeline as the in-server execution path.
+func InvokeHTTPTool(ctx context.Context, tool *config.ToolConfig, serverCfg map[string]string, args map[string]any) (*mcp.CallToolResult, error) {
+	fillDefaultArgs(tool, args)
+
+	// gRPC- and GraphQL-backed tools bypass the HTTP pipeline entirely
+	if tool.GRPC != nil {
+		return invokeGRPCTool(ctx, tool, args)
+	}
+	if tool.GraphQL != nil {
+		return invokeGraphQLTool(ctx, tool, args)
+	}
+	if tool.Database != nil {
+		return invokeSQLTool(ctx, tool, args)
+	}
+
+	template.NormalizeJSONStringValues(args)
+
+	tmplCtx, err := template.AssembleTemplateContext(nil, args, serverCfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare template context: %w", err)
+	}
+
+	req, err := prepareRequest(tool, tmplCtx)
+	if err != nil {
+		return nil, err
+	}
+	processArguments(req, tool, args)
+
+	cli, err := createHTTPClient(tool)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP client: %w", err)
+	}
+
+	if tool.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, tool.Timeout)
+		defer cancel()
+	}
+	req = req.WithContext(ctx)
+
+	resp, err := cli.Do(req)
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("tool call timed out after %s", tool.Timeout)
+		}
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	return CreateResponseHandlerChain().Handle(resp, tool, tmplCtx)
+}
+
+// DescribeHTTPToolRequest renders the outgoing request an HTTP tool would
+// send, without executing it; used by the CLI dry-run mode
+func DescribeHTTPToolRequest(tool *config.ToolConfig, serverCfg map[string]string, args map[string]any) (method, url string, headers map[string]string, body string, err error) {
+	fillDefaultArgs(tool, args)
+	template.NormalizeJSONStringValues(args)
+
+	tmplCtx, err := template.AssembleTemplateContext(nil, args, serverCfg)
+	if err != nil {
+		return "", "", nil, "", err
+	}
+	req, err := prepareRequest(tool, tmplCtx)
+	if err != nil {
+		return "", "", nil, "", err
+	}
+	processArguments(req, tool, args)
+
+	headers = make(map[string]string, len(req.Header))
+	for name, values := range req.Header {
+		if len(values) > 0 {
+			headers[name] = values[0]
+		}
+	}
+	if req.Body != nil {
+		data, readErr := io.ReadAll(req.Body)
+		if readErr == nil {
+			body = string(data)
+		}
+	}
+	return req.Method, req.URL.String(), headers, body, nil
+}
@@ -0,0 +1,66 @@
+package core
+
+import (
+	"net"
+	"net/http"
+
+	"github.com/amoylab/unla/internal/common/config"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// ipMatches reports whether an address is covered by a CIDR or exact entry
+func ipMatches(entry string, ip net.IP) bool {
+	if _, network, err := net.ParseCIDR(entry); err == nil {
+		return network.Contains(ip)
+	}
+	return entry != "" && net.ParseIP(entry) != nil && net.ParseIP(entry).Equal(ip)
+}
+
+// ipAllowed applies an IP filter; deny wins and an empty allow list permits
+// everything not denied
+func ipAllowed(filter *config.IPFilterConfig, ip net.IP) bool {
+	if filter == nil || ip == nil {
+		return true
+	}
+	for _, entry := range filter.Deny {
+		if ipMatches(entry, ip) {
+			return false
+		}
+	}
+	if len(filter.Allow) == 0 {
+		return true
+	}
+	for _, entry := range filter.Allow {
+		if ipMatches(entry, ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkIPFilter enforces the listener-wide filter plus the prefix's own
+// filter; returns false after responding with 403
+func (s *Server) checkIPFilter(c *gin.Context, prefix string) bool {
+	ip := net.ParseIP(c.ClientIP())
+
+	filters := []*config.IPFilterConfig{s.cfg.IPFilter}
+	if router := s.currentState().GetRouter(prefix); router != nil {
+		filters = append(filters, router.IPFilter)
+	}
+
+	for _, filter := range filters {
+		if !ipAllowed(filter, ip) {
+			s.logger.Warn("request blocked by IP filter",
+				zap.String("prefix", prefix),
+				zap.String("client_ip", c.ClientIP()))
+			c.JSON(http.StatusForbidden, gin.H{
+				"error":             "forbidden",
+				"error_description": "Client address is not allowed",
+			})
+			return false
+		}
+	}
+	return true
+}
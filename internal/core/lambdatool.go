@@ -0,0 +1,138 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/amoylab/unla/internal/common/config"
+	"github.com/amoylab/unla/pkg/mcp"
+)
+
+var lambdaHTTPClient = &http.Client{Timeout: 60 * time.Second}
+
+// invokeLambdaTool invokes an AWS Lambda function with the tool arguments
+// as the JSON payload, signing the request with SigV4
+func invokeLambdaTool(ctx context.Context, tool *config.ToolConfig, args map[string]any) (*mcp.CallToolResult, error) {
+	cfg := tool.Lambda
+
+	accessKey := cfg.AccessKey
+	if accessKey == "" {
+		accessKey = os.Getenv("AWS_ACCESS_KEY_ID")
+	}
+	secretKey := cfg.SecretKey
+	if secretKey == "" {
+		secretKey = os.Getenv("AWS_SECRET_ACCESS_KEY")
+	}
+	sessionToken := cfg.SessionToken
+	if sessionToken == "" {
+		sessionToken = os.Getenv("AWS_SESSION_TOKEN")
+	}
+	if accessKey == "" || secretKey == "" {
+		return nil, fmt.Errorf("lambda tool %s has no AWS credentials configured", tool.Name)
+	}
+
+	payload, err := json.Marshal(args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode lambda payload: %w", err)
+	}
+
+	endpoint := cfg.Endpoint
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://lambda.%s.amazonaws.com", cfg.Region)
+	}
+	invokeURL := fmt.Sprintf("%s/2015-03-31/functions/%s/invocations", endpoint, url.PathEscape(cfg.Function))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, invokeURL, bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create lambda request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if cfg.Async {
+		req.Header.Set("X-Amz-Invocation-Type", "Event")
+	}
+	signSigV4(req, payload, accessKey, secretKey, sessionToken, cfg.Region, "lambda", time.Now().UTC())
+
+	resp, err := lambdaHTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("lambda invocation failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read lambda response: %w", err)
+	}
+
+	if resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("lambda invocation failed: status %d: %s", resp.StatusCode, body)
+	}
+	if funcErr := resp.Header.Get("X-Amz-Function-Error"); funcErr != "" {
+		return mcp.NewCallToolResultError(fmt.Sprintf("lambda function error (%s): %s", funcErr, body)), nil
+	}
+	if cfg.Async {
+		return mcp.NewCallToolResultText("invocation accepted"), nil
+	}
+	return mcp.NewCallToolResultText(string(body)), nil
+}
+
+// signSigV4 signs an HTTP request with AWS Signature Version 4
+func signSigV4(req *http.Request, payload []byte, accessKey, secretKey, sessionToken, region, service string, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	dateStamp := now.Format("20060102")
+
+	payloadHash := sha256.Sum256(payload)
+	payloadHashHex := hex.EncodeToString(payloadHash[:])
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHashHex)
+	req.Header.Set("Host", req.URL.Host)
+
+	signedHeaders := "content-type;host;x-amz-content-sha256;x-amz-date"
+	canonicalHeaders := "content-type:" + req.Header.Get("Content-Type") + "\n" +
+		"host:" + req.URL.Host + "\n" +
+		"x-amz-content-sha256:" + payloadHashHex + "\n" +
+		"x-amz-date:" + amzDate + "\n"
+	if sessionToken != "" {
+		// Temporary credentials must sign the security token as well
+		req.Header.Set("X-Amz-Security-Token", sessionToken)
+		signedHeaders += ";x-amz-security-token"
+		canonicalHeaders += "x-amz-security-token:" + sessionToken + "\n"
+	}
+
+	canonicalRequest := req.Method + "\n" +
+		req.URL.EscapedPath() + "\n" +
+		req.URL.RawQuery + "\n" +
+		canonicalHeaders + "\n" +
+		signedHeaders + "\n" +
+		payloadHashHex
+
+	credentialScope := dateStamp + "/" + region + "/" + service + "/aws4_request"
+	canonicalHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + credentialScope + "\n" +
+		hex.EncodeToString(canonicalHash[:])
+
+	hmacSHA256 := func(key, data []byte) []byte {
+		mac := hmac.New(sha256.New, key)
+		mac.Write(data)
+		return mac.Sum(nil)
+	}
+	kDate := hmacSHA256([]byte("AWS4"+secretKey), []byte(dateStamp))
+	kRegion := hmacSHA256(kDate, []byte(region))
+	kService := hmacSHA256(kRegion, []byte(service))
+	kSigning := hmacSHA256(kService, []byte("aws4_request"))
+	signature := hex.EncodeToString(hmacSHA256(kSigning, []byte(stringToSign)))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		accessKey, credentialScope, signedHeaders, signature))
+}
@@ -0,0 +1,68 @@
+package core
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// The expected Authorization headers were derived independently by
+// following the steps in the AWS Signature Version 4 specification with
+// the documented example credentials, so a regression in any derivation
+// step (canonical request, string to sign, key derivation) changes the
+// signature.
+func TestSignSigV4(t *testing.T) {
+	const (
+		accessKey = "AKIDEXAMPLE"
+		secretKey = "wJalrXUtnFEMI/K7MDENG/bPxRfiCYEXAMPLEKEY"
+		payload   = `{"name":"test"}`
+	)
+	now := time.Date(2013, 5, 24, 0, 0, 0, 0, time.UTC)
+
+	newRequest := func() *http.Request {
+		req, err := http.NewRequest(http.MethodPost,
+			"https://lambda.us-east-1.amazonaws.com/2015-03-31/functions/my-function/invocations",
+			bytes.NewReader([]byte(payload)))
+		assert.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		return req
+	}
+
+	t.Run("static credentials", func(t *testing.T) {
+		req := newRequest()
+		signSigV4(req, []byte(payload), accessKey, secretKey, "", "us-east-1", "lambda", now)
+
+		assert.Equal(t,
+			"AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20130524/us-east-1/lambda/aws4_request, "+
+				"SignedHeaders=content-type;host;x-amz-content-sha256;x-amz-date, "+
+				"Signature=00d67b9df6140462d0aa864a5fe3f67088c658d234f502bde30be731f857593c",
+			req.Header.Get("Authorization"))
+		assert.Equal(t, "20130524T000000Z", req.Header.Get("X-Amz-Date"))
+		assert.Equal(t,
+			"7d9fd2051fc32b32feab10946fab6bb91426ab7e39aa5439289ed892864aa91d",
+			req.Header.Get("X-Amz-Content-Sha256"))
+		assert.Empty(t, req.Header.Get("X-Amz-Security-Token"))
+	})
+
+	t.Run("temporary credentials sign the session token", func(t *testing.T) {
+		req := newRequest()
+		signSigV4(req, []byte(payload), accessKey, secretKey, "SESSIONTOKEN", "us-east-1", "lambda", now)
+
+		assert.Equal(t,
+			"AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20130524/us-east-1/lambda/aws4_request, "+
+				"SignedHeaders=content-type;host;x-amz-content-sha256;x-amz-date;x-amz-security-token, "+
+				"Signature=00226e63973ea1997a53621a787a8902dbf9757aa87c3eac14a195d82878d925",
+			req.Header.Get("Authorization"))
+		assert.Equal(t, "SESSIONTOKEN", req.Header.Get("X-Amz-Security-Token"))
+	})
+
+	t.Run("payload changes the signature", func(t *testing.T) {
+		req := newRequest()
+		signSigV4(req, []byte(`{"name":"other"}`), accessKey, secretKey, "", "us-east-1", "lambda", now)
+		assert.NotContains(t, req.Header.Get("Authorization"),
+			"00d67b9df6140462d0aa864a5fe3f67088c658d234f502bde30be731f857593c")
+	})
+}
@@ -0,0 +1,67 @@
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"github.com/amoylab/unla/internal/common/config"
+
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+)
+
+// persistLastGoodConfigs atomically writes the merged configuration set so
+// the gateway can start even when storage is unreachable
+func (s *Server) persistLastGoodConfigs(cfgs []*config.MCPConfig) {
+	path := s.cfg.LastGoodConfigPath
+	if path == "" {
+		return
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	for _, cfg := range cfgs {
+		if err := encoder.Encode(cfg); err != nil {
+			s.logger.Warn("failed to encode last-good config cache", zap.Error(err))
+			return
+		}
+	}
+	_ = encoder.Close()
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, buf.Bytes(), 0600); err != nil {
+		s.logger.Warn("failed to write last-good config cache", zap.Error(err))
+		return
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		s.logger.Warn("failed to replace last-good config cache", zap.Error(err))
+	}
+}
+
+// loadLastGoodConfigs reads the persisted configuration set
+func (s *Server) loadLastGoodConfigs() ([]*config.MCPConfig, error) {
+	path := s.cfg.LastGoodConfigPath
+	if path == "" {
+		return nil, fmt.Errorf("no last-good config path configured")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var cfgs []*config.MCPConfig
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	for {
+		var cfg config.MCPConfig
+		if err := decoder.Decode(&cfg); err != nil {
+			break
+		}
+		cfgs = append(cfgs, &cfg)
+	}
+	if len(cfgs) == 0 {
+		return nil, fmt.Errorf("last-good config cache is empty")
+	}
+	return cfgs, nil
+}
@@ -0,0 +1,341 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/amoylab/unla/internal/common/config"
+	"github.com/amoylab/unla/internal/core/mcpproxy"
+	"github.com/amoylab/unla/internal/core/quota"
+	"github.com/amoylab/unla/internal/core/ratelimit"
+	"github.com/amoylab/unla/pkg/mcp"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// decodeCursor decodes an opaque pagination cursor into a list offset
+func decodeCursor(cursor string) (int, error) {
+	if cursor == "" {
+		return 0, nil
+	}
+	raw, err := base64.StdEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, fmt.Errorf("invalid cursor: %w", err)
+	}
+	offset, err := strconv.Atoi(string(raw))
+	if err != nil || offset < 0 {
+		return 0, fmt.Errorf("invalid cursor")
+	}
+	return offset, nil
+}
+
+// encodeCursor encodes a list offset into an opaque pagination cursor
+func encodeCursor(offset int) string {
+	return base64.StdEncoding.EncodeToString([]byte(strconv.Itoa(offset)))
+}
+
+// paginateTools slices the full tool list according to the cursor and the
+// configured page size, returning the page and the cursor for the next one
+func (s *Server) paginateTools(tools []mcp.ToolSchema, cursor string) ([]mcp.ToolSchema, string, error) {
+	pageSize := s.cfg.ToolsListPageSize
+	if pageSize <= 0 {
+		return tools, "", nil
+	}
+
+	offset, err := decodeCursor(cursor)
+	if err != nil {
+		return nil, "", err
+	}
+	if offset >= len(tools) {
+		return []mcp.ToolSchema{}, "", nil
+	}
+
+	end := offset + pageSize
+	if end >= len(tools) {
+		return tools[offset:], "", nil
+	}
+	return tools[offset:end], encodeCursor(end), nil
+}
+
+// toolCallContext bounds a proxied tool call with the backend's configured
+// timeout; the returned cancel func is always safe to call
+func (s *Server) toolCallContext(c *gin.Context, prefix string) (context.Context, context.CancelFunc) {
+	ctx := c.Request.Context()
+	if mcpCfg := s.currentState().GetMCPServerConfig(prefix); mcpCfg != nil && mcpCfg.Timeout > 0 {
+		return context.WithTimeout(ctx, mcpCfg.Timeout)
+	}
+	return ctx, func() {}
+}
+
+// resolveRateLimitRule returns the limit for a prefix/tool pair. The most
+// specific matching rule wins: prefix+tool, then prefix, then the default.
+func (s *Server) resolveRateLimitRule(prefix, tool string) ratelimit.Rule {
+	rule := ratelimit.Rule{Rate: s.cfg.RateLimit.Rate, Burst: s.cfg.RateLimit.Burst}
+	for i := range s.cfg.RateLimit.Rules {
+		r := &s.cfg.RateLimit.Rules[i]
+		if r.Prefix != prefix {
+			continue
+		}
+		if r.Tool == tool {
+			return ratelimit.Rule{Rate: r.Rate, Burst: r.Burst}
+		}
+		if r.Tool == "" {
+			rule = ratelimit.Rule{Rate: r.Rate, Burst: r.Burst}
+		}
+	}
+	return rule
+}
+
+// rateLimitClientKey identifies the caller for rate limiting purposes:
+// the API key when one is supplied, otherwise the session ID.
+func rateLimitClientKey(c *gin.Context, sessionID string) string {
+	if apiKey := c.GetHeader("X-API-Key"); apiKey != "" {
+		return "key:" + apiKey
+	}
+	return "session:" + sessionID
+}
+
+// quotaRuleKey builds a stable counter key for a quota rule
+func quotaRuleKey(rule *config.QuotaRule) string {
+	return fmt.Sprintf("%s|%s|%s", rule.Tenant, rule.APIKey, rule.Tool)
+}
+
+// quotaRuleMatches reports whether a rule applies to a call
+func quotaRuleMatches(rule *config.QuotaRule, tenant, apiKey, tool string) bool {
+	if rule.Tenant != "" && rule.Tenant != tenant {
+		return false
+	}
+	if rule.APIKey != "" && rule.APIKey != apiKey {
+		return false
+	}
+	if rule.Tool != "" && rule.Tool != tool {
+		return false
+	}
+	return true
+}
+
+// checkQuota enforces daily/monthly call quotas for a tool call. It sends a
+// JSON-RPC error with HTTP 429 and returns false when a quota is exhausted.
+func (s *Server) checkQuota(c *gin.Context, reqID any, prefix, tool string) bool {
+	if s.quota == nil {
+		return true
+	}
+
+	tenant := s.currentState().GetTenant(prefix)
+	apiKey := c.GetHeader("X-API-Key")
+	now := time.Now()
+
+	for i := range s.cfg.Quota.Limits {
+		rule := &s.cfg.Quota.Limits[i]
+		if !quotaRuleMatches(rule, tenant, apiKey, tool) {
+			continue
+		}
+
+		base := quotaRuleKey(rule)
+		if rule.Daily > 0 {
+			count, err := s.quota.Incr(c.Request.Context(), quota.DayKey(base, now), 48*time.Hour)
+			if err != nil {
+				s.logger.Error("quota counter failed", zap.Error(err))
+				continue // fail open
+			}
+			if count > rule.Daily {
+				s.sendProtocolError(c, reqID, "Daily quota exceeded", http.StatusTooManyRequests, mcp.ErrorCodeRequestTimeout)
+				return false
+			}
+		}
+		if rule.Monthly > 0 {
+			count, err := s.quota.Incr(c.Request.Context(), quota.MonthKey(base, now), 32*24*time.Hour)
+			if err != nil {
+				s.logger.Error("quota counter failed", zap.Error(err))
+				continue // fail open
+			}
+			if count > rule.Monthly {
+				s.sendProtocolError(c, reqID, "Monthly quota exceeded", http.StatusTooManyRequests, mcp.ErrorCodeRequestTimeout)
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// checkToolRateLimit enforces the configured limit for a tool call. It sends
+// a JSON-RPC error with HTTP 429 and returns false when the limit is exceeded.
+func (s *Server) checkToolRateLimit(c *gin.Context, reqID any, prefix, tool, sessionID string) bool {
+	if s.limiter == nil {
+		return true
+	}
+
+	rule := s.resolveRateLimitRule(prefix, tool)
+	if rule.Rate <= 0 {
+		return true
+	}
+
+	key := fmt.Sprintf("%s:%s:%s", prefix, tool, rateLimitClientKey(c, sessionID))
+	allowed, err := s.limiter.Allow(c.Request.Context(), key, rule)
+	if err != nil {
+		// Fail open: an unreachable limiter backend should not take down tool calls
+		s.logger.Error("rate limiter check failed",
+			zap.String("prefix", prefix),
+			zap.String("tool", tool),
+			zap.Error(err))
+		return true
+	}
+	if !allowed {
+		s.logger.Warn("rate limit exceeded",
+			zap.String("prefix", prefix),
+			zap.String("tool", tool),
+			zap.String("session_id", sessionID))
+		s.sendProtocolError(c, reqID, "Rate limit exceeded", http.StatusTooManyRequests, mcp.ErrorCodeRequestTimeout)
+		return false
+	}
+	return true
+}
+
+// keepaliveInterval returns the SSE keepalive period; negative config
+// disables it by returning an effectively-infinite interval
+func (s *Server) keepaliveInterval() time.Duration {
+	interval := s.cfg.KeepaliveInterval
+	if interval == 0 {
+		return 30 * time.Second
+	}
+	if interval < 0 {
+		return time.Hour * 24 * 365
+	}
+	return interval
+}
+
+// toolFilterFor returns the tool filter configured on a prefix's router
+func (s *Server) toolFilterFor(prefix string) *config.ToolFilterConfig {
+	router := s.currentState().GetRouter(prefix)
+	if router == nil {
+		return nil
+	}
+	return router.ToolFilter
+}
+
+// toolAllowed applies the prefix's allow/deny lists, with per-consumer
+// overrides selected by API key. Deny always wins.
+func toolAllowed(filter *config.ToolFilterConfig, tool, apiKey string) bool {
+	if filter == nil {
+		return true
+	}
+
+	allow, deny := filter.Allow, filter.Deny
+	if consumer, ok := filter.Consumers[apiKey]; ok && apiKey != "" {
+		// A consumer entry replaces the prefix-level rules entirely
+		allow, deny = consumer.Allow, consumer.Deny
+	}
+
+	for _, denied := range deny {
+		if denied == tool {
+			return false
+		}
+	}
+	if len(allow) == 0 {
+		return true
+	}
+	for _, allowed := range allow {
+		if allowed == tool {
+			return true
+		}
+	}
+	return false
+}
+
+// filterTools drops tools the caller may not see from a tools/list result
+func (s *Server) filterTools(c *gin.Context, prefix string, tools []mcp.ToolSchema) []mcp.ToolSchema {
+	filter := s.toolFilterFor(prefix)
+	if filter == nil {
+		return tools
+	}
+	apiKey := c.GetHeader("X-API-Key")
+	filtered := make([]mcp.ToolSchema, 0, len(tools))
+	for _, tool := range tools {
+		if toolAllowed(filter, tool.Name, apiKey) {
+			filtered = append(filtered, tool)
+		}
+	}
+	return filtered
+}
+
+// checkToolAccess rejects calls to filtered tools with a protocol error
+func (s *Server) checkToolAccess(c *gin.Context, reqID any, prefix, tool string) bool {
+	if toolAllowed(s.toolFilterFor(prefix), tool, c.GetHeader("X-API-Key")) {
+		return true
+	}
+	s.sendProtocolError(c, reqID, "Tool not allowed", http.StatusForbidden, mcp.ErrorCodeMethodNotFound)
+	return false
+}
+
+// bodyLimitMiddleware caps request body sizes before handlers read them
+func (s *Server) bodyLimitMiddleware() gin.HandlerFunc {
+	maxBytes := s.cfg.Limits.MaxBodyBytes
+	if maxBytes == 0 {
+		maxBytes = 4 << 20
+	}
+	return func(c *gin.Context) {
+		if maxBytes > 0 && c.Request.Body != nil {
+			c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxBytes)
+		}
+		c.Next()
+	}
+}
+
+// jsonDepth measures the maximum nesting depth of a JSON document
+func jsonDepth(data []byte) int {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	depth, maxDepth := 0, 0
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return maxDepth
+		}
+		if delim, ok := token.(json.Delim); ok {
+			switch delim {
+			case '{', '[':
+				depth++
+				if depth > maxDepth {
+					maxDepth = depth
+				}
+			case '}', ']':
+				depth--
+			}
+		}
+	}
+}
+
+// checkJSONDepth rejects payloads nested beyond the configured bound
+func (s *Server) checkJSONDepth(c *gin.Context, body []byte) bool {
+	maxDepth := s.cfg.Limits.MaxJSONDepth
+	if maxDepth <= 0 {
+		maxDepth = 100
+	}
+	if jsonDepth(body) > maxDepth {
+		s.sendProtocolError(c, nil, "Request JSON exceeds the nesting limit",
+			http.StatusBadRequest, mcp.ErrorCodeInvalidRequest)
+		return false
+	}
+	return true
+}
+
+// transportForSession returns the prefix's transport, routing the sticky
+// canary share of sessions to the canary replica
+func (s *Server) transportForSession(prefix, sessionID string) mcpproxy.Transport {
+	canary, percent := s.currentState().GetCanary(prefix)
+	if canary != nil && percent > 0 {
+		sum := sha256.Sum256([]byte(sessionID))
+		if int(binary.BigEndian.Uint32(sum[:4]))%100 < percent {
+			return canary
+		}
+	}
+	return s.currentState().GetTransport(prefix)
+}
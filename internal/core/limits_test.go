@@ -0,0 +1,145 @@
+package core
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/amoylab/unla/internal/common/config"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSONDepth(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected int
+	}{
+		{
+			name:     "scalar",
+			input:    `42`,
+			expected: 0,
+		},
+		{
+			name:     "flat object",
+			input:    `{"a":1,"b":2}`,
+			expected: 1,
+		},
+		{
+			name:     "nested object and array",
+			input:    `{"a":{"b":[{"c":1}]}}`,
+			expected: 4,
+		},
+		{
+			name:     "siblings do not accumulate",
+			input:    `{"a":{"b":1},"c":{"d":2}}`,
+			expected: 2,
+		},
+		{
+			name:     "deep array",
+			input:    strings.Repeat("[", 50) + strings.Repeat("]", 50),
+			expected: 50,
+		},
+		{
+			name:     "truncated input counts what was parsed",
+			input:    `{"a":[{"b":`,
+			expected: 3,
+		},
+		{
+			name:     "invalid input",
+			input:    `not json`,
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, jsonDepth([]byte(tt.input)))
+		})
+	}
+}
+
+func TestToolAllowed(t *testing.T) {
+	filter := &config.ToolFilterConfig{
+		Allow: []string{"alpha", "beta"},
+		Deny:  []string{"beta"},
+		Consumers: map[string]config.ConsumerFilter{
+			"key1": {Allow: []string{"gamma"}},
+			"key2": {Deny: []string{"alpha"}},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		filter   *config.ToolFilterConfig
+		tool     string
+		apiKey   string
+		expected bool
+	}{
+		{
+			name:     "nil filter allows everything",
+			filter:   nil,
+			tool:     "anything",
+			expected: true,
+		},
+		{
+			name:     "allowed tool",
+			filter:   filter,
+			tool:     "alpha",
+			expected: true,
+		},
+		{
+			name:     "deny wins over allow",
+			filter:   filter,
+			tool:     "beta",
+			expected: false,
+		},
+		{
+			name:     "not in allow list",
+			filter:   filter,
+			tool:     "gamma",
+			expected: false,
+		},
+		{
+			name:     "consumer override replaces prefix rules",
+			filter:   filter,
+			tool:     "gamma",
+			apiKey:   "key1",
+			expected: true,
+		},
+		{
+			name:     "consumer override drops prefix allow",
+			filter:   filter,
+			tool:     "alpha",
+			apiKey:   "key1",
+			expected: false,
+		},
+		{
+			name:     "consumer deny with empty allow permits the rest",
+			filter:   filter,
+			tool:     "delta",
+			apiKey:   "key2",
+			expected: true,
+		},
+		{
+			name:     "consumer deny blocks its tool",
+			filter:   filter,
+			tool:     "alpha",
+			apiKey:   "key2",
+			expected: false,
+		},
+		{
+			name:     "unknown key falls back to prefix rules",
+			filter:   filter,
+			tool:     "alpha",
+			apiKey:   "other",
+			expected: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, toolAllowed(tt.filter, tt.tool, tt.apiKey))
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package core
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/amoylab/unla/internal/common/config"
+
+	"go.uber.org/zap"
+)
+
+// listenerAuthExemptKey marks requests from listeners that run with
+// disable_auth so handleRoot can skip the prefix auth checks
+type listenerAuthExemptKey struct{}
+
+// startExtraListeners serves the gateway on the additional configured
+// addresses, e.g. a unix socket for sidecar traffic next to the TCP port
+func (s *Server) startExtraListeners() {
+	for _, listenerCfg := range s.cfg.Listeners {
+		cfg := listenerCfg
+		go func() {
+			if err := s.serveListener(cfg); err != nil {
+				s.logger.Error("extra listener failed",
+					zap.String("address", cfg.Address),
+					zap.Error(err))
+			}
+		}()
+	}
+}
+
+// serveListener runs one extra listener with its prefix restriction
+func (s *Server) serveListener(cfg config.ListenerConfig) error {
+	var (
+		listener net.Listener
+		err      error
+	)
+	if path, isUnix := strings.CutPrefix(cfg.Address, "unix://"); isUnix {
+		// Remove a socket left behind by an unclean shutdown; a live
+		// gateway would still hold the listen and fail below instead
+		if info, statErr := os.Stat(path); statErr == nil && info.Mode()&os.ModeSocket != 0 {
+			if removeErr := os.Remove(path); removeErr != nil {
+				s.logger.Warn("failed to remove stale unix socket",
+					zap.String("path", path),
+					zap.Error(removeErr))
+			}
+		}
+		listener, err = net.Listen("unix", path)
+		if err == nil {
+			s.trackUnixSocket(path)
+		}
+	} else {
+		listener, err = net.Listen("tcp", cfg.Address)
+	}
+	if err != nil {
+		return err
+	}
+
+	s.logger.Info("starting extra listener",
+		zap.String("address", cfg.Address),
+		zap.Strings("prefixes", cfg.Prefixes))
+
+	handler := http.Handler(s.router)
+	if len(cfg.Prefixes) > 0 {
+		handler = prefixRestrictedHandler(s.router, cfg.Prefixes)
+	}
+	if cfg.DisableAuth {
+		handler = authExemptHandler(handler)
+	}
+	return http.Serve(listener, handler)
+}
+
+// trackUnixSocket records a socket path for removal on shutdown
+func (s *Server) trackUnixSocket(path string) {
+	s.unixSocketsMu.Lock()
+	defer s.unixSocketsMu.Unlock()
+	s.unixSockets = append(s.unixSockets, path)
+}
+
+// removeUnixSockets unlinks the extra listeners' socket files
+func (s *Server) removeUnixSockets() {
+	s.unixSocketsMu.Lock()
+	defer s.unixSocketsMu.Unlock()
+	for _, path := range s.unixSockets {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			s.logger.Warn("failed to remove unix socket",
+				zap.String("path", path),
+				zap.Error(err))
+		}
+	}
+	s.unixSockets = nil
+}
+
+// authExemptHandler marks every request on the listener as exempt from
+// prefix auth
+func authExemptHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), listenerAuthExemptKey{}, true)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// prefixRestrictedHandler rejects requests outside the listener's allowed
+// prefixes before they reach the shared router
+func prefixRestrictedHandler(next http.Handler, prefixes []string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(r.URL.Path, prefix+"/") || r.URL.Path == prefix {
+				next.ServeHTTP(w, r)
+				return
+			}
+		}
+		http.Error(w, "prefix not served on this listener", http.StatusForbidden)
+	})
+}
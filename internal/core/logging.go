@@ -0,0 +1,61 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/amoylab/unla/internal/mcp/session"
+	"github.com/amoylab/unla/pkg/mcp"
+)
+
+// logLevelSeverity orders the MCP logging levels
+var logLevelSeverity = map[string]int{
+	"debug":     0,
+	"info":      1,
+	"notice":    2,
+	"warning":   3,
+	"error":     4,
+	"critical":  5,
+	"alert":     6,
+	"emergency": 7,
+}
+
+// setSessionLogLevel records the minimum level a session wants to receive
+func (s *Server) setSessionLogLevel(sessionID, level string) bool {
+	if _, ok := logLevelSeverity[level]; !ok {
+		return false
+	}
+	s.sessionLogLevels.Store(sessionID, level)
+	return true
+}
+
+// sessionLog emits a notifications/message event to the session when its
+// subscribed level includes the given severity; sessions that never called
+// logging/setLevel receive nothing
+func (s *Server) sessionLog(ctx context.Context, conn session.Connection, level, message string) {
+	value, ok := s.sessionLogLevels.Load(conn.Meta().ID)
+	if !ok {
+		return
+	}
+	if logLevelSeverity[level] < logLevelSeverity[value.(string)] {
+		return
+	}
+
+	data, err := json.Marshal(struct {
+		JSONRPC string `json:"jsonrpc"`
+		Method  string `json:"method"`
+		Params  any    `json:"params"`
+	}{
+		JSONRPC: mcp.JSPNRPCVersion,
+		Method:  mcp.NotificationMessage,
+		Params: map[string]any{
+			"level":  level,
+			"logger": "gateway",
+			"data":   message,
+		},
+	})
+	if err != nil {
+		return
+	}
+	_ = conn.Send(ctx, &session.Message{Event: "message", Data: data})
+}
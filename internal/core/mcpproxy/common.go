@@ -2,11 +2,49 @@ package mcpproxy
 
 import (
 	"encoding/json"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/amoylab/unla/internal/common/config"
 
-	mcpgo "github.com/mark3labs/mcp-go/mcp"
 	"github.com/amoylab/unla/pkg/mcp"
+	mcpgo "github.com/mark3labs/mcp-go/mcp"
 )
 
+// defaultIdleTimeout is how long an on-demand upstream client is kept
+// initialized after its last use before being shut down
+const defaultIdleTimeout = 5 * time.Minute
+
+// keepAlive defers shutdown of on-demand upstream clients so they are reused
+// across calls instead of being re-initialized for every single request
+type keepAlive struct {
+	mu    sync.Mutex
+	timer *time.Timer
+}
+
+// touch postpones the idle shutdown; stop runs once the client has been
+// unused for the full idle timeout
+func (k *keepAlive) touch(stop func()) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if k.timer != nil {
+		k.timer.Stop()
+	}
+	k.timer = time.AfterFunc(defaultIdleTimeout, stop)
+}
+
+// cancel drops any pending idle shutdown, e.g. when the client is stopped
+// explicitly
+func (k *keepAlive) cancel() {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if k.timer != nil {
+		k.timer.Stop()
+		k.timer = nil
+	}
+}
+
 // convertMCPGoResult converts mcp-go result to local mcp format
 func convertMCPGoResult(res *mcpgo.CallToolResult) *mcp.CallToolResult {
 	result := &mcp.CallToolResult{
@@ -93,3 +131,42 @@ func convertMCPGoResult(res *mcpgo.CallToolResult) *mcp.CallToolResult {
 
 	return result
 }
+
+// convertAnnotations maps mcp-go tool annotations to the local schema,
+// returning nil when no hints are set
+func convertAnnotations(annotations mcpgo.ToolAnnotation) *mcp.ToolAnnotationsSchema {
+	if annotations.Title == "" && annotations.ReadOnlyHint == nil &&
+		annotations.DestructiveHint == nil && annotations.IdempotentHint == nil &&
+		annotations.OpenWorldHint == nil {
+		return nil
+	}
+	return &mcp.ToolAnnotationsSchema{
+		Title:           annotations.Title,
+		ReadOnlyHint:    annotations.ReadOnlyHint,
+		DestructiveHint: annotations.DestructiveHint,
+		IdempotentHint:  annotations.IdempotentHint,
+		OpenWorldHint:   annotations.OpenWorldHint,
+	}
+}
+
+// replicaPicker selects upstream replicas round-robin, skipping over
+// replicas that fail to connect
+var replicaPicker atomic.Uint64
+
+// candidateURLs returns the backend's replica list in rotated order so
+// connection attempts are spread across replicas
+func candidateURLs(cfg *config.MCPServerConfig) []string {
+	urls := cfg.URLs
+	if len(urls) == 0 {
+		if cfg.URL == "" {
+			return nil
+		}
+		return []string{cfg.URL}
+	}
+
+	start := int(replicaPicker.Add(1)) % len(urls)
+	rotated := make([]string, 0, len(urls))
+	rotated = append(rotated, urls[start:]...)
+	rotated = append(rotated, urls[:start]...)
+	return rotated
+}
@@ -0,0 +1,46 @@
+package mcpproxy
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/amoylab/unla/internal/common/config"
+)
+
+// forwardHeadersKey carries the incoming request headers through the call
+// context so the transport's header func can apply the backend policy
+type forwardHeadersKey struct{}
+
+// WithForwardHeaders attaches the incoming request headers to the context
+func WithForwardHeaders(ctx context.Context, headers map[string]string) context.Context {
+	if len(headers) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, forwardHeadersKey{}, headers)
+}
+
+// policyHeaders applies the backend's header policy to the incoming headers
+// from the context: allowed headers are forwarded (optionally renamed) and
+// static headers are always injected
+func policyHeaders(ctx context.Context, policy *config.HeaderPolicyConfig) map[string]string {
+	result := make(map[string]string)
+
+	incoming, _ := ctx.Value(forwardHeadersKey{}).(map[string]string)
+	for _, allowed := range policy.Allowed {
+		canonical := http.CanonicalHeaderKey(allowed)
+		for name, value := range incoming {
+			if http.CanonicalHeaderKey(name) != canonical {
+				continue
+			}
+			upstream := allowed
+			if renamed, ok := policy.Rename[allowed]; ok {
+				upstream = renamed
+			}
+			result[upstream] = value
+		}
+	}
+	for name, value := range policy.Static {
+		result[name] = value
+	}
+	return result
+}
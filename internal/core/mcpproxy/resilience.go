@@ -0,0 +1,219 @@
+package mcpproxy
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/amoylab/unla/internal/common/config"
+	"github.com/amoylab/unla/internal/template"
+	"github.com/amoylab/unla/pkg/mcp"
+
+	"go.uber.org/zap"
+)
+
+// breakerState represents the circuit breaker state
+type breakerState string
+
+const (
+	breakerClosed   breakerState = "closed"
+	breakerOpen     breakerState = "open"
+	breakerHalfOpen breakerState = "half-open"
+)
+
+// breaker is a consecutive-failure circuit breaker
+type breaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	failures  int
+	state     breakerState
+	openedAt  time.Time
+}
+
+// allow reports whether a call may proceed
+func (b *breaker) allow() bool {
+	if b.threshold <= 0 {
+		return true
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == breakerOpen {
+		if time.Since(b.openedAt) < b.cooldown {
+			return false
+		}
+		// Cooldown elapsed: let a single trial call through
+		b.state = breakerHalfOpen
+	}
+	return true
+}
+
+// record updates the breaker with the outcome of a call
+func (b *breaker) record(err error) {
+	if b.threshold <= 0 {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err == nil {
+		b.failures = 0
+		b.state = breakerClosed
+		return
+	}
+
+	b.failures++
+	if b.state == breakerHalfOpen || b.failures >= b.threshold {
+		b.state = breakerOpen
+		b.openedAt = time.Now()
+	}
+}
+
+// State returns the current breaker state for observability
+func (b *breaker) State() breakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+// resilientTransport decorates a Transport with per-call timeouts, retries
+// with exponential backoff, and a circuit breaker
+type resilientTransport struct {
+	inner   Transport
+	cfg     config.ResilienceConfig
+	name    string
+	logger  *zap.Logger
+	breaker breaker
+}
+
+var _ Transport = (*resilientTransport)(nil)
+
+// newResilientTransport wraps a transport according to its backend's
+// resilience configuration
+func newResilientTransport(logger *zap.Logger, inner Transport, serverCfg config.MCPServerConfig) *resilientTransport {
+	cfg := *serverCfg.Resilience
+	if cfg.RetryBackoff <= 0 {
+		cfg.RetryBackoff = 100 * time.Millisecond
+	}
+	if cfg.BreakerCooldown <= 0 {
+		cfg.BreakerCooldown = 30 * time.Second
+	}
+	return &resilientTransport{
+		inner:  inner,
+		cfg:    cfg,
+		name:   serverCfg.Name,
+		logger: logger.Named("mcpproxy.resilience"),
+		breaker: breaker{
+			threshold: cfg.BreakerThreshold,
+			cooldown:  cfg.BreakerCooldown,
+			state:     breakerClosed,
+		},
+	}
+}
+
+// do runs fn with breaker gating, per-call timeout and retries
+func (t *resilientTransport) do(ctx context.Context, op string, fn func(ctx context.Context) error) error {
+	if !t.breaker.allow() {
+		return fmt.Errorf("circuit breaker open for backend %s", t.name)
+	}
+
+	backoff := t.cfg.RetryBackoff
+	var err error
+	for attempt := 0; ; attempt++ {
+		callCtx := ctx
+		var cancel context.CancelFunc
+		if t.cfg.Timeout > 0 {
+			callCtx, cancel = context.WithTimeout(ctx, t.cfg.Timeout)
+		}
+		err = fn(callCtx)
+		if cancel != nil {
+			cancel()
+		}
+		if err == nil || attempt >= t.cfg.Retries || ctx.Err() != nil {
+			break
+		}
+
+		t.logger.Warn("retrying upstream call",
+			zap.String("backend", t.name),
+			zap.String("op", op),
+			zap.Int("attempt", attempt+1),
+			zap.Duration("backoff", backoff),
+			zap.Error(err))
+		select {
+		case <-ctx.Done():
+			err = ctx.Err()
+		case <-time.After(backoff):
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		backoff *= 2
+	}
+
+	t.breaker.record(err)
+	if err != nil && t.breaker.State() == breakerOpen {
+		t.logger.Warn("circuit breaker opened",
+			zap.String("backend", t.name),
+			zap.Int("threshold", t.cfg.BreakerThreshold))
+	}
+	return err
+}
+
+// FetchTools implements Transport.FetchTools
+func (t *resilientTransport) FetchTools(ctx context.Context) ([]mcp.ToolSchema, error) {
+	var tools []mcp.ToolSchema
+	err := t.do(ctx, "tools/list", func(ctx context.Context) error {
+		var err error
+		tools, err = t.inner.FetchTools(ctx)
+		return err
+	})
+	return tools, err
+}
+
+// CallTool implements Transport.CallTool
+func (t *resilientTransport) CallTool(ctx context.Context, params mcp.CallToolParams, req *template.RequestWrapper) (*mcp.CallToolResult, error) {
+	var result *mcp.CallToolResult
+	err := t.do(ctx, "tools/call", func(ctx context.Context) error {
+		var err error
+		result, err = t.inner.CallTool(ctx, params, req)
+		return err
+	})
+	return result, err
+}
+
+// Start implements Transport.Start
+func (t *resilientTransport) Start(ctx context.Context, tmplCtx *template.Context) error {
+	return t.inner.Start(ctx, tmplCtx)
+}
+
+// Stop implements Transport.Stop
+func (t *resilientTransport) Stop(ctx context.Context) error {
+	return t.inner.Stop(ctx)
+}
+
+// IsRunning implements Transport.IsRunning
+func (t *resilientTransport) IsRunning() bool {
+	return t.inner.IsRunning()
+}
+
+// FetchPrompts implements Transport.FetchPrompts
+func (t *resilientTransport) FetchPrompts(ctx context.Context) ([]mcp.PromptSchema, error) {
+	return t.inner.FetchPrompts(ctx)
+}
+
+// FetchPrompt implements Transport.FetchPrompt
+func (t *resilientTransport) FetchPrompt(ctx context.Context, name string) (*mcp.PromptSchema, error) {
+	return t.inner.FetchPrompt(ctx, name)
+}
+
+// SetNotificationHandler implements NotificationForwarder by delegating to
+// the wrapped transport when it supports notifications
+func (t *resilientTransport) SetNotificationHandler(handler func(method string, params map[string]any)) {
+	if forwarder, ok := t.inner.(NotificationForwarder); ok {
+		forwarder.SetNotificationHandler(handler)
+	}
+}
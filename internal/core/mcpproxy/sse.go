@@ -5,7 +5,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"sync"
 
+	"github.com/amoylab/unla/internal/auth"
 	"github.com/amoylab/unla/internal/common/cnst"
 
 	"github.com/amoylab/unla/internal/common/config"
@@ -19,30 +21,76 @@ import (
 
 // SSETransport implements Transport using Server-Sent Events
 type SSETransport struct {
-	client *client.Client
-	cfg    config.MCPServerConfig
+	// mu guards client, notifyAttached and the start/stop lifecycle so
+	// concurrent calls, idle eviction and recovery never race on the
+	// shared client
+	mu        sync.Mutex
+	client    *client.Client
+	cfg       config.MCPServerConfig
+	keepAlive keepAlive
+	// notifyHandler receives backend notifications for forwarding
+	notifyHandler func(method string, params map[string]any)
+	// notifyAttached tracks whether the current client has the
+	// notification callback registered
+	notifyAttached bool
 }
 
 var _ Transport = (*SSETransport)(nil)
 
 func (t *SSETransport) Start(ctx context.Context, tmplCtx *template.Context) error {
-	if t.IsRunning() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.startLocked(ctx, tmplCtx)
+}
+
+// startLocked connects and initializes the client; callers must hold mu
+func (t *SSETransport) startLocked(ctx context.Context, _ *template.Context) error {
+	if t.client != nil {
 		return nil
 	}
 
 	// Create SSE transport
-	sseTransport, err := transport.NewSSE(t.cfg.URL)
-	if err != nil {
-		return fmt.Errorf("failed to create SSE transport: %w", err)
+	var opts []transport.ClientOption
+	if t.cfg.Headers != nil {
+		policy := t.cfg.Headers
+		opts = append(opts, transport.WithHeaderFunc(func(ctx context.Context) map[string]string {
+			return policyHeaders(ctx, policy)
+		}))
 	}
-
-	// Start the transport
-	if err := sseTransport.Start(ctx); err != nil {
-		return fmt.Errorf("failed to start SSE transport: %w", err)
+	if t.cfg.UpstreamAuth != nil {
+		token, err := auth.GetUpstreamTokenSource(t.cfg.UpstreamAuth).Token(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to fetch upstream token: %w", err)
+		}
+		opts = append(opts, transport.WithHeaders(map[string]string{
+			"Authorization": "Bearer " + token,
+		}))
+	}
+	// Try each replica until one accepts the connection
+	var (
+		c       *client.Client
+		lastErr error
+	)
+	for _, url := range candidateURLs(&t.cfg) {
+		sseTransport, err := transport.NewSSE(url, opts...)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		// Create client with the transport and start it through the client
+		// so the notification relay is wired up. The connection outlives
+		// the current request, so detach it from the caller's cancelation.
+		candidate := client.NewClient(sseTransport)
+		if err := candidate.Start(context.WithoutCancel(ctx)); err != nil {
+			lastErr = err
+			continue
+		}
+		c = candidate
+		break
+	}
+	if c == nil {
+		return fmt.Errorf("failed to start SSE transport: %w", lastErr)
 	}
-
-	// Create client with the transport
-	c := client.NewClient(sseTransport)
 
 	// Initialize the client
 	initRequest := mcpgo.InitializeRequest{}
@@ -52,47 +100,87 @@ func (t *SSETransport) Start(ctx context.Context, tmplCtx *template.Context) err
 		Version: version.Get(),
 	}
 
-	_, err = c.Initialize(ctx, initRequest)
-	if err != nil {
-		_ = sseTransport.Close()
+	if _, err := c.Initialize(ctx, initRequest); err != nil {
+		_ = c.Close()
 		return fmt.Errorf("failed to initialize SSE client: %w", err)
 	}
 
 	t.client = c
+	t.notifyAttached = false
+	t.attachNotifyLocked()
 	return nil
 }
 
 func (t *SSETransport) Stop(_ context.Context) error {
-	if !t.IsRunning() {
-		return nil
-	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.stopLocked()
+}
 
-	if t.client != nil {
-		err := t.client.Close()
-		if err != nil {
-			return err
-		}
-		t.client = nil
+// stopLocked closes the client; callers must hold mu
+func (t *SSETransport) stopLocked() error {
+	t.keepAlive.cancel()
+	if t.client == nil {
+		return nil
 	}
 
-	return nil
+	err := t.client.Close()
+	t.client = nil
+	return err
 }
 
 func (t *SSETransport) IsRunning() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
 	return t.client != nil
 }
 
-func (t *SSETransport) FetchTools(ctx context.Context) ([]mcp.ToolSchema, error) {
-	if !t.IsRunning() {
-		if err := t.Start(ctx, nil); err != nil {
+// ensureClient returns the live client, starting one if needed
+func (t *SSETransport) ensureClient(ctx context.Context, tmplCtx *template.Context) (*client.Client, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.client == nil {
+		if err := t.startLocked(ctx, tmplCtx); err != nil {
 			return nil, err
 		}
 	}
+	return t.client, nil
+}
+
+// replaceClient swaps out a stale client for a fresh one. When another
+// goroutine already replaced it, the current client is returned as-is so
+// concurrent recoveries don't stop over each other.
+func (t *SSETransport) replaceClient(ctx context.Context, tmplCtx *template.Context, stale *client.Client) (*client.Client, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.client != nil && t.client != stale {
+		return t.client, nil
+	}
+	_ = t.stopLocked()
+	if err := t.startLocked(ctx, tmplCtx); err != nil {
+		return nil, err
+	}
+	return t.client, nil
+}
+
+func (t *SSETransport) FetchTools(ctx context.Context) ([]mcp.ToolSchema, error) {
+	c, err := t.ensureClient(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
 
 	// List available tools
-	toolsResult, err := t.client.ListTools(ctx, mcpgo.ListToolsRequest{})
+	toolsResult, err := c.ListTools(ctx, mcpgo.ListToolsRequest{})
 	if err != nil {
-		return nil, fmt.Errorf("failed to list tools: %w", err)
+		// A reused client may have gone stale; re-initialize once and retry
+		c, startErr := t.replaceClient(ctx, nil, c)
+		if startErr != nil {
+			return nil, fmt.Errorf("failed to list tools: %w", err)
+		}
+		toolsResult, err = c.ListTools(ctx, mcpgo.ListToolsRequest{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list tools: %w", err)
+		}
 	}
 
 	// Convert from mcpgo.Tool to mcp.ToolSchema
@@ -132,18 +220,26 @@ func (t *SSETransport) FetchTools(ctx context.Context) ([]mcp.ToolSchema, error)
 			Name:        schema.Name,
 			Description: schema.Description,
 			InputSchema: inputSchema,
+			Annotations: convertAnnotations(schema.Annotations),
 		}
 	}
 
-	t.Stop(ctx)
+	t.scheduleIdleStop()
 	return tools, nil
 }
 
+// scheduleIdleStop keeps an on-demand client initialized for reuse and shuts
+// it down only after the idle timeout expires
+func (t *SSETransport) scheduleIdleStop() {
+	if t.cfg.Policy == cnst.PolicyOnDemand {
+		t.keepAlive.touch(func() { _ = t.Stop(context.Background()) })
+	}
+}
+
 func (t *SSETransport) CallTool(ctx context.Context, params mcp.CallToolParams, req *template.RequestWrapper) (*mcp.CallToolResult, error) {
-	if !t.IsRunning() {
-		if err := t.Start(ctx, nil); err != nil {
-			return nil, err
-		}
+	c, err := t.ensureClient(ctx, nil)
+	if err != nil {
+		return nil, err
 	}
 
 	// Convert arguments to map[string]any
@@ -178,13 +274,29 @@ func (t *SSETransport) CallTool(ctx context.Context, params mcp.CallToolParams,
 	callRequest := mcpgo.CallToolRequest{}
 	callRequest.Params.Name = params.Name
 	callRequest.Params.Arguments = toolCallRequestParams
+	if params.Meta.ProgressToken != nil {
+		// Forward the caller's progress token so backend progress
+		// notifications can be routed back to the originating session
+		callRequest.Params.Meta = &mcpgo.Meta{ProgressToken: params.Meta.ProgressToken}
+	}
 
-	mcpResult, err := t.client.CallTool(ctx, callRequest)
+	if req != nil {
+		ctx = WithForwardHeaders(ctx, req.Headers)
+	}
+	mcpResult, err := c.CallTool(ctx, callRequest)
 	if err != nil {
-		return nil, fmt.Errorf("failed to call tool: %w", err)
+		// A reused client may have gone stale; re-initialize once and retry
+		c, startErr := t.replaceClient(ctx, tmplCtx, c)
+		if startErr != nil {
+			return nil, fmt.Errorf("failed to call tool: %w", err)
+		}
+		mcpResult, err = c.CallTool(ctx, callRequest)
+		if err != nil {
+			return nil, fmt.Errorf("failed to call tool: %w", err)
+		}
 	}
 
-	t.Stop(ctx)
+	t.scheduleIdleStop()
 	return convertMCPGoResult(mcpResult), nil
 }
 
@@ -199,4 +311,37 @@ func (t *SSETransport) FetchPrompt(ctx context.Context, name string) (*mcp.Promp
 		StatusCode: http.StatusNotFound,
 		Message:    "Prompt not found",
 	}
-}
\ No newline at end of file
+}
+
+// SetNotificationHandler implements NotificationForwarder; it attaches to
+// an already-running client as well so reloads can re-register safely
+func (t *SSETransport) SetNotificationHandler(handler func(method string, params map[string]any)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.notifyHandler = handler
+	t.attachNotifyLocked()
+}
+
+// attachNotifyLocked registers the notification callback once per client;
+// the callback reads notifyHandler dynamically so handler swaps need no
+// re-registration. Callers must hold mu.
+func (t *SSETransport) attachNotifyLocked() {
+	c := t.client
+	if c == nil || t.notifyAttached || t.notifyHandler == nil {
+		return
+	}
+	t.notifyAttached = true
+	c.OnNotification(func(n mcpgo.JSONRPCNotification) {
+		handler := t.notifyHandler
+		if handler == nil {
+			return
+		}
+		params, err := json.Marshal(n.Params)
+		if err != nil {
+			return
+		}
+		decoded := make(map[string]any)
+		_ = json.Unmarshal(params, &decoded)
+		handler(n.Method, decoded)
+	})
+}
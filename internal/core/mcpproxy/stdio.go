@@ -1,33 +1,74 @@
 package mcpproxy
 
 import (
+	"bufio"
 	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"sync"
+	"time"
 
 	"github.com/amoylab/unla/internal/template"
 
-	"github.com/mark3labs/mcp-go/client"
-	"github.com/mark3labs/mcp-go/client/transport"
-	mcpgo "github.com/mark3labs/mcp-go/mcp"
 	"github.com/amoylab/unla/internal/common/cnst"
 	"github.com/amoylab/unla/internal/common/config"
 	"github.com/amoylab/unla/pkg/mcp"
 	"github.com/amoylab/unla/pkg/utils"
 	"github.com/amoylab/unla/pkg/version"
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/client/transport"
+	mcpgo "github.com/mark3labs/mcp-go/mcp"
+	"go.uber.org/zap"
 )
 
-// StdioTransport implements Transport using standard input/output
+const (
+	// stdioPingInterval is how often supervised child processes are probed
+	stdioPingInterval = 15 * time.Second
+	// stdioMaxRestartBackoff caps the exponential restart backoff
+	stdioMaxRestartBackoff = time.Minute
+	// stdioDrainTimeout bounds how long Stop waits for in-flight calls
+	stdioDrainTimeout = 10 * time.Second
+	// stdioInitTimeout bounds the initialize handshake with a fresh child;
+	// startLocked holds the transport lock, so a child that never answers
+	// must not stall other requests forever
+	stdioInitTimeout = 30 * time.Second
+)
+
+// StdioTransport implements Transport using standard input/output.
+// A single child process is maintained per backend (not a pool); the
+// supervisor restarts it with backoff when it stops responding.
 type StdioTransport struct {
-	client *client.Client
-	cfg    config.MCPServerConfig
+	// mu guards client, notifyAttached, stopSupervisor and the start/stop
+	// lifecycle so the supervisor and request goroutines never race
+	mu        sync.Mutex
+	client    *client.Client
+	cfg       config.MCPServerConfig
+	logger    *zap.Logger
+	keepAlive keepAlive
+	// notifyHandler receives backend notifications for forwarding
+	notifyHandler func(method string, params map[string]any)
+	// notifyAttached tracks whether the current client has the
+	// notification callback registered
+	notifyAttached bool
+
+	// inflight tracks running tool calls so Stop can drain gracefully
+	inflight sync.WaitGroup
+	// stopSupervisor terminates the crash-restart supervisor
+	stopSupervisor chan struct{}
 }
 
 var _ Transport = (*StdioTransport)(nil)
 
 func (t *StdioTransport) Start(ctx context.Context, tmplCtx *template.Context) error {
-	if t.IsRunning() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.startLocked(ctx, tmplCtx)
+}
+
+// startLocked spawns and initializes the child process; callers must hold mu
+func (t *StdioTransport) startLocked(ctx context.Context, tmplCtx *template.Context) error {
+	if t.client != nil {
 		return nil
 	}
 
@@ -47,14 +88,14 @@ func (t *StdioTransport) Start(ctx context.Context, tmplCtx *template.Context) e
 		t.cfg.Args...,
 	)
 
-	// Start the transport
-	if err := stdioTransport.Start(ctx); err != nil {
+	// Create client with the transport and start it through the client so
+	// the notification relay is wired up. The child process outlives the
+	// current request, so detach it from the caller's cancelation.
+	c := client.NewClient(stdioTransport)
+	if err := c.Start(context.WithoutCancel(ctx)); err != nil {
 		return fmt.Errorf("failed to start stdio transport: %w", err)
 	}
 
-	// Create client with the transport
-	c := client.NewClient(stdioTransport)
-
 	// Initialize the client
 	initRequest := mcpgo.InitializeRequest{}
 	initRequest.Params.ProtocolVersion = mcpgo.LATEST_PROTOCOL_VERSION
@@ -63,52 +104,220 @@ func (t *StdioTransport) Start(ctx context.Context, tmplCtx *template.Context) e
 		Version: version.Get(),
 	}
 
-	_, err := c.Initialize(ctx, initRequest)
+	initCtx, cancel := context.WithTimeout(ctx, stdioInitTimeout)
+	_, err := c.Initialize(initCtx, initRequest)
+	cancel()
 	if err != nil {
-		_ = stdioTransport.Close()
+		// Close waits for the child to exit; do it off the lock path
+		go func() { _ = stdioTransport.Close() }()
 		return fmt.Errorf("failed to initialize stdio client: %w", err)
 	}
 
+	// Forward the child's stderr into the gateway log
+	go t.captureStderr(stdioTransport)
+
 	t.client = c
+	t.notifyAttached = false
+	t.attachNotifyLocked()
+
+	// Keep long-lived processes alive across crashes
+	if t.cfg.Policy == cnst.PolicyOnStart && t.stopSupervisor == nil {
+		t.stopSupervisor = make(chan struct{})
+		go t.supervise(t.stopSupervisor)
+	}
 	return nil
 }
 
 func (t *StdioTransport) Stop(_ context.Context) error {
-	if !t.IsRunning() {
+	// Drain in-flight tool calls before killing the child process; the
+	// lock is not held while waiting so calls can finish
+	done := make(chan struct{})
+	go func() {
+		t.inflight.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(stdioDrainTimeout):
+		if t.logger != nil {
+			t.logger.Warn("timed out draining in-flight calls, stopping stdio backend",
+				zap.String("command", t.cfg.Command))
+		}
+	}
+
+	t.mu.Lock()
+	if t.stopSupervisor != nil {
+		close(t.stopSupervisor)
+		t.stopSupervisor = nil
+	}
+	old := t.detachLocked()
+	t.mu.Unlock()
+
+	if old == nil {
 		return nil
 	}
+	return old.Close()
+}
 
-	if t.client != nil {
-		err := t.client.Close()
-		if err != nil {
-			return err
+// detachLocked takes ownership of the current client without closing it.
+// Closing a stdio client waits for the child process to exit, which can
+// block indefinitely; callers must release mu before calling Close on the
+// returned client so a stuck child cannot wedge the whole transport.
+func (t *StdioTransport) detachLocked() *client.Client {
+	t.keepAlive.cancel()
+	c := t.client
+	t.client = nil
+	return c
+}
+
+// currentClient returns the live client under the lock
+func (t *StdioTransport) currentClient() *client.Client {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.client
+}
+
+// ensureClient returns the live client, starting one if needed
+func (t *StdioTransport) ensureClient(ctx context.Context, tmplCtx *template.Context) (*client.Client, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.client == nil {
+		if err := t.startLocked(ctx, tmplCtx); err != nil {
+			return nil, err
 		}
-		t.client = nil
 	}
+	return t.client, nil
+}
 
-	return nil
+// replaceClient swaps out a stale client for a fresh one. When another
+// goroutine already replaced it, the current client is returned as-is so
+// concurrent recoveries don't stop over each other.
+func (t *StdioTransport) replaceClient(ctx context.Context, tmplCtx *template.Context, stale *client.Client) (*client.Client, error) {
+	t.mu.Lock()
+	if t.client != nil && t.client != stale {
+		c := t.client
+		t.mu.Unlock()
+		return c, nil
+	}
+	old := t.detachLocked()
+	startErr := t.startLocked(ctx, tmplCtx)
+	c := t.client
+	t.mu.Unlock()
+
+	if old != nil {
+		// Close outside the lock; a child that ignores stdin EOF would
+		// otherwise block every other request on this transport
+		go func() { _ = old.Close() }()
+	}
+	if startErr != nil {
+		return nil, startErr
+	}
+	return c, nil
+}
+
+// clientDead reports whether the child behind a client has stopped
+// responding, using the same liveness probe as the supervisor
+func (t *StdioTransport) clientDead(ctx context.Context, c *client.Client) bool {
+	pingCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	return c.Ping(pingCtx) != nil
+}
+
+// captureStderr copies the child process stderr into the gateway log until
+// the pipe is closed
+func (t *StdioTransport) captureStderr(stdioTransport *transport.Stdio) {
+	if t.logger == nil {
+		return
+	}
+	scanner := bufio.NewScanner(stdioTransport.Stderr())
+	for scanner.Scan() {
+		t.logger.Warn("stdio backend stderr",
+			zap.String("command", t.cfg.Command),
+			zap.String("line", scanner.Text()))
+	}
+}
+
+// supervise probes the child process and restarts it with exponential
+// backoff when it stops responding
+func (t *StdioTransport) supervise(stop chan struct{}) {
+	backoff := time.Second
+	ticker := time.NewTicker(stdioPingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			c := t.currentClient()
+			if c == nil {
+				continue
+			}
+
+			pingCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			err := c.Ping(pingCtx)
+			cancel()
+			if err == nil {
+				backoff = time.Second
+				continue
+			}
+
+			t.logger.Warn("stdio backend unresponsive, restarting",
+				zap.String("command", t.cfg.Command),
+				zap.Error(err))
+
+			for {
+				select {
+				case <-stop:
+					return
+				case <-time.After(backoff):
+				}
+				if backoff < stdioMaxRestartBackoff {
+					backoff *= 2
+				}
+				if _, err := t.replaceClient(context.Background(), template.NewContext(), c); err != nil {
+					t.logger.Error("failed to restart stdio backend",
+						zap.String("command", t.cfg.Command),
+						zap.Duration("backoff", backoff),
+						zap.Error(err))
+					continue
+				}
+				t.logger.Info("stdio backend restarted",
+					zap.String("command", t.cfg.Command))
+				break
+			}
+		}
+	}
 }
 
 func (t *StdioTransport) IsRunning() bool {
-	return t.client != nil
+	return t.currentClient() != nil
 }
 
 func (t *StdioTransport) FetchTools(ctx context.Context) ([]mcp.ToolSchema, error) {
-	if !t.IsRunning() {
-		if err := t.Start(ctx, template.NewContext()); err != nil {
-			return nil, err
-		}
+	c, err := t.ensureClient(ctx, template.NewContext())
+	if err != nil {
+		return nil, err
 	}
-	defer func() {
-		if t.cfg.Policy == cnst.PolicyOnDemand {
-			_ = t.Stop(ctx)
-		}
-	}()
+	defer t.scheduleIdleStop()
 
 	// List available tools
-	toolsResult, err := t.client.ListTools(ctx, mcpgo.ListToolsRequest{})
+	toolsResult, err := c.ListTools(ctx, mcpgo.ListToolsRequest{})
 	if err != nil {
-		return nil, fmt.Errorf("failed to list tools: %w", err)
+		// A reused client may have gone stale; re-initialize once and
+		// retry, but only when the child is actually unresponsive — the
+		// error may be an application-level response from a live backend
+		if !t.clientDead(ctx, c) {
+			return nil, fmt.Errorf("failed to list tools: %w", err)
+		}
+		c, startErr := t.replaceClient(ctx, template.NewContext(), c)
+		if startErr != nil {
+			return nil, fmt.Errorf("failed to list tools: %w", err)
+		}
+		toolsResult, err = c.ListTools(ctx, mcpgo.ListToolsRequest{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list tools: %w", err)
+		}
 	}
 
 	// Convert from mcpgo.Tool to mcp.ToolSchema
@@ -148,6 +357,7 @@ func (t *StdioTransport) FetchTools(ctx context.Context) ([]mcp.ToolSchema, erro
 			Name:        schema.Name,
 			Description: schema.Description,
 			InputSchema: inputSchema,
+			Annotations: convertAnnotations(schema.Annotations),
 		}
 	}
 
@@ -155,25 +365,23 @@ func (t *StdioTransport) FetchTools(ctx context.Context) ([]mcp.ToolSchema, erro
 }
 
 func (t *StdioTransport) CallTool(ctx context.Context, params mcp.CallToolParams, req *template.RequestWrapper) (*mcp.CallToolResult, error) {
-	if !t.IsRunning() {
-		var args map[string]any
-		if err := json.Unmarshal(params.Arguments, &args); err != nil {
-			return nil, fmt.Errorf("invalid tool arguments: %w", err)
-		}
-		tmplCtx, err := template.AssembleTemplateContext(req, args, nil)
-		if err != nil {
-			return nil, err
-		}
+	var args map[string]any
+	if err := json.Unmarshal(params.Arguments, &args); err != nil {
+		return nil, fmt.Errorf("invalid tool arguments: %w", err)
+	}
+	tmplCtx, err := template.AssembleTemplateContext(req, args, nil)
+	if err != nil {
+		return nil, err
+	}
 
-		if err := t.Start(ctx, tmplCtx); err != nil {
-			return nil, err
-		}
+	c, err := t.ensureClient(ctx, tmplCtx)
+	if err != nil {
+		return nil, err
 	}
-	defer func() {
-		if t.cfg.Policy == cnst.PolicyOnDemand {
-			_ = t.Stop(ctx)
-		}
-	}()
+	defer t.scheduleIdleStop()
+
+	t.inflight.Add(1)
+	defer t.inflight.Done()
 
 	toolCallRequestParams := make(map[string]any)
 	if err := json.Unmarshal(params.Arguments, &toolCallRequestParams); err != nil {
@@ -184,10 +392,28 @@ func (t *StdioTransport) CallTool(ctx context.Context, params mcp.CallToolParams
 	callRequest := mcpgo.CallToolRequest{}
 	callRequest.Params.Name = params.Name
 	callRequest.Params.Arguments = toolCallRequestParams
+	if params.Meta.ProgressToken != nil {
+		// Forward the caller's progress token so backend progress
+		// notifications can be routed back to the originating session
+		callRequest.Params.Meta = &mcpgo.Meta{ProgressToken: params.Meta.ProgressToken}
+	}
 
-	mcpResult, err := t.client.CallTool(ctx, callRequest)
+	mcpResult, err := c.CallTool(ctx, callRequest)
 	if err != nil {
-		return nil, fmt.Errorf("failed to call tool: %w", err)
+		// The child process may have crashed; restart it once and retry,
+		// but only when it is actually unresponsive — the error may be an
+		// application-level response from a live backend
+		if !t.clientDead(ctx, c) {
+			return nil, fmt.Errorf("failed to call tool: %w", err)
+		}
+		c, startErr := t.replaceClient(ctx, tmplCtx, c)
+		if startErr != nil {
+			return nil, fmt.Errorf("failed to call tool: %w", err)
+		}
+		mcpResult, err = c.CallTool(ctx, callRequest)
+		if err != nil {
+			return nil, fmt.Errorf("failed to call tool: %w", err)
+		}
 	}
 
 	// Convert mcp-go result to local mcp format
@@ -276,6 +502,14 @@ func (t *StdioTransport) CallTool(ctx context.Context, params mcp.CallToolParams
 	return result, nil
 }
 
+// scheduleIdleStop keeps an on-demand client initialized for reuse and shuts
+// it down only after the idle timeout expires
+func (t *StdioTransport) scheduleIdleStop() {
+	if t.cfg.Policy == cnst.PolicyOnDemand {
+		t.keepAlive.touch(func() { _ = t.Stop(context.Background()) })
+	}
+}
+
 // FetchPrompts returns all prompts
 func (t *StdioTransport) FetchPrompts(ctx context.Context) ([]mcp.PromptSchema, error) {
 	return []mcp.PromptSchema{}, nil
@@ -287,4 +521,37 @@ func (t *StdioTransport) FetchPrompt(ctx context.Context, name string) (*mcp.Pro
 		StatusCode: http.StatusNotFound,
 		Message:    "Prompt not found",
 	}
-}
\ No newline at end of file
+}
+
+// SetNotificationHandler implements NotificationForwarder; it attaches to
+// an already-running client as well so reloads can re-register safely
+func (t *StdioTransport) SetNotificationHandler(handler func(method string, params map[string]any)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.notifyHandler = handler
+	t.attachNotifyLocked()
+}
+
+// attachNotifyLocked registers the notification callback once per client;
+// the callback reads notifyHandler dynamically so handler swaps need no
+// re-registration. Callers must hold mu.
+func (t *StdioTransport) attachNotifyLocked() {
+	c := t.client
+	if c == nil || t.notifyAttached || t.notifyHandler == nil {
+		return
+	}
+	t.notifyAttached = true
+	c.OnNotification(func(n mcpgo.JSONRPCNotification) {
+		handler := t.notifyHandler
+		if handler == nil {
+			return
+		}
+		params, err := json.Marshal(n.Params)
+		if err != nil {
+			return
+		}
+		decoded := make(map[string]any)
+		_ = json.Unmarshal(params, &decoded)
+		handler(n.Method, decoded)
+	})
+}
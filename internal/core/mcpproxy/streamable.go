@@ -5,44 +5,92 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"sync"
 
+	"github.com/amoylab/unla/internal/auth"
 	"github.com/amoylab/unla/internal/common/cnst"
 
-	"github.com/mark3labs/mcp-go/client"
-	"github.com/mark3labs/mcp-go/client/transport"
-	mcpgo "github.com/mark3labs/mcp-go/mcp"
 	"github.com/amoylab/unla/internal/common/config"
 	"github.com/amoylab/unla/internal/template"
 	"github.com/amoylab/unla/pkg/mcp"
 	"github.com/amoylab/unla/pkg/version"
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/client/transport"
+	mcpgo "github.com/mark3labs/mcp-go/mcp"
 )
 
 // StreamableTransport implements Transport using Streamable HTTP
 type StreamableTransport struct {
-	client *client.Client
-	cfg    config.MCPServerConfig
+	// mu guards client, notifyAttached and the start/stop lifecycle so
+	// concurrent calls, idle eviction and recovery never race on the
+	// shared client
+	mu        sync.Mutex
+	client    *client.Client
+	cfg       config.MCPServerConfig
+	keepAlive keepAlive
+	// notifyHandler receives backend notifications for forwarding
+	notifyHandler func(method string, params map[string]any)
+	// notifyAttached tracks whether the current client has the
+	// notification callback registered
+	notifyAttached bool
 }
 
 var _ Transport = (*StreamableTransport)(nil)
 
 func (t *StreamableTransport) Start(ctx context.Context, tmplCtx *template.Context) error {
-	if t.IsRunning() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.startLocked(ctx, tmplCtx)
+}
+
+// startLocked connects and initializes the client; callers must hold mu
+func (t *StreamableTransport) startLocked(ctx context.Context, _ *template.Context) error {
+	if t.client != nil {
 		return nil
 	}
 
 	// Create streamable transport
-	streamableTransport, err := transport.NewStreamableHTTP(t.cfg.URL)
-	if err != nil {
-		return fmt.Errorf("failed to create Streamable HTTP transport: %w", err)
+	var opts []transport.StreamableHTTPCOption
+	if t.cfg.Headers != nil {
+		policy := t.cfg.Headers
+		opts = append(opts, transport.WithHTTPHeaderFunc(func(ctx context.Context) map[string]string {
+			return policyHeaders(ctx, policy)
+		}))
 	}
-
-	// Start the transport
-	if err := streamableTransport.Start(ctx); err != nil {
-		return fmt.Errorf("failed to start Streamable HTTP transport: %w", err)
+	if t.cfg.UpstreamAuth != nil {
+		token, err := auth.GetUpstreamTokenSource(t.cfg.UpstreamAuth).Token(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to fetch upstream token: %w", err)
+		}
+		opts = append(opts, transport.WithHTTPHeaders(map[string]string{
+			"Authorization": "Bearer " + token,
+		}))
+	}
+	// Try each replica until one accepts the connection
+	var (
+		c       *client.Client
+		lastErr error
+	)
+	for _, url := range candidateURLs(&t.cfg) {
+		streamableTransport, err := transport.NewStreamableHTTP(url, opts...)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		// Create client with the transport and start it through the client
+		// so the notification relay is wired up. The connection outlives
+		// the current request, so detach it from the caller's cancelation.
+		candidate := client.NewClient(streamableTransport)
+		if err := candidate.Start(context.WithoutCancel(ctx)); err != nil {
+			lastErr = err
+			continue
+		}
+		c = candidate
+		break
+	}
+	if c == nil {
+		return fmt.Errorf("failed to start Streamable HTTP transport: %w", lastErr)
 	}
-
-	// Create client with the transport
-	c := client.NewClient(streamableTransport)
 
 	// Initialize the client
 	initRequest := mcpgo.InitializeRequest{}
@@ -52,47 +100,88 @@ func (t *StreamableTransport) Start(ctx context.Context, tmplCtx *template.Conte
 		Version: version.Get(),
 	}
 
-	_, err = c.Initialize(ctx, initRequest)
-	if err != nil {
-		_ = streamableTransport.Close()
+	if _, err := c.Initialize(ctx, initRequest); err != nil {
+		_ = c.Close()
 		return fmt.Errorf("failed to initialize streamable client: %w", err)
 	}
 
 	t.client = c
+	t.notifyAttached = false
+	t.attachNotifyLocked()
 	return nil
 }
 
 func (t *StreamableTransport) Stop(_ context.Context) error {
-	if !t.IsRunning() {
-		return nil
-	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.stopLocked()
+}
 
-	if t.client != nil {
-		err := t.client.Close()
-		if err != nil {
-			return err
-		}
-		t.client = nil
+// stopLocked closes the client; callers must hold mu
+func (t *StreamableTransport) stopLocked() error {
+	t.keepAlive.cancel()
+	if t.client == nil {
+		return nil
 	}
 
-	return nil
+	err := t.client.Close()
+	t.client = nil
+	return err
 }
 
 func (t *StreamableTransport) IsRunning() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
 	return t.client != nil
 }
 
-func (t *StreamableTransport) FetchTools(ctx context.Context) ([]mcp.ToolSchema, error) {
-	if !t.IsRunning() {
-		if err := t.Start(ctx, nil); err != nil {
+// ensureClient returns the live client, starting one if needed
+func (t *StreamableTransport) ensureClient(ctx context.Context, tmplCtx *template.Context) (*client.Client, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.client == nil {
+		if err := t.startLocked(ctx, tmplCtx); err != nil {
 			return nil, err
 		}
 	}
+	return t.client, nil
+}
+
+// replaceClient swaps out a stale client for a fresh one. When another
+// goroutine already replaced it, the current client is returned as-is so
+// concurrent recoveries don't stop over each other.
+func (t *StreamableTransport) replaceClient(ctx context.Context, tmplCtx *template.Context, stale *client.Client) (*client.Client, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.client != nil && t.client != stale {
+		return t.client, nil
+	}
+	_ = t.stopLocked()
+	if err := t.startLocked(ctx, tmplCtx); err != nil {
+		return nil, err
+	}
+	return t.client, nil
+}
+
+func (t *StreamableTransport) FetchTools(ctx context.Context) ([]mcp.ToolSchema, error) {
+	c, err := t.ensureClient(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer t.scheduleIdleStop()
 
 	// List available tools
-	toolsResult, err := t.client.ListTools(ctx, mcpgo.ListToolsRequest{})
+	toolsResult, err := c.ListTools(ctx, mcpgo.ListToolsRequest{})
 	if err != nil {
-		return nil, fmt.Errorf("failed to list tools: %w", err)
+		// A reused client may have gone stale; re-initialize once and retry
+		c, startErr := t.replaceClient(ctx, nil, c)
+		if startErr != nil {
+			return nil, fmt.Errorf("failed to list tools: %w", err)
+		}
+		toolsResult, err = c.ListTools(ctx, mcpgo.ListToolsRequest{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list tools: %w", err)
+		}
 	}
 
 	// Convert from mcpgo.Tool to mcp.ToolSchema
@@ -132,6 +221,7 @@ func (t *StreamableTransport) FetchTools(ctx context.Context) ([]mcp.ToolSchema,
 			Name:        schema.Name,
 			Description: schema.Description,
 			InputSchema: inputSchema,
+			Annotations: convertAnnotations(schema.Annotations),
 		}
 	}
 
@@ -139,25 +229,11 @@ func (t *StreamableTransport) FetchTools(ctx context.Context) ([]mcp.ToolSchema,
 }
 
 func (t *StreamableTransport) CallTool(ctx context.Context, params mcp.CallToolParams, req *template.RequestWrapper) (*mcp.CallToolResult, error) {
-	if !t.IsRunning() {
-		var args map[string]any
-		if err := json.Unmarshal(params.Arguments, &args); err != nil {
-			return nil, fmt.Errorf("invalid tool arguments: %w", err)
-		}
-		tmplCtx, err := template.AssembleTemplateContext(req, args, nil)
-		if err != nil {
-			return nil, err
-		}
-
-		if err := t.Start(ctx, tmplCtx); err != nil {
-			return nil, err
-		}
+	c, err := t.ensureClient(ctx, nil)
+	if err != nil {
+		return nil, err
 	}
-	defer func() {
-		if t.cfg.Policy == cnst.PolicyOnDemand {
-			_ = t.Stop(ctx)
-		}
-	}()
+	defer t.scheduleIdleStop()
 
 	// Prepare tool call request parameters
 	toolCallRequestParams := make(map[string]interface{})
@@ -169,15 +245,39 @@ func (t *StreamableTransport) CallTool(ctx context.Context, params mcp.CallToolP
 	callRequest := mcpgo.CallToolRequest{}
 	callRequest.Params.Name = params.Name
 	callRequest.Params.Arguments = toolCallRequestParams
+	if params.Meta.ProgressToken != nil {
+		// Forward the caller's progress token so backend progress
+		// notifications can be routed back to the originating session
+		callRequest.Params.Meta = &mcpgo.Meta{ProgressToken: params.Meta.ProgressToken}
+	}
 
-	res, err := t.client.CallTool(ctx, callRequest)
+	if req != nil {
+		ctx = WithForwardHeaders(ctx, req.Headers)
+	}
+	res, err := c.CallTool(ctx, callRequest)
 	if err != nil {
-		return nil, fmt.Errorf("failed to call tool: %w", err)
+		// A reused client may have gone stale; re-initialize once and retry
+		c, startErr := t.replaceClient(ctx, nil, c)
+		if startErr != nil {
+			return nil, fmt.Errorf("failed to call tool: %w", err)
+		}
+		res, err = c.CallTool(ctx, callRequest)
+		if err != nil {
+			return nil, fmt.Errorf("failed to call tool: %w", err)
+		}
 	}
 
 	return convertMCPGoResult(res), nil
 }
 
+// scheduleIdleStop keeps an on-demand client initialized for reuse and shuts
+// it down only after the idle timeout expires
+func (t *StreamableTransport) scheduleIdleStop() {
+	if t.cfg.Policy == cnst.PolicyOnDemand {
+		t.keepAlive.touch(func() { _ = t.Stop(context.Background()) })
+	}
+}
+
 // FetchPrompts returns all prompts
 func (t *StreamableTransport) FetchPrompts(ctx context.Context) ([]mcp.PromptSchema, error) {
 	return []mcp.PromptSchema{}, nil
@@ -189,4 +289,37 @@ func (t *StreamableTransport) FetchPrompt(ctx context.Context, name string) (*mc
 		StatusCode: http.StatusNotFound,
 		Message:    "Prompt not found",
 	}
-}
\ No newline at end of file
+}
+
+// SetNotificationHandler implements NotificationForwarder; it attaches to
+// an already-running client as well so reloads can re-register safely
+func (t *StreamableTransport) SetNotificationHandler(handler func(method string, params map[string]any)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.notifyHandler = handler
+	t.attachNotifyLocked()
+}
+
+// attachNotifyLocked registers the notification callback once per client;
+// the callback reads notifyHandler dynamically so handler swaps need no
+// re-registration. Callers must hold mu.
+func (t *StreamableTransport) attachNotifyLocked() {
+	c := t.client
+	if c == nil || t.notifyAttached || t.notifyHandler == nil {
+		return
+	}
+	t.notifyAttached = true
+	c.OnNotification(func(n mcpgo.JSONRPCNotification) {
+		handler := t.notifyHandler
+		if handler == nil {
+			return
+		}
+		params, err := json.Marshal(n.Params)
+		if err != nil {
+			return
+		}
+		decoded := make(map[string]any)
+		_ = json.Unmarshal(params, &decoded)
+		handler(n.Method, decoded)
+	})
+}
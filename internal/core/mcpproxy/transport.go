@@ -7,6 +7,8 @@ import (
 	"github.com/amoylab/unla/internal/common/config"
 	"github.com/amoylab/unla/internal/template"
 	"github.com/amoylab/unla/pkg/mcp"
+
+	"go.uber.org/zap"
 )
 
 // TransportType represents the type of transport
@@ -21,6 +23,14 @@ const (
 	TypeStreamable TransportType = "streamable-http"
 )
 
+// NotificationForwarder is implemented by transports that can surface
+// server-initiated notifications (e.g. progress) from the proxied backend
+type NotificationForwarder interface {
+	// SetNotificationHandler registers the handler invoked for every
+	// notification received from the backend
+	SetNotificationHandler(handler func(method string, params map[string]any))
+}
+
 // Transport defines the interface for MCP transport implementations
 type Transport interface {
 	// FetchTools fetches the list of available tools
@@ -44,16 +54,45 @@ type Transport interface {
 	FetchPrompt(ctx context.Context, name string) (*mcp.PromptSchema, error)
 }
 
-// NewTransport creates transport based on the configuration
-func NewTransport(cfg config.MCPServerConfig) (Transport, error) {
-	switch TransportType(cfg.Type) {
-	case TypeSSE:
+// TransportFactory builds a transport for one backend type
+type TransportFactory func(logger *zap.Logger, cfg config.MCPServerConfig) (Transport, error)
+
+// transportRegistry maps backend types to their factories so new upstream
+// kinds (gRPC, WASM, ...) can be added without touching dispatch code
+var transportRegistry = map[TransportType]TransportFactory{}
+
+// RegisterTransport installs a factory for a backend type; later
+// registrations for the same type replace earlier ones
+func RegisterTransport(transportType TransportType, factory TransportFactory) {
+	transportRegistry[transportType] = factory
+}
+
+func init() {
+	RegisterTransport(TypeSSE, func(_ *zap.Logger, cfg config.MCPServerConfig) (Transport, error) {
 		return &SSETransport{cfg: cfg}, nil
-	case TypeStdio:
-		return &StdioTransport{cfg: cfg}, nil
-	case TypeStreamable:
+	})
+	RegisterTransport(TypeStdio, func(logger *zap.Logger, cfg config.MCPServerConfig) (Transport, error) {
+		return &StdioTransport{cfg: cfg, logger: logger.Named("mcpproxy.stdio")}, nil
+	})
+	RegisterTransport(TypeStreamable, func(_ *zap.Logger, cfg config.MCPServerConfig) (Transport, error) {
 		return &StreamableTransport{cfg: cfg}, nil
-	default:
+	})
+}
+
+// NewTransport creates transport based on the configuration
+func NewTransport(logger *zap.Logger, cfg config.MCPServerConfig) (Transport, error) {
+	factory, ok := transportRegistry[TransportType(cfg.Type)]
+	if !ok {
 		return nil, fmt.Errorf("unknown transport type: %s", cfg.Type)
 	}
+
+	transport, err := factory(logger, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Resilience != nil {
+		transport = newResilientTransport(logger, transport, cfg)
+	}
+	return transport, nil
 }
@@ -0,0 +1,54 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"os"
+	"time"
+
+	"github.com/amoylab/unla/internal/common/config"
+	"github.com/amoylab/unla/internal/template"
+	"github.com/amoylab/unla/pkg/mcp"
+)
+
+// invokeMockTool serves a canned response with optional artificial latency
+// and error injection, so clients and CI can run without live upstreams
+func invokeMockTool(ctx context.Context, tool *config.ToolConfig, args map[string]any, restricted bool) (*mcp.CallToolResult, error) {
+	cfg := tool.Mock
+
+	if cfg.LatencyMs > 0 {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(time.Duration(cfg.LatencyMs) * time.Millisecond):
+		}
+	}
+
+	if cfg.ErrorRate > 0 && rand.Float64() < cfg.ErrorRate {
+		return mcp.NewCallToolResultError("mock error injected"), nil
+	}
+
+	body := cfg.Response
+	if cfg.File != "" {
+		if restricted {
+			// Reading arbitrary gateway files is not available to
+			// untrusted tenants
+			return nil, fmt.Errorf("mock fixture files are disabled for restricted configs")
+		}
+		data, err := os.ReadFile(cfg.File)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read mock fixture: %w", err)
+		}
+		body = string(data)
+	}
+
+	tmplCtx := template.NewContext()
+	tmplCtx.Args = args
+	tmplCtx.Restricted = restricted
+	rendered, err := template.RenderTemplate(body, tmplCtx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render mock response: %w", err)
+	}
+	return mcp.NewCallToolResultText(rendered), nil
+}
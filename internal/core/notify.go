@@ -0,0 +1,198 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/amoylab/unla/internal/core/mcpproxy"
+	"github.com/amoylab/unla/internal/core/state"
+	"github.com/amoylab/unla/internal/mcp/session"
+	"github.com/amoylab/unla/pkg/mcp"
+
+	"go.uber.org/zap"
+)
+
+// attachNotificationForwarders registers the upstream notification handler
+// on every transport that supports it; called after each state swap
+func (s *Server) attachNotificationForwarders(st *state.State) {
+	for _, transport := range st.GetTransports() {
+		if forwarder, ok := transport.(mcpproxy.NotificationForwarder); ok {
+			forwarder.SetNotificationHandler(s.forwardUpstreamNotification)
+		}
+	}
+}
+
+// forwardUpstreamNotification relays a backend notification to the session
+// that initiated the call carrying its progress token
+func (s *Server) forwardUpstreamNotification(method string, params map[string]any) {
+	if method != mcp.NotificationProgress {
+		return
+	}
+	token, ok := params["progressToken"]
+	if !ok {
+		return
+	}
+
+	value, ok := s.progressRoutes.Load(fmt.Sprint(token))
+	if !ok {
+		return
+	}
+	route := value.(progressRoute)
+	conn := route.conn
+	// Restore the token the client chose before relaying
+	params["progressToken"] = route.clientToken
+
+	data, err := json.Marshal(struct {
+		JSONRPC string `json:"jsonrpc"`
+		Method  string `json:"method"`
+		Params  any    `json:"params"`
+	}{
+		JSONRPC: mcp.JSPNRPCVersion,
+		Method:  method,
+		Params:  params,
+	})
+	if err != nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := conn.Send(ctx, &session.Message{Event: "message", Data: data}); err != nil {
+		s.logger.Warn("failed to forward upstream notification",
+			zap.String("session_id", conn.Meta().ID),
+			zap.Error(err))
+	}
+}
+
+// progressRoute ties a gateway-issued upstream token back to the calling
+// session and the token that session chose
+type progressRoute struct {
+	conn        session.Connection
+	clientToken any
+}
+
+// routeProgressToken maps a tool call's progress token to the calling
+// session for the duration of the call. The token forwarded upstream is
+// rewritten to a gateway-unique value so client-chosen tokens from
+// different sessions can never collide; the returned func removes the
+// route.
+func (s *Server) routeProgressToken(token any, conn session.Connection) (any, func()) {
+	if token == nil {
+		return nil, func() {}
+	}
+	upstream := fmt.Sprintf("unla-%d", s.progressSeq.Add(1))
+	s.progressRoutes.Store(upstream, progressRoute{conn: conn, clientToken: token})
+	return upstream, func() { s.progressRoutes.Delete(upstream) }
+}
+
+// registerCancel maps a session's in-flight request id to its cancel func
+// so a notifications/cancelled message can abort the upstream work
+func (s *Server) registerCancel(sessionID string, requestID any, cancel context.CancelFunc) func() {
+	key := sessionID + "|" + fmt.Sprint(requestID)
+	s.cancelRoutes.Store(key, cancel)
+	return func() { s.cancelRoutes.Delete(key) }
+}
+
+// cancelToolCall aborts the in-flight call identified by a cancellation
+// notification; unknown ids are ignored per the MCP spec
+func (s *Server) cancelToolCall(sessionID string, requestID any) {
+	key := sessionID + "|" + fmt.Sprint(requestID)
+	if value, ok := s.cancelRoutes.LoadAndDelete(key); ok {
+		s.logger.Info("cancelling in-flight tool call",
+			zap.String("session_id", sessionID),
+			zap.Any("request_id", requestID))
+		value.(context.CancelFunc)()
+	}
+}
+
+// broadcastShutdownNotice sends a logging message notification to every
+// connected session so clients know the server is going away
+func (s *Server) broadcastShutdownNotice(ctx context.Context) {
+	data, err := json.Marshal(struct {
+		JSONRPC string `json:"jsonrpc"`
+		Method  string `json:"method"`
+		Params  any    `json:"params"`
+	}{
+		JSONRPC: mcp.JSPNRPCVersion,
+		Method:  mcp.NotificationMessage,
+		Params: map[string]any{
+			"level": "warning",
+			"data":  "server is shutting down",
+		},
+	})
+	if err != nil {
+		return
+	}
+
+	conns, err := s.sessions.List(ctx)
+	if err != nil {
+		s.logger.Warn("failed to list sessions for shutdown notice", zap.Error(err))
+		return
+	}
+	for _, conn := range conns {
+		_ = conn.Send(ctx, &session.Message{Event: "message", Data: data})
+	}
+}
+
+// notifyToolListChanged pushes notifications/tools/list_changed to all
+// active sessions whose prefix exposes a different tool set after a reload
+func (s *Server) notifyToolListChanged(ctx context.Context, oldState, newState *state.State) {
+	changed := make(map[string]struct{})
+	for _, prefix := range newState.GetPrefixes() {
+		if !reflect.DeepEqual(oldState.GetToolSchemas(prefix), newState.GetToolSchemas(prefix)) {
+			changed[prefix] = struct{}{}
+		}
+	}
+	// Prefixes that disappeared entirely also invalidate client tool lists
+	for _, prefix := range oldState.GetPrefixes() {
+		if newState.GetProtoType(prefix) == "" {
+			changed[prefix] = struct{}{}
+		}
+	}
+	if len(changed) == 0 {
+		return
+	}
+
+	// Notifications carry no id, so don't reuse JSONRPCBaseResult here
+	data, err := json.Marshal(struct {
+		JSONRPC string `json:"jsonrpc"`
+		Method  string `json:"method"`
+	}{
+		JSONRPC: mcp.JSPNRPCVersion,
+		Method:  mcp.NotificationToolListChanged,
+	})
+	if err != nil {
+		s.logger.Error("failed to marshal tools/list_changed notification", zap.Error(err))
+		return
+	}
+
+	conns, err := s.sessions.List(ctx)
+	if err != nil {
+		s.logger.Error("failed to list sessions for tools/list_changed notification", zap.Error(err))
+		return
+	}
+
+	notified := 0
+	for _, conn := range conns {
+		if _, ok := changed[conn.Meta().Prefix]; !ok {
+			continue
+		}
+		if err := conn.Send(ctx, &session.Message{
+			Event: "message",
+			Data:  data,
+		}); err != nil {
+			s.logger.Warn("failed to send tools/list_changed notification",
+				zap.String("session_id", conn.Meta().ID),
+				zap.Error(err))
+			continue
+		}
+		notified++
+	}
+
+	s.logger.Info("notified sessions about tool list changes",
+		zap.Int("changed_prefixes", len(changed)),
+		zap.Int("notified_sessions", notified))
+}
@@ -0,0 +1,108 @@
+package core
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/amoylab/unla/internal/common/config"
+	"github.com/amoylab/unla/internal/mcp/session"
+	"github.com/amoylab/unla/internal/template"
+	"github.com/amoylab/unla/pkg/mcp"
+
+	"go.uber.org/zap"
+)
+
+// invokePipelineTool runs a composite tool: each step calls another tool on
+// the same server, with templated arguments that can reference the original
+// args and previous step outputs
+func (s *Server) invokePipelineTool(conn session.Connection, tool *config.ToolConfig, args map[string]any, request *http.Request, server *config.ServerConfig) (*mcp.CallToolResult, error) {
+	prefix := conn.Meta().Prefix
+	steps := make(map[string]any)
+
+	restricted := s.isUntrustedTenant(prefix)
+	stepCtx := func() *template.Context {
+		tmplCtx := template.NewContext()
+		for k, v := range args {
+			tmplCtx.Args[k] = v
+		}
+		tmplCtx.Args["Steps"] = steps
+		tmplCtx.Restricted = restricted
+		return tmplCtx
+	}
+
+	for i, step := range tool.Pipeline.Steps {
+		stepName := step.Name
+		if stepName == "" {
+			stepName = fmt.Sprintf("step%d", i+1)
+		}
+
+		if step.Condition != "" {
+			rendered, err := template.RenderTemplate(step.Condition, stepCtx())
+			if err != nil {
+				return nil, fmt.Errorf("pipeline step %s: failed to render condition: %w", stepName, err)
+			}
+			if strings.TrimSpace(rendered) != "true" {
+				s.logger.Debug("pipeline step skipped by condition",
+					zap.String("tool", tool.Name),
+					zap.String("step", stepName))
+				continue
+			}
+		}
+
+		target := s.currentState().GetTool(prefix, step.Tool)
+		if target == nil {
+			return nil, fmt.Errorf("pipeline step %s: tool %q not found", stepName, step.Tool)
+		}
+		if target.Pipeline != nil {
+			return nil, fmt.Errorf("pipeline step %s: nested pipelines are not supported", stepName)
+		}
+
+		stepArgs := make(map[string]any, len(step.Arguments))
+		for name, argTmpl := range step.Arguments {
+			rendered, err := template.RenderTemplate(argTmpl, stepCtx())
+			if err != nil {
+				return nil, fmt.Errorf("pipeline step %s: failed to render argument %s: %w", stepName, name, err)
+			}
+			stepArgs[name] = rendered
+		}
+
+		result, err := s.executeHTTPTool(conn, target, stepArgs, request, server, nil)
+		if err != nil {
+			return nil, fmt.Errorf("pipeline step %s failed: %w", stepName, err)
+		}
+		if result.IsError {
+			return result, nil
+		}
+
+		output := ""
+		for _, content := range result.Content {
+			if text, ok := content.(*mcp.TextContent); ok {
+				output += text.Text
+			}
+		}
+		steps[stepName] = output
+	}
+
+	if tool.Pipeline.Result == "" {
+		// Default to the last step's output
+		var last string
+		for i := len(tool.Pipeline.Steps) - 1; i >= 0; i-- {
+			stepName := tool.Pipeline.Steps[i].Name
+			if stepName == "" {
+				stepName = fmt.Sprintf("step%d", i+1)
+			}
+			if output, ok := steps[stepName].(string); ok {
+				last = output
+				break
+			}
+		}
+		return mcp.NewCallToolResultText(last), nil
+	}
+
+	rendered, err := template.RenderTemplate(tool.Pipeline.Result, stepCtx())
+	if err != nil {
+		return nil, fmt.Errorf("failed to render pipeline result: %w", err)
+	}
+	return mcp.NewCallToolResultText(rendered), nil
+}
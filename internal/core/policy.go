@@ -0,0 +1,100 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/amoylab/unla/internal/mcp/session"
+	"github.com/amoylab/unla/pkg/mcp"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+var opaHTTPClient = &http.Client{Timeout: 5 * time.Second}
+
+// checkPolicy evaluates the configured OPA policy for a tool call. The OPA
+// document is expected to produce either a boolean or {"allow": bool}.
+// Returns false (and responds) when the call is denied.
+func (s *Server) checkPolicy(c *gin.Context, reqID any, conn session.Connection, tool string, arguments json.RawMessage) bool {
+	policyCfg := s.cfg.Policy
+	if policyCfg == nil || policyCfg.OPAURL == "" {
+		return true
+	}
+
+	var args map[string]any
+	_ = json.Unmarshal(arguments, &args)
+
+	input, err := json.Marshal(map[string]any{
+		"input": map[string]any{
+			"session_id":  conn.Meta().ID,
+			"prefix":      conn.Meta().Prefix,
+			"remote_addr": conn.Meta().RemoteAddr,
+			"client_name": conn.Meta().ClientName,
+			"api_key":     c.GetHeader("X-API-Key"),
+			"jwt_claims":  c.GetHeader("X-Jwt-Claims"),
+			"tool":        tool,
+			"arguments":   args,
+		},
+	})
+	if err != nil {
+		return true
+	}
+
+	deny := func(reason string) bool {
+		s.logger.Warn("tool call denied by policy",
+			zap.String("tool", tool),
+			zap.String("session_id", conn.Meta().ID),
+			zap.String("reason", reason))
+		s.sendProtocolError(c, reqID, "Tool call denied by policy", http.StatusForbidden, mcp.ErrorCodeMethodNotFound)
+		return false
+	}
+
+	req, err := http.NewRequestWithContext(c.Request.Context(), http.MethodPost, policyCfg.OPAURL, bytes.NewReader(input))
+	if err != nil {
+		return true
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := opaHTTPClient.Do(req)
+	if err != nil {
+		if policyCfg.FailOpen {
+			s.logger.Warn("policy endpoint unreachable, failing open", zap.Error(err))
+			return true
+		}
+		return deny("policy endpoint unreachable")
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Result json.RawMessage `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil || len(result.Result) == 0 {
+		if policyCfg.FailOpen {
+			return true
+		}
+		return deny("policy returned no result")
+	}
+
+	// Accept either a bare boolean or an object with an allow field
+	var allowed bool
+	if err := json.Unmarshal(result.Result, &allowed); err != nil {
+		var doc struct {
+			Allow bool `json:"allow"`
+		}
+		if err := json.Unmarshal(result.Result, &doc); err != nil {
+			if policyCfg.FailOpen {
+				return true
+			}
+			return deny("unrecognized policy result")
+		}
+		allowed = doc.Allow
+	}
+
+	if !allowed {
+		return deny("policy rejected the call")
+	}
+	return true
+}
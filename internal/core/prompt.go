@@ -0,0 +1,47 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/amoylab/unla/internal/template"
+	"github.com/amoylab/unla/pkg/mcp"
+)
+
+// renderPrompt substitutes the supplied arguments into a prompt's messages
+// and returns the prompts/get result. Both {name} placeholders and the
+// template engine syntax ({{.Args.name}}) are supported.
+func renderPrompt(prompt *mcp.PromptSchema, args map[string]string, restricted bool) (*mcp.GetPromptResult, error) {
+	tmplCtx := template.NewContext()
+	for k, v := range args {
+		tmplCtx.Args[k] = v
+	}
+	tmplCtx.Restricted = restricted
+
+	result := &mcp.GetPromptResult{
+		Description: prompt.Description,
+		Messages:    make([]mcp.PromptResponseSchema, 0, len(prompt.PromptResponse)),
+	}
+	for _, msg := range prompt.PromptResponse {
+		text := msg.Content.Text
+		for _, arg := range prompt.Arguments {
+			if val, ok := args[arg.Name]; ok {
+				text = strings.ReplaceAll(text, "{"+arg.Name+"}", val)
+			}
+		}
+
+		rendered, err := template.RenderTemplate(text, tmplCtx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render prompt template: %w", err)
+		}
+
+		result.Messages = append(result.Messages, mcp.PromptResponseSchema{
+			Role: msg.Role,
+			Content: mcp.PromptResponseContentSchema{
+				Type: msg.Content.Type,
+				Text: rendered,
+			},
+		})
+	}
+	return result, nil
+}
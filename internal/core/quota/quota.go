@@ -0,0 +1,162 @@
+package quota
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/amoylab/unla/internal/common/cnst"
+	"github.com/amoylab/unla/internal/common/config"
+	"github.com/amoylab/unla/pkg/utils"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// Counter tracks usage counts per key and period window
+type Counter interface {
+	// Incr increments the counter behind key and returns the new value.
+	// ttl bounds how long the counter is retained.
+	Incr(ctx context.Context, key string, ttl time.Duration) (int64, error)
+
+	// Get returns the current value of a counter
+	Get(ctx context.Context, key string) (int64, error)
+
+	// Close releases resources held by the counter store
+	Close() error
+}
+
+// DayKey and MonthKey build period-scoped counter keys
+func DayKey(base string, now time.Time) string {
+	return base + ":d:" + now.Format("2006-01-02")
+}
+
+func MonthKey(base string, now time.Time) string {
+	return base + ":m:" + now.Format("2006-01")
+}
+
+// NewCounter creates a counter store based on the configuration
+func NewCounter(logger *zap.Logger, cfg *config.QuotaConfig) (Counter, error) {
+	switch cfg.Storage {
+	case "redis":
+		return NewRedisCounter(logger, &cfg.Redis)
+	case "memory", "":
+		return NewMemoryCounter(), nil
+	default:
+		return nil, fmt.Errorf("unsupported quota storage: %s", cfg.Storage)
+	}
+}
+
+// MemoryCounter implements Counter in process memory
+type MemoryCounter struct {
+	mu        sync.Mutex
+	counts    map[string]int64
+	expiries  map[string]time.Time
+	lastSweep time.Time
+}
+
+var _ Counter = (*MemoryCounter)(nil)
+
+// NewMemoryCounter creates a new in-memory counter store
+func NewMemoryCounter() *MemoryCounter {
+	return &MemoryCounter{
+		counts:   make(map[string]int64),
+		expiries: make(map[string]time.Time),
+	}
+}
+
+// Incr implements Counter.Incr
+func (c *MemoryCounter) Incr(_ context.Context, key string, ttl time.Duration) (int64, error) {
+	now := time.Now()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if now.Sub(c.lastSweep) > time.Hour {
+		for k, expiry := range c.expiries {
+			if now.After(expiry) {
+				delete(c.counts, k)
+				delete(c.expiries, k)
+			}
+		}
+		c.lastSweep = now
+	}
+
+	if _, exists := c.counts[key]; !exists {
+		c.expiries[key] = now.Add(ttl)
+	}
+	c.counts[key]++
+	return c.counts[key], nil
+}
+
+// Get implements Counter.Get
+func (c *MemoryCounter) Get(_ context.Context, key string) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.counts[key], nil
+}
+
+// Close implements Counter.Close
+func (c *MemoryCounter) Close() error {
+	return nil
+}
+
+// RedisCounter implements Counter shared across gateway instances
+type RedisCounter struct {
+	client redis.UniversalClient
+	prefix string
+}
+
+var _ Counter = (*RedisCounter)(nil)
+
+// NewRedisCounter creates a new Redis-backed counter store
+func NewRedisCounter(logger *zap.Logger, cfg *config.RedisConfig) (*RedisCounter, error) {
+	addrs := utils.SplitByMultipleDelimiters(cfg.Addr, ";", ",")
+	redisOptions := &redis.UniversalOptions{
+		Addrs:    addrs,
+		Username: cfg.Username,
+		Password: cfg.Password,
+	}
+	if cfg.ClusterType == cnst.RedisClusterTypeSentinel {
+		redisOptions.MasterName = cfg.MasterName
+	}
+	if cfg.ClusterType != cnst.RedisClusterTypeCluster {
+		// can not set db in cluster mode
+		redisOptions.DB = cfg.DB
+	}
+	client := redis.NewUniversalClient(redisOptions)
+
+	// Test connection
+	if err := client.Ping(context.Background()).Err(); err != nil {
+		return nil, fmt.Errorf("failed to connect to Redis: %w", err)
+	}
+
+	return &RedisCounter{client: client, prefix: "quota:"}, nil
+}
+
+// Incr implements Counter.Incr
+func (c *RedisCounter) Incr(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	count, err := c.client.Incr(ctx, c.prefix+key).Result()
+	if err != nil {
+		return 0, err
+	}
+	if count == 1 {
+		_ = c.client.Expire(ctx, c.prefix+key, ttl).Err()
+	}
+	return count, nil
+}
+
+// Get implements Counter.Get
+func (c *RedisCounter) Get(ctx context.Context, key string) (int64, error) {
+	count, err := c.client.Get(ctx, c.prefix+key).Int64()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	return count, err
+}
+
+// Close implements Counter.Close
+func (c *RedisCounter) Close() error {
+	return c.client.Close()
+}
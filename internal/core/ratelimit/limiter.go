@@ -0,0 +1,39 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/amoylab/unla/internal/common/config"
+
+	"go.uber.org/zap"
+)
+
+// Rule describes a token-bucket limit: Rate tokens are refilled per second
+// with a maximum of Burst tokens available at once.
+type Rule struct {
+	Rate  float64
+	Burst int
+}
+
+// Limiter decides whether a request identified by key may proceed
+type Limiter interface {
+	// Allow consumes one token from the bucket identified by key,
+	// returning false when the bucket is empty
+	Allow(ctx context.Context, key string, rule Rule) (bool, error)
+
+	// Close releases resources held by the limiter
+	Close() error
+}
+
+// NewLimiter creates a limiter based on the configuration
+func NewLimiter(logger *zap.Logger, cfg *config.RateLimitConfig) (Limiter, error) {
+	switch cfg.Storage {
+	case "redis":
+		return NewRedisLimiter(logger, &cfg.Redis)
+	case "memory", "":
+		return NewMemoryLimiter(), nil
+	default:
+		return nil, fmt.Errorf("unsupported rate limit storage: %s", cfg.Storage)
+	}
+}
@@ -0,0 +1,77 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// idleBucketTTL is how long an untouched bucket is kept before the next
+// sweep drops it; buckets are keyed by session so they must not accumulate
+const idleBucketTTL = 10 * time.Minute
+
+// MemoryLimiter implements Limiter with in-process token buckets
+type MemoryLimiter struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+type bucket struct {
+	tokens   float64
+	lastFill time.Time
+}
+
+var _ Limiter = (*MemoryLimiter)(nil)
+
+// NewMemoryLimiter creates a new in-memory limiter
+func NewMemoryLimiter() *MemoryLimiter {
+	return &MemoryLimiter{
+		buckets: make(map[string]*bucket),
+	}
+}
+
+// Allow implements Limiter.Allow
+func (l *MemoryLimiter) Allow(_ context.Context, key string, rule Rule) (bool, error) {
+	if rule.Rate <= 0 {
+		return true, nil
+	}
+
+	now := time.Now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.Sub(l.lastSweep) > idleBucketTTL {
+		for k, b := range l.buckets {
+			if now.Sub(b.lastFill) > idleBucketTTL {
+				delete(l.buckets, k)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(rule.Burst), lastFill: now}
+		l.buckets[key] = b
+	}
+
+	// Refill tokens according to the elapsed time, capped at the burst size
+	b.tokens += now.Sub(b.lastFill).Seconds() * rule.Rate
+	if b.tokens > float64(rule.Burst) {
+		b.tokens = float64(rule.Burst)
+	}
+	b.lastFill = now
+
+	if b.tokens < 1 {
+		return false, nil
+	}
+	b.tokens--
+	return true, nil
+}
+
+// Close implements Limiter.Close
+func (l *MemoryLimiter) Close() error {
+	return nil
+}
@@ -0,0 +1,91 @@
+package core
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/amoylab/unla/internal/common/config"
+	"github.com/amoylab/unla/internal/template"
+)
+
+// matchURITemplate matches a URI against an RFC 6570 style template containing
+// simple {variable} expressions and returns the extracted variable values.
+func matchURITemplate(uriTemplate, uri string) (map[string]any, bool) {
+	var (
+		pattern strings.Builder
+		names   []string
+	)
+	pattern.WriteString("^")
+	rest := uriTemplate
+	for {
+		start := strings.Index(rest, "{")
+		if start < 0 {
+			pattern.WriteString(regexp.QuoteMeta(rest))
+			break
+		}
+		end := strings.Index(rest[start:], "}")
+		if end < 0 {
+			pattern.WriteString(regexp.QuoteMeta(rest))
+			break
+		}
+		pattern.WriteString(regexp.QuoteMeta(rest[:start]))
+		names = append(names, rest[start+1:start+end])
+		pattern.WriteString("([^/]+)")
+		rest = rest[start+end+1:]
+	}
+	pattern.WriteString("$")
+
+	re, err := regexp.Compile(pattern.String())
+	if err != nil {
+		return nil, false
+	}
+	matches := re.FindStringSubmatch(uri)
+	if matches == nil {
+		return nil, false
+	}
+
+	args := make(map[string]any, len(names))
+	for i, name := range names {
+		args[name] = matches[i+1]
+	}
+	return args, true
+}
+
+// readResource resolves a resource URI against the static resources and
+// resource templates configured for the given prefix
+func (s *Server) readResource(prefix, uri string) (*config.ResourceConfig, map[string]any, error) {
+	if resource := s.currentState().GetResource(prefix, uri); resource != nil {
+		return resource, map[string]any{}, nil
+	}
+
+	for _, tmpl := range s.currentState().GetResourceTemplates(prefix) {
+		args, ok := matchURITemplate(tmpl.URITemplate, uri)
+		if !ok {
+			continue
+		}
+		return &config.ResourceConfig{
+			URI:         uri,
+			Name:        tmpl.Name,
+			Description: tmpl.Description,
+			MimeType:    tmpl.MimeType,
+			Text:        tmpl.Text,
+		}, args, nil
+	}
+
+	return nil, nil, fmt.Errorf("resource not found: %s", uri)
+}
+
+// renderResourceContents renders the resource text through the template engine
+// and wraps it into the MCP resource contents shape
+func renderResourceContents(resource *config.ResourceConfig, args map[string]any, restricted bool) (string, error) {
+	tmplCtx := template.NewContext()
+	tmplCtx.Args = args
+	tmplCtx.Restricted = restricted
+
+	text, err := template.RenderTemplate(resource.Text, tmplCtx)
+	if err != nil {
+		return "", fmt.Errorf("failed to render resource template: %w", err)
+	}
+	return text, nil
+}
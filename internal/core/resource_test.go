@@ -0,0 +1,73 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchURITemplate(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		uri      string
+		expected map[string]any
+		matches  bool
+	}{
+		{
+			name:     "single variable",
+			template: "doc://users/{id}",
+			uri:      "doc://users/42",
+			expected: map[string]any{"id": "42"},
+			matches:  true,
+		},
+		{
+			name:     "multiple variables",
+			template: "doc://{tenant}/reports/{year}",
+			uri:      "doc://acme/reports/2026",
+			expected: map[string]any{"tenant": "acme", "year": "2026"},
+			matches:  true,
+		},
+		{
+			name:     "variable does not span segments",
+			template: "doc://users/{id}",
+			uri:      "doc://users/42/posts",
+			matches:  false,
+		},
+		{
+			name:     "literal mismatch",
+			template: "doc://users/{id}",
+			uri:      "doc://accounts/42",
+			matches:  false,
+		},
+		{
+			name:     "no variables is an exact match",
+			template: "doc://readme",
+			uri:      "doc://readme",
+			expected: map[string]any{},
+			matches:  true,
+		},
+		{
+			name:     "regex metacharacters in literals are quoted",
+			template: "doc://v1.0/{id}",
+			uri:      "doc://v1x0/42",
+			matches:  false,
+		},
+		{
+			name:     "empty segment does not match",
+			template: "doc://users/{id}",
+			uri:      "doc://users/",
+			matches:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args, ok := matchURITemplate(tt.template, tt.uri)
+			assert.Equal(t, tt.matches, ok)
+			if tt.matches {
+				assert.Equal(t, tt.expected, args)
+			}
+		})
+	}
+}
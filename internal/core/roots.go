@@ -0,0 +1,117 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/amoylab/unla/internal/mcp/session"
+	"github.com/amoylab/unla/pkg/mcp"
+
+	"go.uber.org/zap"
+)
+
+// rootsRequestID identifies the gateway-initiated roots/list request so the
+// client's answer can be routed back to the session cache
+const rootsRequestID = "gateway-roots-list"
+
+// Root mirrors the MCP root descriptor
+type Root struct {
+	URI  string `json:"uri"`
+	Name string `json:"name,omitempty"`
+}
+
+// sessionRoots caches the roots each client has reported
+type sessionRoots struct {
+	mu    sync.RWMutex
+	roots map[string][]Root
+}
+
+// newSessionRoots creates the per-session roots cache
+func newSessionRoots() *sessionRoots {
+	return &sessionRoots{roots: make(map[string][]Root)}
+}
+
+func (r *sessionRoots) set(sessionID string, roots []Root) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.roots[sessionID] = roots
+}
+
+func (r *sessionRoots) get(sessionID string) []Root {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.roots[sessionID]
+}
+
+func (r *sessionRoots) delete(sessionID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.roots, sessionID)
+}
+
+// retain drops roots for sessions not in the live set, reporting
+// how many entries were removed
+func (r *sessionRoots) retain(live map[string]struct{}) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	removed := 0
+	for id := range r.roots {
+		if _, ok := live[id]; !ok {
+			delete(r.roots, id)
+			removed++
+		}
+	}
+	return removed
+}
+
+// requestClientRoots asks the connected client for its roots; the answer
+// arrives as a response message handled by handleClientResponse
+func (s *Server) requestClientRoots(ctx context.Context, conn session.Connection) {
+	data, err := json.Marshal(struct {
+		JSONRPC string `json:"jsonrpc"`
+		ID      string `json:"id"`
+		Method  string `json:"method"`
+	}{
+		JSONRPC: mcp.JSPNRPCVersion,
+		ID:      rootsRequestID,
+		Method:  "roots/list",
+	})
+	if err != nil {
+		return
+	}
+	if err := conn.Send(ctx, &session.Message{Event: "message", Data: data}); err != nil {
+		s.logger.Debug("failed to request client roots",
+			zap.String("session_id", conn.Meta().ID),
+			zap.Error(err))
+	}
+}
+
+// handleClientResponse routes JSON-RPC responses posted by the client (as
+// opposed to requests); currently only roots/list answers are expected.
+// It returns true when the message was consumed.
+func (s *Server) handleClientResponse(raw json.RawMessage, conn session.Connection) bool {
+	var resp struct {
+		ID     any `json:"id"`
+		Result *struct {
+			Roots []Root `json:"roots"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(raw, &resp); err != nil || resp.Result == nil {
+		return false
+	}
+	if id, ok := resp.ID.(string); !ok || id != rootsRequestID {
+		return false
+	}
+
+	s.clientRoots.set(conn.Meta().ID, resp.Result.Roots)
+	s.logger.Info("cached client roots",
+		zap.String("session_id", conn.Meta().ID),
+		zap.Int("root_count", len(resp.Result.Roots)))
+	return true
+}
+
+// GetSessionRoots returns the cached roots for a session
+func (s *Server) GetSessionRoots(sessionID string) []Root {
+	return s.clientRoots.get(sessionID)
+}
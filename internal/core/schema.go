@@ -0,0 +1,185 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/amoylab/unla/pkg/mcp"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// validateAgainstSchema checks a decoded JSON value against a JSON Schema
+// subset: type, required, properties, items and enum. It returns the list
+// of violations so callers can fail or warn.
+func validateAgainstSchema(value any, schema map[string]any, path string) []string {
+	var violations []string
+	if path == "" {
+		path = "$"
+	}
+
+	if typeName, ok := schema["type"].(string); ok {
+		if !matchesSchemaType(value, typeName) {
+			violations = append(violations,
+				fmt.Sprintf("%s: expected %s, got %s", path, typeName, jsonTypeName(value)))
+			return violations
+		}
+	}
+
+	if enum, ok := schema["enum"].([]any); ok && len(enum) > 0 {
+		matched := false
+		for _, allowed := range enum {
+			if fmt.Sprint(allowed) == fmt.Sprint(value) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			violations = append(violations, fmt.Sprintf("%s: value not in enum", path))
+		}
+	}
+
+	if obj, ok := value.(map[string]any); ok {
+		if required, ok := schema["required"].([]any); ok {
+			for _, rawName := range required {
+				name, _ := rawName.(string)
+				if _, present := obj[name]; !present {
+					violations = append(violations,
+						fmt.Sprintf("%s: missing required property %q", path, name))
+				}
+			}
+		}
+		if properties, ok := schema["properties"].(map[string]any); ok {
+			for name, rawPropSchema := range properties {
+				propSchema, ok := rawPropSchema.(map[string]any)
+				if !ok {
+					continue
+				}
+				propValue, present := obj[name]
+				if !present {
+					continue
+				}
+				violations = append(violations,
+					validateAgainstSchema(propValue, propSchema, path+"."+name)...)
+			}
+		}
+	}
+
+	if arr, ok := value.([]any); ok {
+		if items, ok := schema["items"].(map[string]any); ok {
+			for i, element := range arr {
+				violations = append(violations,
+					validateAgainstSchema(element, items, fmt.Sprintf("%s[%d]", path, i))...)
+			}
+		}
+	}
+
+	return violations
+}
+
+// matchesSchemaType reports whether a decoded JSON value satisfies a JSON
+// Schema type name
+func matchesSchemaType(value any, typeName string) bool {
+	switch typeName {
+	case "object":
+		_, ok := value.(map[string]any)
+		return ok
+	case "array":
+		_, ok := value.([]any)
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "integer":
+		number, ok := value.(float64)
+		return ok && number == float64(int64(number))
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "null":
+		return value == nil
+	default:
+		return true
+	}
+}
+
+// jsonTypeName names a decoded JSON value's type for error messages
+func jsonTypeName(value any) string {
+	switch value.(type) {
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	case nil:
+		return "null"
+	default:
+		return strings.TrimPrefix(fmt.Sprintf("%T", value), "*")
+	}
+}
+
+// validateToolArgs checks HTTP tool arguments against the tool's composed
+// input schema according to the server's arg validation mode. It responds
+// with InvalidParams and returns false in strict mode.
+func (s *Server) validateToolArgs(c *gin.Context, reqID any, prefix, toolName string, args map[string]any) bool {
+	serverCfg := s.currentState().GetServerConfig(prefix)
+	if serverCfg == nil || serverCfg.ArgValidation == "" || serverCfg.ArgValidation == "off" {
+		return true
+	}
+	tool := s.currentState().GetTool(prefix, toolName)
+	if tool == nil {
+		return true
+	}
+
+	// Reuse the advertised schema so validation matches what clients see
+	raw, err := json.Marshal(tool.ToToolSchema().InputSchema)
+	if err != nil {
+		return true
+	}
+	schema := make(map[string]any)
+	if err := json.Unmarshal(raw, &schema); err != nil {
+		return true
+	}
+
+	violations := validateAgainstSchema(normalizeJSONValue(args), schema, "")
+	if len(violations) == 0 {
+		return true
+	}
+
+	if serverCfg.ArgValidation == "strict" {
+		s.sendProtocolError(c, reqID,
+			"Invalid tool arguments: "+strings.Join(violations, "; "),
+			http.StatusBadRequest, mcp.ErrorCodeInvalidParams)
+		return false
+	}
+
+	s.logger.Warn("tool arguments do not match the input schema",
+		zap.String("tool", toolName),
+		zap.Strings("violations", violations))
+	return true
+}
+
+// normalizeJSONValue re-decodes a value through JSON so numeric types match
+// what validateAgainstSchema expects
+func normalizeJSONValue(value any) any {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return value
+	}
+	var decoded any
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		return value
+	}
+	return decoded
+}
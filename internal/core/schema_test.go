@@ -0,0 +1,95 @@
+package core
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateAgainstSchema(t *testing.T) {
+	schema := map[string]any{
+		"type":     "object",
+		"required": []any{"name"},
+		"properties": map[string]any{
+			"name":  map[string]any{"type": "string"},
+			"count": map[string]any{"type": "integer"},
+			"level": map[string]any{"type": "string", "enum": []any{"low", "high"}},
+			"tags": map[string]any{
+				"type":  "array",
+				"items": map[string]any{"type": "string"},
+			},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		value      any
+		violations int
+		contains   string
+	}{
+		{
+			name:       "valid object",
+			value:      map[string]any{"name": "x", "count": float64(3), "level": "low", "tags": []any{"a"}},
+			violations: 0,
+		},
+		{
+			name:       "wrong top-level type",
+			value:      "not an object",
+			violations: 1,
+			contains:   "$: expected object",
+		},
+		{
+			name:       "missing required property",
+			value:      map[string]any{"count": float64(1)},
+			violations: 1,
+			contains:   `missing required property "name"`,
+		},
+		{
+			name:       "wrong property type",
+			value:      map[string]any{"name": "x", "count": "three"},
+			violations: 1,
+			contains:   "$.count: expected integer",
+		},
+		{
+			name:       "non-integral number is not an integer",
+			value:      map[string]any{"name": "x", "count": 1.5},
+			violations: 1,
+			contains:   "$.count: expected integer",
+		},
+		{
+			name:       "enum violation",
+			value:      map[string]any{"name": "x", "level": "medium"},
+			violations: 1,
+			contains:   "$.level: value not in enum",
+		},
+		{
+			name:       "array element type",
+			value:      map[string]any{"name": "x", "tags": []any{"ok", float64(7)}},
+			violations: 1,
+			contains:   "$.tags[1]: expected string",
+		},
+		{
+			name:       "multiple violations accumulate",
+			value:      map[string]any{"count": "three", "level": "medium"},
+			violations: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			violations := validateAgainstSchema(tt.value, schema, "")
+			assert.Len(t, violations, tt.violations)
+			if tt.contains != "" {
+				found := false
+				for _, v := range violations {
+					if strings.Contains(v, tt.contains) {
+						found = true
+						break
+					}
+				}
+				assert.True(t, found, "violations %v should contain %q", violations, tt.contains)
+			}
+		})
+	}
+}
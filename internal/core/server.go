@@ -4,18 +4,24 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/amoylab/unla/internal/auth"
+	jwtauth "github.com/amoylab/unla/internal/auth/jwt"
 	"github.com/amoylab/unla/internal/common/cnst"
 	"github.com/amoylab/unla/internal/common/config"
 	"github.com/amoylab/unla/internal/core/mcpproxy"
+	"github.com/amoylab/unla/internal/core/quota"
+	"github.com/amoylab/unla/internal/core/ratelimit"
 	"github.com/amoylab/unla/internal/core/state"
 	"github.com/amoylab/unla/internal/mcp/session"
 	"github.com/amoylab/unla/internal/mcp/storage"
+	"github.com/amoylab/unla/internal/template"
 	"github.com/amoylab/unla/pkg/mcp"
 
 	"github.com/gin-gonic/gin"
@@ -26,35 +32,101 @@ type (
 	// Server represents the MCP server
 	Server struct {
 		logger *zap.Logger
-		port   int
+		cfg    *config.MCPGatewayConfig
 		router *gin.Engine
-		// state contains all the read-only shared state
-		state *state.State
+		// state contains all the read-only shared state; it is swapped
+		// atomically on reload so in-flight requests and live SSE streams
+		// keep serving from a consistent snapshot
+		state atomic.Pointer[state.State]
 		// store is the storage service for MCP configs
 		store storage.Store
 		// sessions manages all active sessions
 		sessions session.Store
 		// shutdownCh is used to signal shutdown to all SSE connections
 		shutdownCh chan struct{}
+		// unixSockets tracks extra-listener socket paths for cleanup
+		unixSockets   []string
+		unixSocketsMu sync.Mutex
 		// toolRespHandler is a chain of response handlers
 		toolRespHandler ResponseHandler
 		lastUpdateTime  time.Time
 		auth            auth.Auth
+		// limiter enforces per-tool/per-prefix rate limits; nil when disabled
+		limiter ratelimit.Limiter
+		// jwtValidator validates OIDC tokens on prefixes with auth mode oidc
+		jwtValidator *jwtauth.Validator
+		// draining is set during shutdown so new sessions are rejected
+		// while in-flight tool calls complete
+		draining atomic.Bool
+		// inflight tracks running tool calls for graceful draining
+		inflight sync.WaitGroup
+		// accessLog writes the structured access log; nil when disabled
+		accessLog *zap.Logger
+		// auditSink persists tool invocation records; nil when disabled
+		auditSink AuditSink
+		// progressRoutes maps in-flight progress tokens to their sessions
+		progressRoutes sync.Map
+		// progressSeq numbers gateway-issued upstream progress tokens
+		progressSeq atomic.Uint64
+		// cancelRoutes maps in-flight request ids to their cancel funcs
+		cancelRoutes sync.Map
+		// clientRoots caches the roots reported by each client session
+		clientRoots *sessionRoots
+		// sessionLogLevels stores each session's logging/setLevel choice
+		sessionLogLevels sync.Map
+		// stats tracks per-session activity for the admin API
+		stats *sessionStats
+		// health stores backend probe results for the admin API
+		health *backendHealth
+		// toolCache holds proxied tool lists with TTL refresh
+		toolCache *toolListCache
+		// startTime is when this server instance was created
+		startTime time.Time
+		// lastReloadAt/lastReloadOK describe the most recent reload
+		lastReloadAt time.Time
+		lastReloadOK bool
+		// quota tracks daily/monthly call counters; nil when disabled
+		quota quota.Counter
 	}
 )
 
 // NewServer creates a new MCP server
-func NewServer(logger *zap.Logger, port int, store storage.Store, sessionStore session.Store, a auth.Auth) (*Server, error) {
+func NewServer(logger *zap.Logger, cfg *config.MCPGatewayConfig, store storage.Store, sessionStore session.Store, a auth.Auth) (*Server, error) {
 	s := &Server{
 		logger:          logger,
-		port:            port,
+		cfg:             cfg,
 		router:          gin.Default(),
-		state:           state.NewState(),
 		store:           store,
 		sessions:        sessionStore,
 		shutdownCh:      make(chan struct{}),
 		toolRespHandler: CreateResponseHandlerChain(),
 		auth:            a,
+		clientRoots:     newSessionRoots(),
+		stats:           newSessionStats(),
+		health:          newBackendHealth(),
+		toolCache:       newToolListCache(),
+		startTime:       time.Now(),
+	}
+	s.state.Store(state.NewState())
+	go s.sweepSessionState()
+	template.SetExecutionLimits(cfg.Limits.TemplateMaxOutputBytes, cfg.Limits.TemplateTimeout)
+
+	if cfg.Auth.JWT != nil {
+		s.jwtValidator = jwtauth.NewValidator(jwtauth.ValidationConfig{
+			JWKSURL:  cfg.Auth.JWT.JWKSURL,
+			Secret:   cfg.Auth.JWT.Secret,
+			Issuer:   cfg.Auth.JWT.Issuer,
+			Audience: cfg.Auth.JWT.Audience,
+			CacheTTL: cfg.Auth.JWT.CacheTTL,
+		})
+	}
+
+	if cfg.RateLimit.Enabled {
+		limiter, err := ratelimit.NewLimiter(logger, &cfg.RateLimit)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize rate limiter: %w", err)
+		}
+		s.limiter = limiter
 	}
 
 	// Load HTML templates
@@ -62,11 +134,45 @@ func NewServer(logger *zap.Logger, port int, store storage.Store, sessionStore s
 	// Serve static files
 	s.router.Static("/static", "assets/static")
 
+	if len(cfg.TrustedProxies) > 0 {
+		if err := s.router.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+			return nil, fmt.Errorf("invalid trusted_proxies: %w", err)
+		}
+	}
+
 	s.router.Use(s.loggerMiddleware())
 	s.router.Use(s.recoveryMiddleware())
+	s.router.Use(s.bodyLimitMiddleware())
+
+	if cfg.AccessLog.Enabled {
+		accessLog, err := newAccessLogger(&cfg.AccessLog)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize access log: %w", err)
+		}
+		s.accessLog = accessLog
+		s.router.Use(s.accessLogMiddleware())
+	}
+
+	if cfg.Audit.Enabled {
+		s.auditSink = newFileAuditSink(&cfg.Audit)
+	}
+
+	if cfg.Quota.Enabled {
+		counter, err := quota.NewCounter(logger, &cfg.Quota)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize quota counters: %w", err)
+		}
+		s.quota = counter
+	}
+
 	return s, nil
 }
 
+// currentState returns the active state snapshot
+func (s *Server) currentState() *state.State {
+	return s.state.Load()
+}
+
 // RegisterRoutes registers routes with the given router for MCP servers
 func (s *Server) RegisterRoutes(ctx context.Context) error {
 	s.router.GET("/health_check", func(c *gin.Context) {
@@ -108,7 +214,8 @@ func (s *Server) RegisterRoutes(ctx context.Context) error {
 	}
 
 	// Atomically replace the state
-	s.state = newState
+	s.state.Store(newState)
+	s.attachNotificationForwarders(newState)
 
 	// Register all routes under root path
 	s.logger.Debug("registering root handler")
@@ -117,20 +224,54 @@ func (s *Server) RegisterRoutes(ctx context.Context) error {
 	return nil
 }
 
+// routePrefixKey carries the prefix handleRoot resolved (by path or by
+// virtual host) to the endpoint handlers
+const routePrefixKey = "routePrefix"
+
+// routePrefix returns the prefix handleRoot resolved for this request,
+// falling back to trimming the endpoint suffix from the path
+func routePrefix(c *gin.Context, suffix string) string {
+	if value, ok := c.Get(routePrefixKey); ok {
+		return value.(string)
+	}
+	prefix := strings.TrimSuffix(c.Request.URL.Path, suffix)
+	if prefix == "" {
+		prefix = "/"
+	}
+	return prefix
+}
+
 // handleRoot handles all unmatched routes
 func (s *Server) handleRoot(c *gin.Context) {
 	// Get the path
 	path := c.Request.URL.Path
 	parts := strings.Split(strings.Trim(path, "/"), "/")
-	if len(parts) < 2 {
-		s.logger.Debug("invalid path format",
-			zap.String("path", path),
-			zap.String("remote_addr", c.Request.RemoteAddr))
-		s.sendProtocolError(c, nil, "Invalid path", http.StatusBadRequest, mcp.ErrorCodeInvalidRequest)
-		return
+
+	var endpoint, prefix string
+	host := c.Request.Host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
 	}
-	endpoint := parts[len(parts)-1]
-	prefix := "/" + strings.Join(parts[:len(parts)-1], "/")
+	if hostPrefix := s.currentState().GetPrefixByHost(host); hostPrefix != "" && len(parts) >= 1 {
+		// Virtual host routing: the prefix lives at the host root, so the
+		// endpoint is the full request path (e.g. /mcp, /sse)
+		endpoint = parts[len(parts)-1]
+		prefix = hostPrefix
+	} else {
+		if len(parts) < 2 {
+			s.logger.Debug("invalid path format",
+				zap.String("path", path),
+				zap.String("remote_addr", c.Request.RemoteAddr))
+			s.sendProtocolError(c, nil, "Invalid path", http.StatusBadRequest, mcp.ErrorCodeInvalidRequest)
+			return
+		}
+		endpoint = parts[len(parts)-1]
+		prefix = "/" + strings.Join(parts[:len(parts)-1], "/")
+	}
+
+	// Hand the resolved prefix to the endpoint handlers; with virtual
+	// host routing it cannot be re-derived from the path
+	c.Set(routePrefixKey, prefix)
 
 	s.logger.Debug("routing request",
 		zap.String("path", path),
@@ -138,8 +279,32 @@ func (s *Server) handleRoot(c *gin.Context) {
 		zap.String("endpoint", endpoint),
 		zap.String("remote_addr", c.Request.RemoteAddr))
 
-	// Check auth configuration
-	auth := s.state.GetAuth(prefix)
+	if !s.checkIPFilter(c, prefix) {
+		return
+	}
+
+	// Optionally enforce tenant isolation on incoming traffic
+	if s.cfg.TenantHeader != "" {
+		tenant := s.currentState().GetTenant(prefix)
+		if tenant != "" && c.GetHeader(s.cfg.TenantHeader) != tenant {
+			s.logger.Warn("tenant mismatch",
+				zap.String("prefix", prefix),
+				zap.String("expected_tenant", tenant),
+				zap.String("remote_addr", c.Request.RemoteAddr))
+			c.JSON(http.StatusForbidden, gin.H{
+				"error":             "tenant_mismatch",
+				"error_description": "Request tenant does not match the prefix owner",
+			})
+			return
+		}
+	}
+
+	// Check auth configuration; listeners running with disable_auth
+	// carry an exemption in the request context
+	auth := s.currentState().GetAuth(prefix)
+	if exempt, _ := c.Request.Context().Value(listenerAuthExemptKey{}).(bool); exempt {
+		auth = nil
+	}
 	if auth != nil && auth.Mode == cnst.AuthModeOAuth2 {
 		// Validate access token
 		if !s.isValidAccessToken(c.Request) {
@@ -151,9 +316,26 @@ func (s *Server) handleRoot(c *gin.Context) {
 			return
 		}
 	}
+	if auth != nil && auth.Mode == cnst.AuthModeOIDC {
+		if !s.validateOIDCToken(c) {
+			return
+		}
+	}
+	if auth != nil && auth.Mode == cnst.AuthModeAPIKey {
+		if !isValidAPIKey(auth, c.Request) {
+			s.logger.Warn("invalid API key",
+				zap.String("prefix", prefix),
+				zap.String("remote_addr", c.Request.RemoteAddr))
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error":             "invalid_api_key",
+				"error_description": "Missing or invalid API key",
+			})
+			return
+		}
+	}
 
 	// Dynamically set CORS
-	if cors := s.state.GetCORS(prefix); cors != nil {
+	if cors := s.currentState().GetCORS(prefix); cors != nil {
 		s.logger.Debug("applying CORS middleware",
 			zap.String("prefix", prefix))
 		s.corsMiddleware(cors)(c)
@@ -165,7 +347,7 @@ func (s *Server) handleRoot(c *gin.Context) {
 		}
 	}
 
-	protoType := s.state.GetProtoType(prefix)
+	protoType := s.currentState().GetProtoType(prefix)
 	if protoType == "" {
 		s.logger.Warn("invalid prefix",
 			zap.String("prefix", prefix),
@@ -198,20 +380,56 @@ func (s *Server) handleRoot(c *gin.Context) {
 }
 
 func (s *Server) Start() {
+	s.startAdminServer()
+	s.startHealthChecker(context.Background())
+	s.startExtraListeners()
 	go func() {
-		if err := s.router.Run(fmt.Sprintf(":%d", s.port)); err != nil {
+		if s.cfg.TLS != nil {
+			if err := s.serveTLS(); err != nil {
+				s.logger.Error("failed to start TLS server", zap.Error(err))
+			}
+			return
+		}
+		if err := s.router.Run(fmt.Sprintf(":%d", s.cfg.Port)); err != nil {
 			s.logger.Error("failed to start server", zap.Error(err))
 		}
 	}()
 }
 
-// Shutdown gracefully shuts down the server
-func (s *Server) Shutdown(_ context.Context) error {
-	s.logger.Info("shutting down server")
+// Shutdown gracefully shuts down the server: new sessions are rejected,
+// connected SSE clients get a shutdown notice, and in-flight tool calls are
+// drained up to the configured timeout before connections are closed
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.logger.Info("shutting down server, draining in-flight requests")
+	s.draining.Store(true)
+
+	// The caller's context is typically already canceled at this point, so
+	// detach it for the final notifications
+	s.broadcastShutdownNotice(context.WithoutCancel(ctx))
+
+	drainTimeout := s.cfg.DrainTimeout
+	if drainTimeout <= 0 {
+		drainTimeout = 30 * time.Second
+	}
+	done := make(chan struct{})
+	go func() {
+		s.inflight.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		s.logger.Info("all in-flight tool calls completed")
+	case <-time.After(drainTimeout):
+		s.logger.Warn("drain timeout reached, closing remaining connections",
+			zap.Duration("drain_timeout", drainTimeout))
+	}
+
 	close(s.shutdownCh)
 
+	s.removeUnixSockets()
+
 	var wg sync.WaitGroup
-	for prefix, transport := range s.state.GetTransports() {
+	for prefix, transport := range s.currentState().GetTransports() {
 		if transport.IsRunning() {
 			wg.Add(1)
 			go func(p string, t mcpproxy.Transport) {
@@ -232,6 +450,24 @@ func (s *Server) Shutdown(_ context.Context) error {
 	}
 	wg.Wait()
 
+	if s.limiter != nil {
+		if err := s.limiter.Close(); err != nil {
+			s.logger.Warn("failed to close rate limiter", zap.Error(err))
+		}
+	}
+
+	if s.auditSink != nil {
+		if err := s.auditSink.Close(); err != nil {
+			s.logger.Warn("failed to close audit sink", zap.Error(err))
+		}
+	}
+
+	if s.quota != nil {
+		if err := s.quota.Close(); err != nil {
+			s.logger.Warn("failed to close quota counters", zap.Error(err))
+		}
+	}
+
 	return nil
 }
 
@@ -246,12 +482,22 @@ func (s *Server) updateConfigs(ctx context.Context) (*state.State, error) {
 	if s.lastUpdateTime.IsZero() {
 		cfgs, err = s.store.List(ctx)
 		if err != nil {
-			s.logger.Error("Failed to load MCP configurations",
+			// Storage is down: fall back to the persisted last-good set so
+			// a restart doesn't depend on the config backend being up
+			cached, cacheErr := s.loadLastGoodConfigs()
+			if cacheErr != nil {
+				s.logger.Error("Failed to load MCP configurations",
+					zap.Error(err))
+				return nil, err
+			}
+			s.logger.Warn("storage unavailable, starting from last-good config cache",
+				zap.Int("count", len(cached)),
 				zap.Error(err))
-			return nil, err
+			cfgs = cached
+		} else {
+			s.logger.Info("loading all MCP configurations",
+				zap.Int("count", len(cfgs)))
 		}
-		s.logger.Info("loading all MCP configurations",
-			zap.Int("count", len(cfgs)))
 	} else {
 		updatedCfgs, err := s.store.ListUpdated(ctx, s.lastUpdateTime)
 		if err != nil {
@@ -261,16 +507,16 @@ func (s *Server) updateConfigs(ctx context.Context) (*state.State, error) {
 		}
 		if len(updatedCfgs) == 0 {
 			s.logger.Info("no updated MCP configurations found, skipping update")
-			return s.state, nil
+			return s.currentState(), nil
 		}
 		s.logger.Info("getting updated MCP configurations",
 			zap.Int("count", len(updatedCfgs)))
-		cfgs = s.state.GetRawConfigs()
+		cfgs = s.currentState().GetRawConfigs()
 		for _, cfg := range updatedCfgs {
 			cfgs = config.MergeConfigs(cfgs, cfg)
 		}
 		s.logger.Info("merging updated MCP configurations",
-			zap.Int("total_old", len(s.state.GetRawConfigs())),
+			zap.Int("total_old", len(s.currentState().GetRawConfigs())),
 			zap.Int("total_new", len(cfgs)))
 	}
 
@@ -288,8 +534,15 @@ func (s *Server) updateConfigs(ctx context.Context) (*state.State, error) {
 		return nil, err
 	}
 
+	// Compile all tool templates so a broken template rejects the whole
+	// reload instead of failing at call time
+	if err := template.ValidateToolTemplates(cfgs); err != nil {
+		s.logger.Error("template validation failed", zap.Error(err))
+		return nil, err
+	}
+
 	s.logger.Info("initializing server state")
-	newState, err := state.BuildStateFromConfig(ctx, cfgs, s.state, s.logger)
+	newState, err := state.BuildStateFromConfig(ctx, cfgs, s.currentState(), s.logger)
 	if err != nil {
 		s.logger.Error("failed to initialize server state",
 			zap.Error(err))
@@ -302,6 +555,7 @@ func (s *Server) updateConfigs(ctx context.Context) (*state.State, error) {
 		zap.Int("router_count", newState.GetRouterCount()))
 
 	s.lastUpdateTime = now
+	s.persistLastGoodConfigs(cfgs)
 	return newState, nil
 }
 
@@ -309,8 +563,12 @@ func (s *Server) ReloadConfigs(ctx context.Context) {
 	s.logger.Info("Reloading MCP configuration")
 
 	newState, err := s.updateConfigs(ctx)
+	s.lastReloadAt = time.Now()
+	s.lastReloadOK = err == nil
 	if err != nil {
-		s.logger.Error("failed to reload configuration",
+		// Keep serving the last good configuration; the failed update is
+		// retried on the next reload trigger
+		s.logger.Error("failed to reload configuration, keeping last good configuration",
 			zap.Error(err))
 		return
 	}
@@ -318,7 +576,18 @@ func (s *Server) ReloadConfigs(ctx context.Context) {
 		return
 	}
 	// Atomically replace the state
-	s.state = newState
+	oldState := s.currentState()
+	s.state.Store(newState)
+	s.attachNotificationForwarders(newState)
+
+	if diff := state.Diff(oldState, newState); !diff.Empty() {
+		s.logger.Info("configuration diff",
+			zap.Strings("added_tools", diff.AddedTools),
+			zap.Strings("removed_tools", diff.RemovedTools),
+			zap.Strings("changed_tools", diff.ChangedTools))
+	}
+
+	s.notifyToolListChanged(ctx, oldState, newState)
 
 	s.logger.Info("Configuration reloaded successfully")
 }
@@ -340,7 +609,7 @@ func (s *Server) UpdateConfig(ctx context.Context, cfg *config.MCPConfig) {
 	}
 
 	// Get current state
-	currentState := s.state
+	currentState := s.currentState()
 	if currentState == nil {
 		s.logger.Warn("current state is nil, triggering reload")
 		s.ReloadConfigs(ctx)
@@ -365,5 +634,8 @@ func (s *Server) UpdateConfig(ctx context.Context, cfg *config.MCPConfig) {
 		zap.Int("router_count", updatedState.GetRouterCount()))
 
 	// Atomically replace the state
-	s.state = updatedState
+	s.state.Store(updatedState)
+	s.attachNotificationForwarders(updatedState)
+
+	s.notifyToolListChanged(ctx, currentState, updatedState)
 }
@@ -0,0 +1,55 @@
+package core
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// sessionStateSweepInterval is how often per-session server state is
+// reconciled against the session store
+const sessionStateSweepInterval = 5 * time.Minute
+
+// sweepSessionState prunes per-session server state (stats, client roots,
+// log levels) for sessions that no longer exist. Admin terminate and
+// explicit DELETE clean up eagerly; this sweep covers sessions that end by
+// client disconnect or TTL expiry, which would otherwise leak entries in
+// a long-running gateway.
+func (s *Server) sweepSessionState() {
+	ticker := time.NewTicker(sessionStateSweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.shutdownCh:
+			return
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			conns, err := s.sessions.List(ctx)
+			cancel()
+			if err != nil {
+				continue
+			}
+
+			live := make(map[string]struct{}, len(conns))
+			for _, conn := range conns {
+				live[conn.Meta().ID] = struct{}{}
+			}
+
+			removed := s.stats.retain(live)
+			removed += s.clientRoots.retain(live)
+			s.sessionLogLevels.Range(func(key, _ any) bool {
+				if _, ok := live[key.(string)]; !ok {
+					s.sessionLogLevels.Delete(key)
+					removed++
+				}
+				return true
+			})
+			if removed > 0 {
+				s.logger.Debug("pruned state of ended sessions",
+					zap.Int("entries", removed))
+			}
+		}
+	}
+}
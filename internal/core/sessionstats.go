@@ -0,0 +1,79 @@
+package core
+
+import (
+	"sync"
+	"time"
+)
+
+// sessionStats tracks per-session activity counters for the admin API
+type sessionStats struct {
+	mu    sync.RWMutex
+	stats map[string]*sessionStat
+}
+
+type sessionStat struct {
+	ToolCalls    int64     `json:"tool_calls"`
+	LastActivity time.Time `json:"last_activity"`
+}
+
+// newSessionStats creates the stats tracker
+func newSessionStats() *sessionStats {
+	return &sessionStats{stats: make(map[string]*sessionStat)}
+}
+
+// recordToolCall bumps a session's tool-call counter
+func (s *sessionStats) recordToolCall(sessionID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stat, ok := s.stats[sessionID]
+	if !ok {
+		stat = &sessionStat{}
+		s.stats[sessionID] = stat
+	}
+	stat.ToolCalls++
+	stat.LastActivity = time.Now()
+}
+
+// touch refreshes a session's last-activity timestamp
+func (s *sessionStats) touch(sessionID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stat, ok := s.stats[sessionID]
+	if !ok {
+		stat = &sessionStat{}
+		s.stats[sessionID] = stat
+	}
+	stat.LastActivity = time.Now()
+}
+
+// get returns a copy of a session's counters
+func (s *sessionStats) get(sessionID string) sessionStat {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if stat, ok := s.stats[sessionID]; ok {
+		return *stat
+	}
+	return sessionStat{}
+}
+
+// delete drops a terminated session's counters
+func (s *sessionStats) delete(sessionID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.stats, sessionID)
+}
+
+// retain drops counters for sessions not in the live set, reporting
+// how many entries were removed
+func (s *sessionStats) retain(live map[string]struct{}) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	removed := 0
+	for id := range s.stats {
+		if _, ok := live[id]; !ok {
+			delete(s.stats, id)
+			removed++
+		}
+	}
+	return removed
+}
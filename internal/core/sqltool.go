@@ -0,0 +1,152 @@
+package core
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/amoylab/unla/internal/common/config"
+	"github.com/amoylab/unla/pkg/mcp"
+
+	_ "github.com/glebarez/go-sqlite"
+	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+// defaultSQLMaxRows caps SQL tool result sets unless configured otherwise
+const defaultSQLMaxRows = 100
+
+// sqlPoolCache reuses database handles per driver+DSN
+var sqlPoolCache sync.Map
+
+// sqlDriverName maps the config driver to the registered database/sql driver
+func sqlDriverName(driver string) (string, error) {
+	switch driver {
+	case "postgres":
+		return "pgx", nil
+	case "mysql":
+		return "mysql", nil
+	case "sqlite":
+		return "sqlite", nil
+	default:
+		return "", fmt.Errorf("unsupported database driver: %s", driver)
+	}
+}
+
+// sqlPool returns a cached connection pool for the tool's DSN
+func sqlPool(cfg *config.DatabaseToolConfig) (*sql.DB, error) {
+	cacheKey := cfg.Driver + "|" + cfg.DSN
+	if cached, ok := sqlPoolCache.Load(cacheKey); ok {
+		return cached.(*sql.DB), nil
+	}
+
+	driverName, err := sqlDriverName(cfg.Driver)
+	if err != nil {
+		return nil, err
+	}
+	db, err := sql.Open(driverName, cfg.DSN)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	db.SetMaxOpenConns(5)
+
+	if cached, loaded := sqlPoolCache.LoadOrStore(cacheKey, db); loaded {
+		_ = db.Close()
+		return cached.(*sql.DB), nil
+	}
+	return db, nil
+}
+
+// invokeSQLTool executes the configured parameterized query and returns the
+// rows as JSON text
+func invokeSQLTool(ctx context.Context, tool *config.ToolConfig, args map[string]any) (*mcp.CallToolResult, error) {
+	cfg := tool.Database
+
+	if !cfg.AllowWrite {
+		stmt := strings.ToLower(strings.TrimSpace(cfg.Query))
+		if !strings.HasPrefix(stmt, "select") && !strings.HasPrefix(stmt, "with") {
+			return nil, fmt.Errorf("tool %s is read-only but its query is not a SELECT", tool.Name)
+		}
+	}
+
+	db, err := sqlPool(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// Bind arguments as placeholders in the configured order
+	params := make([]any, len(cfg.Params))
+	for i, name := range cfg.Params {
+		params[i] = args[name]
+	}
+
+	// Run read-only queries in a read-only transaction so drivers that
+	// support it enforce the restriction server-side (a WITH ... DELETE
+	// CTE would otherwise slip past the prefix check)
+	var rows *sql.Rows
+	if !cfg.AllowWrite {
+		tx, err := db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+		if err != nil {
+			return nil, fmt.Errorf("failed to begin read-only transaction: %w", err)
+		}
+		defer tx.Rollback()
+		rows, err = tx.QueryContext(ctx, cfg.Query, params...)
+		if err != nil {
+			return nil, fmt.Errorf("query failed: %w", err)
+		}
+	} else {
+		var err error
+		rows, err = db.QueryContext(ctx, cfg.Query, params...)
+		if err != nil {
+			return nil, fmt.Errorf("query failed: %w", err)
+		}
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	maxRows := cfg.MaxRows
+	if maxRows <= 0 {
+		maxRows = defaultSQLMaxRows
+	}
+
+	var results []map[string]any
+	for rows.Next() {
+		if len(results) >= maxRows {
+			break
+		}
+		values := make([]any, len(columns))
+		scanTargets := make([]any, len(columns))
+		for i := range values {
+			scanTargets[i] = &values[i]
+		}
+		if err := rows.Scan(scanTargets...); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+
+		row := make(map[string]any, len(columns))
+		for i, column := range columns {
+			value := values[i]
+			if raw, ok := value.([]byte); ok {
+				value = string(raw)
+			}
+			row[column] = value
+		}
+		results = append(results, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("query iteration failed: %w", err)
+	}
+
+	data, err := json.Marshal(results)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode result rows: %w", err)
+	}
+	return mcp.NewCallToolResultText(string(data)), nil
+}
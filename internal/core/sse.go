@@ -1,8 +1,11 @@
 package core
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"strings"
 	"time"
@@ -25,11 +28,7 @@ func (s *Server) handleSSE(c *gin.Context) {
 	c.Writer.Header().Set("Cache-Control", "no-cache, no-transform")
 	c.Writer.Header().Set("Connection", "keep-alive")
 
-	// Get the prefix from the request path
-	prefix := strings.TrimSuffix(c.Request.URL.Path, "/sse")
-	if prefix == "" {
-		prefix = "/"
-	}
+	prefix := routePrefix(c, "/sse")
 
 	requestInfo := &session.RequestInfo{
 		Headers: make(map[string]string),
@@ -55,14 +54,20 @@ func (s *Server) handleSSE(c *gin.Context) {
 		}
 	}
 
+	if s.draining.Load() {
+		s.sendProtocolError(c, nil, "Server is shutting down", http.StatusServiceUnavailable, mcp.ErrorCodeConnectionClosed)
+		return
+	}
+
 	sessionID := uuid.New().String()
 	meta := &session.Meta{
-		ID:        sessionID,
-		CreatedAt: time.Now(),
-		Prefix:    prefix,
-		Type:      "sse",
-		Request:   requestInfo,
-		Extra:     nil,
+		ID:         sessionID,
+		CreatedAt:  time.Now(),
+		Prefix:     prefix,
+		Type:       "sse",
+		Request:    requestInfo,
+		Extra:      nil,
+		RemoteAddr: c.ClientIP(),
 	}
 
 	s.logger.Info("establishing SSE connection",
@@ -91,7 +96,7 @@ func (s *Server) handleSSE(c *gin.Context) {
 
 	// Send the initial endpoint event
 	endpointURL := fmt.Sprintf("%s/message?sessionId=%s", strings.TrimSuffix(c.Request.URL.Path, "/sse"), meta.ID)
-	ssePrefix := s.state.GetSSEPrefix(prefix)
+	ssePrefix := s.currentState().GetSSEPrefix(prefix)
 	if ssePrefix != "" {
 		endpointURL = fmt.Sprintf("%s/%s", ssePrefix, endpointURL)
 	}
@@ -119,20 +124,31 @@ func (s *Server) handleSSE(c *gin.Context) {
 	)
 
 	// Main event loop
+	keepalive := time.NewTicker(s.keepaliveInterval())
+	defer keepalive.Stop()
+
 	for {
 		select {
+		case <-keepalive.C:
+			// Comment line keeps idle streams alive through proxies
+			_, _ = fmt.Fprint(c.Writer, ": ping\n\n")
+			c.Writer.Flush()
+		case <-conn.Done():
+			s.logger.Info("session terminated, closing SSE stream",
+				zap.String("session_id", sessionID))
+			return
 		case event := <-conn.EventQueue():
 			if event == nil {
 				s.logger.Warn("received nil event for session",
 					zap.String("session_id", sessionID),
 				)
-			} else {
-				s.logger.Debug("sending event to SSE client",
-					zap.String("session_id", sessionID),
-					zap.String("event_type", event.Event),
-					zap.Int("data_size", len(event.Data)),
-				)
+				continue
 			}
+			s.logger.Debug("sending event to SSE client",
+				zap.String("session_id", sessionID),
+				zap.String("event_type", event.Event),
+				zap.Int("data_size", len(event.Data)),
+			)
 
 			switch event.Event {
 			case "message":
@@ -284,9 +300,18 @@ func (s *Server) handlePostMessage(c *gin.Context, conn session.Connection) {
 
 	// TODO: support auth
 
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.String(http.StatusBadRequest, "Failed to read request body")
+		return
+	}
+	if !s.checkJSONDepth(c, body) {
+		return
+	}
+
 	// Parse the JSON-RPC message
 	var req mcp.JSONRPCRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
+	if err := json.Unmarshal(body, &req); err != nil {
 		s.logger.Error("failed to parse JSON-RPC request",
 			zap.Error(err),
 			zap.String("session_id", conn.Meta().ID),
@@ -305,6 +330,28 @@ func (s *Server) handlePostMessage(c *gin.Context, conn session.Connection) {
 	switch req.Method {
 	case mcp.NotificationInitialized:
 		s.sendAcceptedResponse(c)
+	case mcp.NotificationRootsListChanged:
+		// Refresh the cached roots for this session
+		s.requestClientRoots(c.Request.Context(), conn)
+		s.sendAcceptedResponse(c)
+	case mcp.LoggingSetLevel:
+		var params struct {
+			Level string `json:"level"`
+		}
+		if err := json.Unmarshal(req.Params, &params); err != nil || !s.setSessionLogLevel(conn.Meta().ID, params.Level) {
+			s.sendProtocolError(c, req.Id, "Invalid logging level", http.StatusBadRequest, mcp.ErrorCodeInvalidParams)
+			return
+		}
+		s.sendSuccessResponse(c, conn, req, struct{}{}, true)
+		return
+
+	case mcp.NotificationCancelled:
+		var params struct {
+			RequestID any `json:"requestId"`
+		}
+		_ = json.Unmarshal(req.Params, &params)
+		s.cancelToolCall(conn.Meta().ID, params.RequestID)
+		s.sendAcceptedResponse(c)
 	case mcp.Initialize:
 		var params mcp.InitializeRequestParams
 		if err := json.Unmarshal(req.Params, &params); err != nil {
@@ -312,8 +359,11 @@ func (s *Server) handlePostMessage(c *gin.Context, conn session.Connection) {
 			return
 		}
 
+		conn.Meta().ClientName = params.ClientInfo.Name
+		conn.Meta().ClientVersion = params.ClientInfo.Version
+
 		result := mcp.InitializedResult{
-			ProtocolVersion: mcp.LatestProtocolVersion,
+			ProtocolVersion: mcp.NegotiateProtocolVersion(params.ProtocolVersion),
 			ServerInfo: mcp.ImplementationSchema{
 				Name:    cnst.AppName,
 				Version: version.Get(),
@@ -322,6 +372,8 @@ func (s *Server) handlePostMessage(c *gin.Context, conn session.Connection) {
 				Tools: mcp.ToolsCapabilitySchema{
 					ListChanged: true,
 				},
+				Prompts:   mcp.PromptsCapabilitySchema{},
+				Resources: mcp.ResourcesCapabilitySchema{},
 			},
 		}
 		s.sendSuccessResponse(c, conn, req, result, true)
@@ -329,12 +381,17 @@ func (s *Server) handlePostMessage(c *gin.Context, conn session.Connection) {
 		// Handle ping request with an empty response
 		s.sendSuccessResponse(c, conn, req, struct{}{}, true)
 	case mcp.ToolsList:
-		protoType := s.state.GetProtoType(conn.Meta().Prefix)
+		protoType := s.currentState().GetProtoType(conn.Meta().Prefix)
 		if protoType == "" {
 			s.sendProtocolError(c, req.Id, "Server configuration not found", http.StatusInternalServerError, mcp.ErrorCodeInternalError)
 			return
 		}
 
+		var pageParams mcp.PaginatedRequestParams
+		if len(req.Params) > 0 {
+			_ = json.Unmarshal(req.Params, &pageParams)
+		}
+
 		var tools []mcp.ToolSchema
 		var err error
 		switch protoType {
@@ -345,13 +402,13 @@ func (s *Server) handlePostMessage(c *gin.Context, conn session.Connection) {
 				return
 			}
 		case cnst.BackendProtoStdio, cnst.BackendProtoSSE, cnst.BackendProtoStreamable:
-			transport := s.state.GetTransport(conn.Meta().Prefix)
+			transport := s.currentState().GetTransport(conn.Meta().Prefix)
 			if transport == nil {
 				s.sendProtocolError(c, req.Id, "Failed to fetch tools", http.StatusInternalServerError, mcp.ErrorCodeInternalError)
 				return
 			}
 
-			tools, err = transport.FetchTools(c.Request.Context())
+			tools, err = s.fetchProxiedTools(c.Request.Context(), conn.Meta().Prefix, transport)
 			if err != nil {
 				s.sendProtocolError(c, req.Id, "Failed to fetch tools", http.StatusInternalServerError, mcp.ErrorCodeInternalError)
 				return
@@ -361,21 +418,21 @@ func (s *Server) handlePostMessage(c *gin.Context, conn session.Connection) {
 			return
 		}
 
-		toolSchemas := make([]mcp.ToolSchema, len(tools))
-		for i, tool := range tools {
-			toolSchemas[i] = mcp.ToolSchema{
-				Name:        tool.Name,
-				Description: tool.Description,
-				InputSchema: tool.InputSchema,
-			}
+		tools = s.filterTools(c, conn.Meta().Prefix, tools)
+
+		page, nextCursor, err := s.paginateTools(tools, pageParams.Cursor)
+		if err != nil {
+			s.sendProtocolError(c, req.Id, "Invalid cursor", http.StatusBadRequest, mcp.ErrorCodeInvalidParams)
+			return
 		}
 
 		result := mcp.ListToolsResult{
-			Tools: toolSchemas,
+			Tools:      page,
+			NextCursor: nextCursor,
 		}
 		s.sendSuccessResponse(c, conn, req, result, true)
 	case mcp.ToolsCall:
-		protoType := s.state.GetProtoType(conn.Meta().Prefix)
+		protoType := s.currentState().GetProtoType(conn.Meta().Prefix)
 		if protoType == "" {
 			s.sendProtocolError(c, req.Id, "Server configuration not found", http.StatusInternalServerError, mcp.ErrorCodeInternalError)
 			return
@@ -388,23 +445,69 @@ func (s *Server) handlePostMessage(c *gin.Context, conn session.Connection) {
 			return
 		}
 
+		if !s.checkToolAccess(c, req.Id, conn.Meta().Prefix, params.Name) {
+			return
+		}
+		if !s.checkPolicy(c, req.Id, conn, params.Name, params.Arguments) {
+			return
+		}
+		if !s.checkToolRateLimit(c, req.Id, conn.Meta().Prefix, params.Name, conn.Meta().ID) {
+			return
+		}
+		if !s.checkQuota(c, req.Id, conn.Meta().Prefix, params.Name) {
+			return
+		}
+
+		s.inflight.Add(1)
+		defer s.inflight.Done()
+
+		// Allow notifications/cancelled to abort this call
+		cancelCtx, cancelCall := context.WithCancel(c.Request.Context())
+		defer cancelCall()
+		unregisterCancel := s.registerCancel(conn.Meta().ID, req.Id, cancelCall)
+		defer unregisterCancel()
+		c.Request = c.Request.WithContext(cancelCtx)
+
+		callStart := time.Now()
+		caller := rateLimitClientKey(c, conn.Meta().ID)
+		s.stats.recordToolCall(conn.Meta().ID)
+		s.sessionLog(c.Request.Context(), conn, "info", "tool call started: "+params.Name)
+
 		var (
 			result *mcp.CallToolResult
 			err    error
 		)
 		switch protoType {
 		case cnst.BackendProtoHttp:
+			// callHTTPTool has already responded when it returns nil
 			result = s.callHTTPTool(c, req, conn, params)
+			if result == nil {
+				s.auditToolCall(c.Request.Context(), conn.Meta().ID, conn.Meta().Prefix, params.Name, caller,
+					params.Arguments, fmt.Errorf("tool execution failed"), time.Since(callStart))
+				return
+			}
 		case cnst.BackendProtoStdio, cnst.BackendProtoSSE, cnst.BackendProtoStreamable:
-			transport := s.state.GetTransport(conn.Meta().Prefix)
+			transport := s.transportForSession(conn.Meta().Prefix, conn.Meta().ID)
 			if transport == nil {
 				errMsg := "Server configuration not found"
 				s.sendProtocolError(c, req.Id, errMsg, http.StatusNotFound, mcp.ErrorCodeMethodNotFound)
 				return
 			}
 
-			result, err = transport.CallTool(c.Request.Context(), params, mergeRequestInfo(conn.Meta().Request, c.Request))
+			callCtx, cancel := s.toolCallContext(c, conn.Meta().Prefix)
+			upstreamToken, unroute := s.routeProgressToken(params.Meta.ProgressToken, conn)
+			callParams := params
+			callParams.Meta.ProgressToken = upstreamToken
+			result, err = transport.CallTool(callCtx, callParams, mergeRequestInfo(conn.Meta().Request, c.Request))
+			unroute()
+			cancel()
 			if err != nil {
+				if errors.Is(callCtx.Err(), context.DeadlineExceeded) {
+					err = fmt.Errorf("tool call timed out")
+				}
+				s.auditToolCall(c.Request.Context(), conn.Meta().ID, conn.Meta().Prefix, params.Name, caller,
+					params.Arguments, err, time.Since(callStart))
+				s.sessionLog(c.Request.Context(), conn, "error", "tool call failed: "+params.Name+": "+err.Error())
 				s.sendToolExecutionError(c, conn, req, err, true)
 				return
 			}
@@ -413,10 +516,66 @@ func (s *Server) handlePostMessage(c *gin.Context, conn session.Connection) {
 			return
 		}
 
+		s.auditToolCall(c.Request.Context(), conn.Meta().ID, conn.Meta().Prefix, params.Name, caller,
+			params.Arguments, nil, time.Since(callStart))
+		s.sessionLog(c.Request.Context(), conn, "info", "tool call completed: "+params.Name)
 		s.sendSuccessResponse(c, conn, req, result, true)
 
+	case mcp.ResourcesList:
+		resources := s.currentState().GetResourceSchemas(conn.Meta().Prefix)
+		if len(resources) == 0 {
+			resources = []mcp.ResourceSchema{}
+		}
+		s.sendSuccessResponse(c, conn, req, mcp.ListResourcesResult{
+			Resources: resources,
+		}, true)
+		return
+
+	case mcp.ResourcesTemplatesList:
+		templates := s.currentState().GetResourceTemplateSchemas(conn.Meta().Prefix)
+		if len(templates) == 0 {
+			templates = []mcp.ResourceTemplateSchema{}
+		}
+		s.sendSuccessResponse(c, conn, req, mcp.ListResourceTemplatesResult{
+			ResourceTemplates: templates,
+		}, true)
+		return
+
+	case mcp.ResourcesRead:
+		var params mcp.ReadResourceRequestParams
+		if err := json.Unmarshal(req.Params, &params); err != nil || params.URI == "" {
+			s.sendProtocolError(c, req.Id, "Invalid resource read parameters", http.StatusBadRequest, mcp.ErrorCodeInvalidParams)
+			return
+		}
+
+		resource, args, err := s.readResource(conn.Meta().Prefix, params.URI)
+		if err != nil {
+			s.sendProtocolError(c, req.Id, "Resource not found", http.StatusNotFound, mcp.ErrorCodeInvalidParams)
+			return
+		}
+
+		text, err := renderResourceContents(resource, args, s.isUntrustedTenant(conn.Meta().Prefix))
+		if err != nil {
+			s.logger.Error("failed to render resource contents",
+				zap.String("uri", params.URI),
+				zap.Error(err))
+			s.sendProtocolError(c, req.Id, "Failed to read resource", http.StatusInternalServerError, mcp.ErrorCodeInternalError)
+			return
+		}
+
+		s.sendSuccessResponse(c, conn, req, mcp.ReadResourceResult{
+			Contents: []mcp.ResourceContents{
+				{
+					URI:      params.URI,
+					MimeType: resource.MimeType,
+					Text:     text,
+				},
+			},
+		}, true)
+		return
+
 	case mcp.PromptsList:
-		protoType := s.state.GetProtoType(conn.Meta().Prefix)
+		protoType := s.currentState().GetProtoType(conn.Meta().Prefix)
 		if protoType == "" {
 			s.sendProtocolError(c, req.Id, "Server configuration not found", http.StatusInternalServerError, mcp.ErrorCodeInternalError)
 			return
@@ -426,12 +585,12 @@ func (s *Server) handlePostMessage(c *gin.Context, conn session.Connection) {
 		var err error
 		switch protoType {
 		case cnst.BackendProtoHttp:
-			prompts = s.state.GetPromptSchemas(conn.Meta().Prefix)
+			prompts = s.currentState().GetPromptSchemas(conn.Meta().Prefix)
 			if len(prompts) == 0 {
 				prompts = []mcp.PromptSchema{}
 			}
 		case cnst.BackendProtoStdio, cnst.BackendProtoSSE, cnst.BackendProtoStreamable:
-			transport := s.state.GetTransport(conn.Meta().Prefix)
+			transport := s.currentState().GetTransport(conn.Meta().Prefix)
 			if transport == nil {
 				s.sendProtocolError(c, req.Id, "Failed to fetch prompts", http.StatusInternalServerError, mcp.ErrorCodeInternalError)
 				return
@@ -447,24 +606,18 @@ func (s *Server) handlePostMessage(c *gin.Context, conn session.Connection) {
 			return
 		}
 
-		result := struct {
-			Prompts []mcp.PromptSchema `json:"prompts"`
-		}{
+		result := mcp.ListPromptsResult{
 			Prompts: prompts,
 		}
 		s.sendSuccessResponse(c, conn, req, result, true)
 	case mcp.PromptsGet:
-		protoType := s.state.GetProtoType(conn.Meta().Prefix)
+		protoType := s.currentState().GetProtoType(conn.Meta().Prefix)
 		if protoType == "" {
 			s.sendProtocolError(c, req.Id, "Server configuration not found", http.StatusInternalServerError, mcp.ErrorCodeInternalError)
 			return
 		}
 
-		var params struct {
-			Name string `json:"name"`
-			Arguments map[string]string `json:"arguments"`
-		}
-
+		var params mcp.GetPromptRequestParams
 		if err := json.Unmarshal(req.Params, &params); err != nil || params.Name == "" {
 			s.sendProtocolError(c, req.Id, "Invalid prompt get parameters", http.StatusBadRequest, mcp.ErrorCodeInvalidParams)
 			return
@@ -474,7 +627,7 @@ func (s *Server) handlePostMessage(c *gin.Context, conn session.Connection) {
 		var err error
 		switch protoType {
 		case cnst.BackendProtoHttp:
-			prompts := s.state.GetPromptSchemas(conn.Meta().Prefix)
+			prompts := s.currentState().GetPromptSchemas(conn.Meta().Prefix)
 			for i := range prompts {
 				if prompts[i].Name == params.Name {
 					prompt = &prompts[i]
@@ -482,7 +635,7 @@ func (s *Server) handlePostMessage(c *gin.Context, conn session.Connection) {
 				}
 			}
 		case cnst.BackendProtoStdio, cnst.BackendProtoSSE, cnst.BackendProtoStreamable:
-			transport := s.state.GetTransport(conn.Meta().Prefix)
+			transport := s.currentState().GetTransport(conn.Meta().Prefix)
 			if transport == nil {
 				s.sendProtocolError(c, req.Id, "Failed to fetch prompt", http.StatusInternalServerError, mcp.ErrorCodeInternalError)
 				return
@@ -503,55 +656,17 @@ func (s *Server) handlePostMessage(c *gin.Context, conn session.Connection) {
 		}
 
 		// Build the response with argument substitution
-		var argsMap map[string]string
-		if req.Params != nil {
-			_ = json.Unmarshal(req.Params, &argsMap)
-		}
-		argsMap = params.Arguments
-
-		resp := struct {
-			Description string `json:"description"`
-			Messages    []struct {
-				Role    string `json:"role"`
-				Content struct {
-					Type string `json:"type"`
-					Text string `json:"text"`
-				} `json:"content"`
-			} `json:"messages"`
-		}{
-			Description: prompt.Description,
-		}
-
-		for _, msg := range prompt.PromptResponse {
-			text := msg.Content.Text
-			// Replace {argument} placeholders with values from argsMap
-			for _, arg := range prompt.Arguments {
-				if val, ok := argsMap[arg.Name]; ok {
-					text = strings.ReplaceAll(text, "{"+arg.Name+"}", val)
-				}
-			}
-			resp.Messages = append(resp.Messages, struct {
-				Role    string `json:"role"`
-				Content struct {
-					Type string `json:"type"`
-					Text string `json:"text"`
-				} `json:"content"`
-			}{
-				Role: msg.Role,
-				Content: struct {
-					Type string `json:"type"`
-					Text string `json:"text"`
-				}{
-					Type: msg.Content.Type,
-					Text: text,
-				},
-			})
+		result, err := renderPrompt(prompt, params.Arguments, s.isUntrustedTenant(conn.Meta().Prefix))
+		if err != nil {
+			s.logger.Error("failed to render prompt",
+				zap.String("prompt", params.Name),
+				zap.Error(err))
+			s.sendProtocolError(c, req.Id, "Failed to render prompt", http.StatusInternalServerError, mcp.ErrorCodeInternalError)
+			return
 		}
-		
-		s.sendSuccessResponse(c, conn, req, resp, true)
+		s.sendSuccessResponse(c, conn, req, result, true)
 		return
 
-
 	default:
 		s.sendProtocolError(c, req.Id, "Unknown method", http.StatusNotFound, mcp.ErrorCodeMethodNotFound)
 	}
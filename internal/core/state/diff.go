@@ -0,0 +1,60 @@
+package state
+
+import (
+	"reflect"
+
+	"github.com/amoylab/unla/pkg/mcp"
+)
+
+// DiffSummary describes the tool-level changes between two states
+type DiffSummary struct {
+	AddedTools   []string
+	RemovedTools []string
+	ChangedTools []string
+}
+
+// Empty reports whether the diff contains no changes
+func (d *DiffSummary) Empty() bool {
+	return len(d.AddedTools) == 0 && len(d.RemovedTools) == 0 && len(d.ChangedTools) == 0
+}
+
+// Diff compares the HTTP tool sets of two states per prefix. Tool names are
+// reported as "<prefix>:<tool>".
+func Diff(oldState, newState *State) *DiffSummary {
+	summary := &DiffSummary{}
+
+	oldTools := collectTools(oldState)
+	newTools := collectTools(newState)
+
+	for key, schema := range newTools {
+		oldSchema, existed := oldTools[key]
+		if !existed {
+			summary.AddedTools = append(summary.AddedTools, key)
+			continue
+		}
+		if !reflect.DeepEqual(oldSchema, schema) {
+			summary.ChangedTools = append(summary.ChangedTools, key)
+		}
+	}
+	for key := range oldTools {
+		if _, exists := newTools[key]; !exists {
+			summary.RemovedTools = append(summary.RemovedTools, key)
+		}
+	}
+
+	return summary
+}
+
+// collectTools maps "<prefix>:<tool>" to its schema
+func collectTools(s *State) map[string]mcp.ToolSchema {
+	tools := make(map[string]mcp.ToolSchema)
+	if s == nil {
+		return tools
+	}
+	for prefix, runtime := range s.runtime {
+		for _, schema := range runtime.toolSchemas {
+			tools[string(prefix)+":"+schema.Name] = schema
+		}
+	}
+	return tools
+}
@@ -11,10 +11,14 @@ func (s *State) getRuntime(prefix string) runtimeUnit {
 	runtime, ok := s.runtime[uriPrefix(prefix)]
 	if !ok {
 		return runtimeUnit{
-			tools:       make(map[toolName]*config.ToolConfig),
-			toolSchemas: make([]mcp.ToolSchema, 0),
-			prompts:       make(map[promptName]*config.PromptConfig),
-			promptSchemas: make([]mcp.PromptSchema, 0),
+			tools:                   make(map[toolName]*config.ToolConfig),
+			toolSchemas:             make([]mcp.ToolSchema, 0),
+			prompts:                 make(map[promptName]*config.PromptConfig),
+			promptSchemas:           make([]mcp.PromptSchema, 0),
+			resources:               make(map[resourceURI]*config.ResourceConfig),
+			resourceSchemas:         make([]mcp.ResourceSchema, 0),
+			resourceTemplates:       make([]*config.ResourceTemplateConfig, 0),
+			resourceTemplateSchemas: make([]mcp.ResourceTemplateSchema, 0),
 		}
 	}
 	return runtime
@@ -26,6 +30,35 @@ func (s *State) setRouter(prefix string, router *config.RouterConfig) {
 	s.runtime[uriPrefix(prefix)] = runtime
 }
 
+func (s *State) setTenant(prefix, tenant string) {
+	runtime := s.getRuntime(prefix)
+	runtime.tenant = tenant
+	s.runtime[uriPrefix(prefix)] = runtime
+}
+
+// GetTenant returns the tenant owning the config behind a prefix
+func (s *State) GetTenant(prefix string) string {
+	runtime, ok := s.runtime[uriPrefix(prefix)]
+	if !ok {
+		return ""
+	}
+	return runtime.tenant
+}
+
+// GetPrefixByHost returns the prefix served at the root of a virtual host
+func (s *State) GetPrefixByHost(host string) string {
+	return s.hostPrefixes[host]
+}
+
+// GetRouter returns the router config behind a prefix
+func (s *State) GetRouter(prefix string) *config.RouterConfig {
+	runtime, ok := s.runtime[uriPrefix(prefix)]
+	if !ok {
+		return nil
+	}
+	return runtime.router
+}
+
 func (s *State) GetCORS(prefix string) *config.CORSConfig {
 	runtime, ok := s.runtime[uriPrefix(prefix)]
 	if ok && runtime.router != nil {
@@ -94,6 +127,14 @@ func (s *State) GetProtoType(prefix string) cnst.ProtoType {
 	return runtime.protoType
 }
 
+func (s *State) GetMCPServerConfig(prefix string) *config.MCPServerConfig {
+	runtime, ok := s.runtime[uriPrefix(prefix)]
+	if !ok {
+		return nil
+	}
+	return runtime.mcpServer
+}
+
 func (s *State) GetTransport(prefix string) mcpproxy.Transport {
 	runtime, ok := s.runtime[uriPrefix(prefix)]
 	if !ok {
@@ -102,6 +143,15 @@ func (s *State) GetTransport(prefix string) mcpproxy.Transport {
 	return runtime.transport
 }
 
+// GetCanary returns the canary transport and traffic share for a prefix
+func (s *State) GetCanary(prefix string) (mcpproxy.Transport, int) {
+	runtime, ok := s.runtime[uriPrefix(prefix)]
+	if !ok || runtime.canaryTransport == nil || runtime.mcpServer == nil || runtime.mcpServer.Canary == nil {
+		return nil, 0
+	}
+	return runtime.canaryTransport, runtime.mcpServer.Canary.Percent
+}
+
 func (s *State) GetTransports() map[string]mcpproxy.Transport {
 	transports := make(map[string]mcpproxy.Transport)
 	for prefix, runtime := range s.runtime {
@@ -116,6 +166,15 @@ func (s *State) GetRawConfigs() []*config.MCPConfig {
 	return s.rawConfigs
 }
 
+// GetPrefixes returns all prefixes with registered runtime state
+func (s *State) GetPrefixes() []string {
+	prefixes := make([]string, 0, len(s.runtime))
+	for prefix := range s.runtime {
+		prefixes = append(prefixes, string(prefix))
+	}
+	return prefixes
+}
+
 func (s *State) GetAuth(prefix string) *config.Auth {
 	runtime, ok := s.runtime[uriPrefix(prefix)]
 	if !ok || runtime.router == nil {
@@ -146,4 +205,36 @@ func (s *State) GetPromptSchemas(prefix string) []mcp.PromptSchema {
 		return nil
 	}
 	return runtime.promptSchemas
-}
\ No newline at end of file
+}
+
+func (s *State) GetResource(prefix, uri string) *config.ResourceConfig {
+	runtime, ok := s.runtime[uriPrefix(prefix)]
+	if !ok {
+		return nil
+	}
+	return runtime.resources[resourceURI(uri)]
+}
+
+func (s *State) GetResourceSchemas(prefix string) []mcp.ResourceSchema {
+	runtime, ok := s.runtime[uriPrefix(prefix)]
+	if !ok {
+		return nil
+	}
+	return runtime.resourceSchemas
+}
+
+func (s *State) GetResourceTemplates(prefix string) []*config.ResourceTemplateConfig {
+	runtime, ok := s.runtime[uriPrefix(prefix)]
+	if !ok {
+		return nil
+	}
+	return runtime.resourceTemplates
+}
+
+func (s *State) GetResourceTemplateSchemas(prefix string) []mcp.ResourceTemplateSchema {
+	runtime, ok := s.runtime[uriPrefix(prefix)]
+	if !ok {
+		return nil
+	}
+	return runtime.resourceTemplateSchemas
+}
@@ -3,40 +3,53 @@ package state
 import (
 	"context"
 	"fmt"
+	"reflect"
 
-	"github.com/ifuryst/lol"
 	"github.com/amoylab/unla/internal/common/cnst"
 	"github.com/amoylab/unla/internal/common/config"
 	"github.com/amoylab/unla/internal/core/mcpproxy"
 	"github.com/amoylab/unla/internal/template"
 	"github.com/amoylab/unla/pkg/mcp"
+	"github.com/ifuryst/lol"
 	"go.uber.org/zap"
 )
 
 type (
-	uriPrefix string
-	toolName  string
+	uriPrefix   string
+	toolName    string
 	promptName  string
+	resourceURI string
 
 	// State contains all the read-only shared state
 	State struct {
 		rawConfigs []*config.MCPConfig
 		runtime    map[uriPrefix]runtimeUnit
-		metrics    metrics
+		// hostPrefixes maps virtual hosts to the prefix they serve
+		hostPrefixes map[string]string
+		metrics      metrics
 	}
 
 	runtimeUnit struct {
 		protoType cnst.ProtoType
+		// tenant owning the config this prefix came from
+		tenant    string
 		router    *config.RouterConfig
 		server    *config.ServerConfig
 		mcpServer *config.MCPServerConfig
 		transport mcpproxy.Transport
+		// canaryTransport serves the configured share of sessions
+		canaryTransport mcpproxy.Transport
 
 		tools       map[toolName]*config.ToolConfig
 		toolSchemas []mcp.ToolSchema
 
-		prompts      map[promptName]*config.PromptConfig
+		prompts       map[promptName]*config.PromptConfig
 		promptSchemas []mcp.PromptSchema
+
+		resources               map[resourceURI]*config.ResourceConfig
+		resourceSchemas         []mcp.ResourceSchema
+		resourceTemplates       []*config.ResourceTemplateConfig
+		resourceTemplateSchemas []mcp.ResourceTemplateSchema
 	}
 
 	metrics struct {
@@ -51,9 +64,10 @@ type (
 
 func NewState() *State {
 	return &State{
-		rawConfigs: make([]*config.MCPConfig, 0),
-		runtime:    make(map[uriPrefix]runtimeUnit),
-		metrics:    metrics{},
+		rawConfigs:   make([]*config.MCPConfig, 0),
+		runtime:      make(map[uriPrefix]runtimeUnit),
+		hostPrefixes: make(map[string]string),
+		metrics:      metrics{},
 	}
 }
 
@@ -77,6 +91,10 @@ func BuildStateFromConfig(ctx context.Context, cfgs []*config.MCPConfig, oldStat
 		for _, router := range cfg.Routers {
 			prefixMap[router.Server] = append(prefixMap[router.Server], router.Prefix)
 			newState.setRouter(router.Prefix, &router)
+			newState.setTenant(router.Prefix, cfg.Tenant)
+			for _, host := range router.Hosts {
+				newState.hostPrefixes[host] = router.Prefix
+			}
 			logger.Info("registered router",
 				zap.String("tenant", cfg.Tenant),
 				zap.String("prefix", router.Prefix),
@@ -104,8 +122,14 @@ func BuildStateFromConfig(ctx context.Context, cfgs []*config.MCPConfig, oldStat
 			for _, ss := range server.AllowedTools {
 				tool, ok := toolMap[toolName(ss)]
 				if ok {
-					allowedToolSchemas = append(allowedToolSchemas, tool.ToToolSchema())
-					allowedTools[toolName(ss)] = tool
+					exposed := exposedToolName(&server, tool.Name)
+					schema := tool.ToToolSchema()
+					schema.Name = exposed
+					if _, exists := allowedTools[toolName(exposed)]; exists {
+						return nil, fmt.Errorf("tool name %q collides on server %s; configure toolNaming to disambiguate", exposed, server.Name)
+					}
+					allowedToolSchemas = append(allowedToolSchemas, schema)
+					allowedTools[toolName(exposed)] = tool
 				} else {
 					newState.metrics.missingTools++
 					logger.Warn("failed to find allowed tool for server", zap.String("server", server.Name),
@@ -120,16 +144,29 @@ func BuildStateFromConfig(ctx context.Context, cfgs []*config.MCPConfig, oldStat
 				runtime.server = &server
 				runtime.tools = allowedTools
 				runtime.toolSchemas = allowedToolSchemas
-				//runtime.prompts = map[promptName]*cfg.Prompts	
+				//runtime.prompts = map[promptName]*cfg.Prompts
 				for i := range cfg.Prompts {
-   					p := &cfg.Prompts[i]
-    				runtime.prompts[promptName(p.Name)] = p
+					p := &cfg.Prompts[i]
+					runtime.prompts[promptName(p.Name)] = p
 				}
 				//runtime.promptSchemas = cfg.Prompts.ToPromptSchemas()
 				runtime.promptSchemas = make([]mcp.PromptSchema, len(cfg.Prompts))
 				for i, p := range cfg.Prompts {
-    				runtime.promptSchemas[i] = p.ToPromptSchema()
-				}				
+					runtime.promptSchemas[i] = p.ToPromptSchema()
+				}
+				runtime.resourceSchemas = make([]mcp.ResourceSchema, len(cfg.Resources))
+				for i := range cfg.Resources {
+					r := &cfg.Resources[i]
+					runtime.resources[resourceURI(r.URI)] = r
+					runtime.resourceSchemas[i] = r.ToResourceSchema()
+				}
+				runtime.resourceTemplates = make([]*config.ResourceTemplateConfig, len(cfg.ResourceTemplates))
+				runtime.resourceTemplateSchemas = make([]mcp.ResourceTemplateSchema, len(cfg.ResourceTemplates))
+				for i := range cfg.ResourceTemplates {
+					rt := &cfg.ResourceTemplates[i]
+					runtime.resourceTemplates[i] = rt
+					runtime.resourceTemplateSchemas[i] = rt.ToResourceTemplateSchema()
+				}
 				newState.runtime[uriPrefix(prefix)] = runtime
 			}
 		}
@@ -153,24 +190,12 @@ func BuildStateFromConfig(ctx context.Context, cfgs []*config.MCPConfig, oldStat
 				var transport mcpproxy.Transport
 				if oldState != nil {
 					if oldRuntime, exists := oldState.runtime[uriPrefix(prefix)]; exists {
-						// Compare configurations to see if we need to create a new transport
+						// Reuse the warm transport only when the whole backend
+						// configuration (including env, timeout and resilience)
+						// is unchanged
 						oldConfig := oldRuntime.mcpServer
-						if oldConfig != nil && oldConfig.Type == mcpServer.Type &&
-							oldConfig.Command == mcpServer.Command &&
-							oldConfig.URL == mcpServer.URL &&
-							len(oldConfig.Args) == len(mcpServer.Args) {
-							// Compare args
-							argsMatch := true
-							for i, arg := range oldConfig.Args {
-								if arg != mcpServer.Args[i] {
-									argsMatch = false
-									break
-								}
-							}
-							if argsMatch {
-								// Reuse existing transport
-								transport = oldRuntime.transport
-							}
+						if oldConfig != nil && reflect.DeepEqual(*oldConfig, mcpServer) {
+							transport = oldRuntime.transport
 						}
 					}
 				}
@@ -178,12 +203,24 @@ func BuildStateFromConfig(ctx context.Context, cfgs []*config.MCPConfig, oldStat
 				// Create new transport if needed
 				if transport == nil {
 					var err error
-					transport, err = mcpproxy.NewTransport(mcpServer)
+					transport, err = mcpproxy.NewTransport(logger, mcpServer)
 					if err != nil {
 						return nil, fmt.Errorf("failed to create transport for server %s: %w", mcpServer.Name, err)
 					}
 				}
 
+				// Create a second transport for the canary replica
+				if mcpServer.Canary != nil && mcpServer.Canary.URL != "" {
+					canaryCfg := mcpServer
+					canaryCfg.URL = mcpServer.Canary.URL
+					canaryCfg.URLs = nil
+					canaryTransport, err := mcpproxy.NewTransport(logger, canaryCfg)
+					if err != nil {
+						return nil, fmt.Errorf("failed to create canary transport for server %s: %w", mcpServer.Name, err)
+					}
+					runtime.canaryTransport = canaryTransport
+				}
+
 				// Handle server startup based on policy and preinstalled flag
 				if mcpServer.Policy == cnst.PolicyOnStart {
 					// If PolicyOnStart is set, just start the server and keep it running
@@ -273,3 +310,18 @@ func startMCPServer(ctx context.Context, logger *zap.Logger, prefix string, mcpS
 		}
 	}
 }
+
+// exposedToolName maps a tool's configured name to the name it is exposed
+// under, applying renames and optional server namespacing
+func exposedToolName(server *config.ServerConfig, name string) string {
+	if server.ToolNaming == nil {
+		return name
+	}
+	if renamed, ok := server.ToolNaming.Rename[name]; ok && renamed != "" {
+		return renamed
+	}
+	if server.ToolNaming.Namespace {
+		return server.Name + "." + name
+	}
+	return name
+}
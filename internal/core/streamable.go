@@ -1,8 +1,11 @@
 package core
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"strings"
 	"time"
@@ -67,7 +70,12 @@ func (s *Server) handleGet(c *gin.Context) {
 
 	for {
 		select {
+		case <-conn.Done():
+			return
 		case event := <-conn.EventQueue():
+			if event == nil {
+				continue
+			}
 			switch event.Event {
 			case "message":
 				_, err := fmt.Fprintf(c.Writer, "event: message\ndata: %s\n\n", event.Data)
@@ -106,8 +114,17 @@ func (s *Server) handlePost(c *gin.Context) {
 	}
 
 	// TODO: support batch messages
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		s.sendProtocolError(c, nil, "Invalid JSON-RPC request",
+			http.StatusBadRequest, mcp.ErrorCodeParseError)
+		return
+	}
+	if !s.checkJSONDepth(c, body) {
+		return
+	}
 	var req mcp.JSONRPCRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
+	if err := json.Unmarshal(body, &req); err != nil {
 		s.sendProtocolError(c, nil, "Invalid JSON-RPC request",
 			http.StatusBadRequest, mcp.ErrorCodeParseError)
 		return
@@ -115,11 +132,12 @@ func (s *Server) handlePost(c *gin.Context) {
 
 	sessionID := c.GetHeader(mcp.HeaderMcpSessionID)
 
-	var (
-		conn session.Connection
-		err  error
-	)
+	var conn session.Connection
 	if req.Method == mcp.Initialize {
+		if s.draining.Load() {
+			s.sendProtocolError(c, req.Id, "Server is shutting down", http.StatusServiceUnavailable, mcp.ErrorCodeConnectionClosed)
+			return
+		}
 		if sessionID != "" {
 			// confirm if it's registered
 			conn, err = s.sessions.Get(c.Request.Context(), sessionID)
@@ -133,16 +151,14 @@ func (s *Server) handlePost(c *gin.Context) {
 			}
 		} else {
 			sessionID = uuid.New().String()
-			prefix := strings.TrimSuffix(c.Request.URL.Path, "/mcp")
-			if prefix == "" {
-				prefix = "/"
-			}
+			prefix := routePrefix(c, "/mcp")
 
 			meta := &session.Meta{
-				ID:        sessionID,
-				CreatedAt: time.Now(),
-				Prefix:    prefix,
-				Type:      "streamable",
+				ID:         sessionID,
+				CreatedAt:  time.Now(),
+				Prefix:     prefix,
+				Type:       "streamable",
+				RemoteAddr: c.ClientIP(),
 			}
 			conn, err = s.sessions.Register(c.Request.Context(), meta)
 			if err != nil {
@@ -160,6 +176,13 @@ func (s *Server) handlePost(c *gin.Context) {
 		}
 	}
 
+	// Responses to gateway-initiated requests (e.g. roots/list) carry no
+	// method; consume them instead of failing with MethodNotFound
+	if req.Method == "" && s.handleClientResponse(body, conn) {
+		c.Status(http.StatusAccepted)
+		return
+	}
+
 	s.handleMCPRequest(c, req, conn)
 }
 
@@ -175,6 +198,7 @@ func (s *Server) handleDelete(c *gin.Context) {
 		s.sendProtocolError(c, conn.Meta().ID, "Failed to terminate session", http.StatusInternalServerError, mcp.ErrorCodeInternalError)
 		return
 	}
+	s.clientRoots.delete(conn.Meta().ID)
 	c.Status(http.StatusOK)
 }
 
@@ -189,13 +213,19 @@ func (s *Server) handleMCPRequest(c *gin.Context, req mcp.JSONRPCRequest, conn s
 			return
 		}
 
+		// Enrich the session with the client's identity
+		conn.Meta().ClientName = params.ClientInfo.Name
+		conn.Meta().ClientVersion = params.ClientInfo.Version
+
 		s.sendSuccessResponse(c, conn, req, mcp.InitializedResult{
-			ProtocolVersion: mcp.LatestProtocolVersion,
+			ProtocolVersion: mcp.NegotiateProtocolVersion(params.ProtocolVersion),
 			Capabilities: mcp.ServerCapabilitiesSchema{
 				Logging: mcp.LoggingCapabilitySchema{},
 				Tools: mcp.ToolsCapabilitySchema{
 					ListChanged: true,
 				},
+				Prompts:   mcp.PromptsCapabilitySchema{},
+				Resources: mcp.ResourcesCapabilitySchema{},
 			},
 			ServerInfo: mcp.ImplementationSchema{
 				Name:    cnst.AppName,
@@ -208,30 +238,66 @@ func (s *Server) handleMCPRequest(c *gin.Context, req mcp.JSONRPCRequest, conn s
 		c.Status(http.StatusAccepted)
 		return
 
+	case mcp.Ping:
+		// Respond to ping with an empty result
+		s.sendSuccessResponse(c, conn, req, struct{}{}, false)
+		return
+
+	case mcp.NotificationRootsListChanged:
+		// Refresh the cached roots for this session
+		s.requestClientRoots(c.Request.Context(), conn)
+		c.Status(http.StatusAccepted)
+		return
+
+	case mcp.LoggingSetLevel:
+		var params struct {
+			Level string `json:"level"`
+		}
+		if err := json.Unmarshal(req.Params, &params); err != nil || !s.setSessionLogLevel(conn.Meta().ID, params.Level) {
+			s.sendProtocolError(c, req.Id, "Invalid logging level", http.StatusBadRequest, mcp.ErrorCodeInvalidParams)
+			return
+		}
+		s.sendSuccessResponse(c, conn, req, struct{}{}, false)
+		return
+
+	case mcp.NotificationCancelled:
+		var params struct {
+			RequestID any `json:"requestId"`
+		}
+		_ = json.Unmarshal(req.Params, &params)
+		s.cancelToolCall(conn.Meta().ID, params.RequestID)
+		c.Status(http.StatusAccepted)
+		return
+
 	case mcp.ToolsList:
-		protoType := s.state.GetProtoType(conn.Meta().Prefix)
+		protoType := s.currentState().GetProtoType(conn.Meta().Prefix)
 		if protoType == "" {
 			s.sendProtocolError(c, req.Id, "Server configuration not found", http.StatusInternalServerError, mcp.ErrorCodeInternalError)
 			return
 		}
 
+		var pageParams mcp.PaginatedRequestParams
+		if len(req.Params) > 0 {
+			_ = json.Unmarshal(req.Params, &pageParams)
+		}
+
 		var tools []mcp.ToolSchema
 		var err error
 		switch protoType {
 		case cnst.BackendProtoHttp:
 			// Get tools for HTTP backend
-			tools = s.state.GetToolSchemas(conn.Meta().Prefix)
+			tools = s.currentState().GetToolSchemas(conn.Meta().Prefix)
 			if len(tools) == 0 {
 				tools = []mcp.ToolSchema{}
 			}
 		case cnst.BackendProtoStdio, cnst.BackendProtoSSE, cnst.BackendProtoStreamable:
-			transport := s.state.GetTransport(conn.Meta().Prefix)
+			transport := s.currentState().GetTransport(conn.Meta().Prefix)
 			if transport == nil {
 				s.sendProtocolError(c, req.Id, "Failed to fetch tools", http.StatusInternalServerError, mcp.ErrorCodeInternalError)
 				return
 			}
 
-			tools, err = transport.FetchTools(c.Request.Context())
+			tools, err = s.fetchProxiedTools(c.Request.Context(), conn.Meta().Prefix, transport)
 			if err != nil {
 				s.sendProtocolError(c, req.Id, "Failed to fetch tools", http.StatusInternalServerError, mcp.ErrorCodeInternalError)
 				return
@@ -241,13 +307,22 @@ func (s *Server) handleMCPRequest(c *gin.Context, req mcp.JSONRPCRequest, conn s
 			return
 		}
 
+		tools = s.filterTools(c, conn.Meta().Prefix, tools)
+
+		page, nextCursor, err := s.paginateTools(tools, pageParams.Cursor)
+		if err != nil {
+			s.sendProtocolError(c, req.Id, "Invalid cursor", http.StatusBadRequest, mcp.ErrorCodeInvalidParams)
+			return
+		}
+
 		s.sendSuccessResponse(c, conn, req, mcp.ListToolsResult{
-			Tools: tools,
+			Tools:      page,
+			NextCursor: nextCursor,
 		}, false)
 		return
 
 	case mcp.ToolsCall:
-		protoType := s.state.GetProtoType(conn.Meta().Prefix)
+		protoType := s.currentState().GetProtoType(conn.Meta().Prefix)
 		if protoType == "" {
 			s.sendProtocolError(c, req.Id, "Server configuration not found", http.StatusInternalServerError, mcp.ErrorCodeInternalError)
 			return
@@ -260,23 +335,69 @@ func (s *Server) handleMCPRequest(c *gin.Context, req mcp.JSONRPCRequest, conn s
 			return
 		}
 
+		if !s.checkToolAccess(c, req.Id, conn.Meta().Prefix, params.Name) {
+			return
+		}
+		if !s.checkPolicy(c, req.Id, conn, params.Name, params.Arguments) {
+			return
+		}
+		if !s.checkToolRateLimit(c, req.Id, conn.Meta().Prefix, params.Name, conn.Meta().ID) {
+			return
+		}
+		if !s.checkQuota(c, req.Id, conn.Meta().Prefix, params.Name) {
+			return
+		}
+
+		s.inflight.Add(1)
+		defer s.inflight.Done()
+
+		// Allow notifications/cancelled to abort this call
+		cancelCtx, cancelCall := context.WithCancel(c.Request.Context())
+		defer cancelCall()
+		unregisterCancel := s.registerCancel(conn.Meta().ID, req.Id, cancelCall)
+		defer unregisterCancel()
+		c.Request = c.Request.WithContext(cancelCtx)
+
+		callStart := time.Now()
+		caller := rateLimitClientKey(c, conn.Meta().ID)
+		s.stats.recordToolCall(conn.Meta().ID)
+		s.sessionLog(c.Request.Context(), conn, "info", "tool call started: "+params.Name)
+
 		var (
 			result *mcp.CallToolResult
 			err    error
 		)
 		switch protoType {
 		case cnst.BackendProtoHttp:
+			// callHTTPTool has already responded when it returns nil
 			result = s.callHTTPTool(c, req, conn, params)
+			if result == nil {
+				s.auditToolCall(c.Request.Context(), conn.Meta().ID, conn.Meta().Prefix, params.Name, caller,
+					params.Arguments, fmt.Errorf("tool execution failed"), time.Since(callStart))
+				return
+			}
 		case cnst.BackendProtoStdio, cnst.BackendProtoSSE, cnst.BackendProtoStreamable:
-			transport := s.state.GetTransport(conn.Meta().Prefix)
+			transport := s.transportForSession(conn.Meta().Prefix, conn.Meta().ID)
 			if transport == nil {
 				errMsg := "Server configuration not found"
 				s.sendProtocolError(c, req.Id, errMsg, http.StatusNotFound, mcp.ErrorCodeMethodNotFound)
 				return
 			}
 
-			result, err = transport.CallTool(c.Request.Context(), params, mergeRequestInfo(conn.Meta().Request, c.Request))
+			callCtx, cancel := s.toolCallContext(c, conn.Meta().Prefix)
+			upstreamToken, unroute := s.routeProgressToken(params.Meta.ProgressToken, conn)
+			callParams := params
+			callParams.Meta.ProgressToken = upstreamToken
+			result, err = transport.CallTool(callCtx, callParams, mergeRequestInfo(conn.Meta().Request, c.Request))
+			unroute()
+			cancel()
 			if err != nil {
+				if errors.Is(callCtx.Err(), context.DeadlineExceeded) {
+					err = fmt.Errorf("tool call timed out")
+				}
+				s.auditToolCall(c.Request.Context(), conn.Meta().ID, conn.Meta().Prefix, params.Name, caller,
+					params.Arguments, err, time.Since(callStart))
+				s.sessionLog(c.Request.Context(), conn, "error", "tool call failed: "+params.Name+": "+err.Error())
 				s.sendToolExecutionError(c, conn, req, err, true)
 				return
 			}
@@ -286,11 +407,67 @@ func (s *Server) handleMCPRequest(c *gin.Context, req mcp.JSONRPCRequest, conn s
 			return
 		}
 
+		s.auditToolCall(c.Request.Context(), conn.Meta().ID, conn.Meta().Prefix, params.Name, caller,
+			params.Arguments, nil, time.Since(callStart))
+		s.sessionLog(c.Request.Context(), conn, "info", "tool call completed: "+params.Name)
 		s.sendSuccessResponse(c, conn, req, result, false)
 		return
 
+	case mcp.ResourcesList:
+		resources := s.currentState().GetResourceSchemas(conn.Meta().Prefix)
+		if len(resources) == 0 {
+			resources = []mcp.ResourceSchema{}
+		}
+		s.sendSuccessResponse(c, conn, req, mcp.ListResourcesResult{
+			Resources: resources,
+		}, false)
+		return
+
+	case mcp.ResourcesTemplatesList:
+		templates := s.currentState().GetResourceTemplateSchemas(conn.Meta().Prefix)
+		if len(templates) == 0 {
+			templates = []mcp.ResourceTemplateSchema{}
+		}
+		s.sendSuccessResponse(c, conn, req, mcp.ListResourceTemplatesResult{
+			ResourceTemplates: templates,
+		}, false)
+		return
+
+	case mcp.ResourcesRead:
+		var params mcp.ReadResourceRequestParams
+		if err := json.Unmarshal(req.Params, &params); err != nil || params.URI == "" {
+			s.sendProtocolError(c, req.Id, "Invalid resource read parameters", http.StatusBadRequest, mcp.ErrorCodeInvalidParams)
+			return
+		}
+
+		resource, args, err := s.readResource(conn.Meta().Prefix, params.URI)
+		if err != nil {
+			s.sendProtocolError(c, req.Id, "Resource not found", http.StatusNotFound, mcp.ErrorCodeInvalidParams)
+			return
+		}
+
+		text, err := renderResourceContents(resource, args, s.isUntrustedTenant(conn.Meta().Prefix))
+		if err != nil {
+			s.logger.Error("failed to render resource contents",
+				zap.String("uri", params.URI),
+				zap.Error(err))
+			s.sendProtocolError(c, req.Id, "Failed to read resource", http.StatusInternalServerError, mcp.ErrorCodeInternalError)
+			return
+		}
+
+		s.sendSuccessResponse(c, conn, req, mcp.ReadResourceResult{
+			Contents: []mcp.ResourceContents{
+				{
+					URI:      params.URI,
+					MimeType: resource.MimeType,
+					Text:     text,
+				},
+			},
+		}, false)
+		return
+
 	case mcp.PromptsList:
-		protoType := s.state.GetProtoType(conn.Meta().Prefix)
+		protoType := s.currentState().GetProtoType(conn.Meta().Prefix)
 		if protoType == "" {
 			s.sendProtocolError(c, req.Id, "Server configuration not found", http.StatusInternalServerError, mcp.ErrorCodeInternalError)
 			return
@@ -300,12 +477,12 @@ func (s *Server) handleMCPRequest(c *gin.Context, req mcp.JSONRPCRequest, conn s
 		var err error
 		switch protoType {
 		case cnst.BackendProtoHttp:
-			prompts = s.state.GetPromptSchemas(conn.Meta().Prefix)
+			prompts = s.currentState().GetPromptSchemas(conn.Meta().Prefix)
 			if len(prompts) == 0 {
 				prompts = []mcp.PromptSchema{}
 			}
 		case cnst.BackendProtoStdio, cnst.BackendProtoSSE, cnst.BackendProtoStreamable:
-			transport := s.state.GetTransport(conn.Meta().Prefix)
+			transport := s.currentState().GetTransport(conn.Meta().Prefix)
 			if transport == nil {
 				s.sendProtocolError(c, req.Id, "Failed to fetch prompts", http.StatusInternalServerError, mcp.ErrorCodeInternalError)
 				return
@@ -321,24 +498,19 @@ func (s *Server) handleMCPRequest(c *gin.Context, req mcp.JSONRPCRequest, conn s
 			return
 		}
 
-		s.sendSuccessResponse(c, conn, req, struct {
-			Prompts []mcp.PromptSchema `json:"prompts"`
-		}{
+		s.sendSuccessResponse(c, conn, req, mcp.ListPromptsResult{
 			Prompts: prompts,
 		}, false)
 		return
 
 	case mcp.PromptsGet:
-		protoType := s.state.GetProtoType(conn.Meta().Prefix)
+		protoType := s.currentState().GetProtoType(conn.Meta().Prefix)
 		if protoType == "" {
 			s.sendProtocolError(c, req.Id, "Server configuration not found", http.StatusInternalServerError, mcp.ErrorCodeInternalError)
 			return
 		}
 
-		var params struct {
-			Name      string            `json:"name"`
-			Arguments map[string]string `json:"arguments"`
-		}
+		var params mcp.GetPromptRequestParams
 		if err := json.Unmarshal(req.Params, &params); err != nil || params.Name == "" {
 			s.sendProtocolError(c, req.Id, "Invalid prompt get parameters", http.StatusBadRequest, mcp.ErrorCodeInvalidParams)
 			return
@@ -348,16 +520,15 @@ func (s *Server) handleMCPRequest(c *gin.Context, req mcp.JSONRPCRequest, conn s
 		var err error
 		switch protoType {
 		case cnst.BackendProtoHttp:
-			prompts := s.state.GetPromptSchemas(conn.Meta().Prefix)
+			prompts := s.currentState().GetPromptSchemas(conn.Meta().Prefix)
 			for i := range prompts {
 				if prompts[i].Name == params.Name {
 					prompt = &prompts[i]
 					break
 				}
 			}
-			s.logger.Info("PromptsGet-prompt found", zap.String("params.Name", params.Name), zap.String("promptname", prompt.Name))
 		case cnst.BackendProtoStdio, cnst.BackendProtoSSE, cnst.BackendProtoStreamable:
-			transport := s.state.GetTransport(conn.Meta().Prefix)
+			transport := s.currentState().GetTransport(conn.Meta().Prefix)
 			if transport == nil {
 				s.sendProtocolError(c, req.Id, "Failed to fetch prompt", http.StatusInternalServerError, mcp.ErrorCodeInternalError)
 				return
@@ -378,44 +549,15 @@ func (s *Server) handleMCPRequest(c *gin.Context, req mcp.JSONRPCRequest, conn s
 		}
 
 		// Build the response with argument substitution
-		argsMap := params.Arguments
-		resp := struct {
-			Description string `json:"description"`
-			Messages    []struct {
-				Role    string `json:"role"`
-				Content struct {
-					Type string `json:"type"`
-					Text string `json:"text"`
-				} `json:"content"`
-			} `json:"messages"`
-		}{
-			Description: prompt.Description,
-		}
-		for _, msg := range prompt.PromptResponse {
-			text := msg.Content.Text
-			for _, arg := range prompt.Arguments {
-				if val, ok := argsMap[arg.Name]; ok {
-					text = strings.ReplaceAll(text, "{"+arg.Name+"}", val)
-				}
-			}
-			resp.Messages = append(resp.Messages, struct {
-				Role    string `json:"role"`
-				Content struct {
-					Type string `json:"type"`
-					Text string `json:"text"`
-				} `json:"content"`
-			}{
-				Role: msg.Role,
-				Content: struct {
-					Type string `json:"type"`
-					Text string `json:"text"`
-				}{
-					Type: msg.Content.Type,
-					Text: text,
-				},
-			})
+		result, err := renderPrompt(prompt, params.Arguments, s.isUntrustedTenant(conn.Meta().Prefix))
+		if err != nil {
+			s.logger.Error("failed to render prompt",
+				zap.String("prompt", params.Name),
+				zap.Error(err))
+			s.sendProtocolError(c, req.Id, "Failed to render prompt", http.StatusInternalServerError, mcp.ErrorCodeInternalError)
+			return
 		}
-		s.sendSuccessResponse(c, conn, req, resp, false)
+		s.sendSuccessResponse(c, conn, req, result, false)
 		return
 
 	default:
@@ -0,0 +1,67 @@
+package core
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/amoylab/unla/internal/mcp/session"
+	"github.com/amoylab/unla/pkg/mcp"
+
+	"go.uber.org/zap"
+)
+
+// isStreamingResponse reports whether an upstream response should be
+// forwarded incrementally instead of buffered
+func isStreamingResponse(resp *http.Response) bool {
+	return strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream")
+}
+
+// streamResponseToSession forwards an upstream streaming body line by line
+// as notifications/progress events on the session stream, returning the
+// accumulated body as the final tool result
+func (s *Server) streamResponseToSession(ctx context.Context, conn session.Connection, progressToken any, resp *http.Response) (*mcp.CallToolResult, error) {
+	var builder strings.Builder
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	progress := 0
+	for scanner.Scan() {
+		line := scanner.Text()
+		builder.WriteString(line)
+		builder.WriteString("\n")
+		if line == "" {
+			continue
+		}
+
+		progress++
+		notification, err := json.Marshal(struct {
+			JSONRPC string `json:"jsonrpc"`
+			Method  string `json:"method"`
+			Params  any    `json:"params"`
+		}{
+			JSONRPC: mcp.JSPNRPCVersion,
+			Method:  mcp.NotificationProgress,
+			Params: map[string]any{
+				"progressToken": progressToken,
+				"progress":      progress,
+				"message":       line,
+			},
+		})
+		if err != nil {
+			continue
+		}
+		if err := conn.Send(ctx, &session.Message{Event: "message", Data: notification}); err != nil {
+			s.logger.Warn("failed to forward streaming chunk",
+				zap.String("session_id", conn.Meta().ID),
+				zap.Error(err))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return mcp.NewCallToolResultText(builder.String()), nil
+}
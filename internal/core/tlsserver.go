@@ -0,0 +1,156 @@
+package core
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/amoylab/unla/internal/common/config"
+
+	"go.uber.org/zap"
+	"golang.org/x/crypto/acme"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// certReloader serves the certificate from disk, re-reading it whenever the
+// file changes so renewals don't require a restart
+type certReloader struct {
+	certFile string
+	keyFile  string
+	logger   *zap.Logger
+
+	mu      sync.Mutex
+	cert    *tls.Certificate
+	modTime time.Time
+}
+
+// getCertificate implements tls.Config.GetCertificate
+func (r *certReloader) getCertificate(_ *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	info, err := os.Stat(r.certFile)
+	if err == nil && (r.cert == nil || info.ModTime().After(r.modTime)) {
+		cert, loadErr := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+		if loadErr != nil {
+			if r.cert == nil {
+				return nil, loadErr
+			}
+			r.logger.Warn("failed to reload TLS certificate, keeping previous one",
+				zap.Error(loadErr))
+		} else {
+			r.cert = &cert
+			r.modTime = info.ModTime()
+			r.logger.Info("TLS certificate loaded",
+				zap.String("cert_file", r.certFile))
+		}
+	}
+	if r.cert == nil {
+		return nil, fmt.Errorf("no TLS certificate available")
+	}
+	return r.cert, nil
+}
+
+// newTLSConfig builds the listener TLS configuration with hot certificate
+// reload and optional mTLS
+func newTLSConfig(logger *zap.Logger, cfg *config.TLSConfig) (*tls.Config, error) {
+	reloader := &certReloader{
+		certFile: cfg.CertFile,
+		keyFile:  cfg.KeyFile,
+		logger:   logger.Named("tls"),
+	}
+	// Fail fast on startup when the initial pair cannot be loaded
+	if _, err := reloader.getCertificate(nil); err != nil {
+		return nil, fmt.Errorf("failed to load TLS certificate: %w", err)
+	}
+
+	tlsCfg := &tls.Config{
+		GetCertificate: reloader.getCertificate,
+		MinVersion:     tls.VersionTLS12,
+		NextProtos:     []string{"h2", "http/1.1"},
+	}
+
+	if cfg.ClientCAFile != "" {
+		caData, err := os.ReadFile(cfg.ClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read client CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caData) {
+			return nil, fmt.Errorf("client CA file contains no valid certificates")
+		}
+		tlsCfg.ClientCAs = pool
+		tlsCfg.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	return tlsCfg, nil
+}
+
+// serveACME runs the gateway listener with certificates obtained and
+// renewed automatically through ACME
+func (s *Server) serveACME() error {
+	acmeCfg := s.cfg.TLS.ACME
+
+	cacheDir := acmeCfg.CacheDir
+	if cacheDir == "" {
+		cacheDir = "./acme-cache"
+	}
+	manager := &autocert.Manager{
+		Prompt:     autocert.AcceptTOS,
+		HostPolicy: autocert.HostWhitelist(acmeCfg.Domains...),
+		Cache:      autocert.DirCache(cacheDir),
+		Email:      acmeCfg.Email,
+	}
+	if acmeCfg.DirectoryURL != "" {
+		manager.Client = &acme.Client{DirectoryURL: acmeCfg.DirectoryURL}
+	}
+
+	// Serve the HTTP-01 challenge (and redirect everything else to HTTPS)
+	httpPort := acmeCfg.HTTPPort
+	if httpPort == 0 {
+		httpPort = 80
+	}
+	go func() {
+		addr := fmt.Sprintf(":%d", httpPort)
+		s.logger.Info("starting ACME HTTP-01 challenge listener", zap.String("addr", addr))
+		if err := http.ListenAndServe(addr, manager.HTTPHandler(nil)); err != nil {
+			s.logger.Error("ACME challenge listener failed", zap.Error(err))
+		}
+	}()
+
+	tlsCfg := manager.TLSConfig()
+	tlsCfg.MinVersion = tls.VersionTLS12
+
+	server := &http.Server{
+		Addr:      fmt.Sprintf(":%d", s.cfg.Port),
+		Handler:   s.router,
+		TLSConfig: tlsCfg,
+	}
+	s.logger.Info("serving TLS with ACME-managed certificates",
+		zap.Strings("domains", acmeCfg.Domains))
+	return server.ListenAndServeTLS("", "")
+}
+
+// serveTLS runs the gateway listener with TLS enabled
+func (s *Server) serveTLS() error {
+	if s.cfg.TLS.ACME != nil {
+		return s.serveACME()
+	}
+
+	tlsCfg, err := newTLSConfig(s.logger, s.cfg.TLS)
+	if err != nil {
+		return err
+	}
+
+	server := &http.Server{
+		Addr:      fmt.Sprintf(":%d", s.cfg.Port),
+		Handler:   s.router,
+		TLSConfig: tlsCfg,
+	}
+	// Cert/key come from TLSConfig.GetCertificate
+	return server.ListenAndServeTLS("", "")
+}
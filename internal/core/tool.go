@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"mime/multipart"
@@ -12,9 +13,10 @@ import (
 	"net/url"
 	"strings"
 
-	"github.com/gin-gonic/gin"
+	"github.com/amoylab/unla/internal/auth"
 	"github.com/amoylab/unla/internal/mcp/session"
 	"github.com/amoylab/unla/pkg/mcp"
+	"github.com/gin-gonic/gin"
 	"golang.org/x/net/proxy"
 
 	"github.com/amoylab/unla/internal/common/config"
@@ -143,11 +145,86 @@ func createHTTPClient(tool *config.ToolConfig) (*http.Client, error) {
 	return &http.Client{}, nil
 }
 
+// isUntrustedTenant reports whether a prefix belongs to a tenant whose
+// configs must render without env and secret access
+func (s *Server) isUntrustedTenant(prefix string) bool {
+	tenant := s.currentState().GetTenant(prefix)
+	if tenant == "" {
+		return false
+	}
+	for _, untrusted := range s.cfg.UntrustedTenants {
+		if untrusted == tenant {
+			return true
+		}
+	}
+	return false
+}
+
 // executeHTTPTool executes a tool with the given arguments
-func (s *Server) executeHTTPTool(conn session.Connection, tool *config.ToolConfig, args map[string]any, request *http.Request, serverCfg map[string]string) (*mcp.CallToolResult, error) {
+func (s *Server) executeHTTPTool(conn session.Connection, tool *config.ToolConfig, args map[string]any, request *http.Request, server *config.ServerConfig, progressToken any) (*mcp.CallToolResult, error) {
+	serverCfg := server.Config
 	// Fill default values for missing arguments
 	fillDefaultArgs(tool, args)
 
+	// Mock tools serve canned responses
+	if tool.Mock != nil {
+		s.logger.Info("executing mock tool",
+			zap.String("tool", tool.Name),
+			zap.String("session_id", conn.Meta().ID))
+		return invokeMockTool(request.Context(), tool, args, s.isUntrustedTenant(conn.Meta().Prefix))
+	}
+
+	// Composite tools orchestrate other tools on the same server
+	if tool.Pipeline != nil {
+		s.logger.Info("executing pipeline tool",
+			zap.String("tool", tool.Name),
+			zap.Int("steps", len(tool.Pipeline.Steps)),
+			zap.String("session_id", conn.Meta().ID))
+		return s.invokePipelineTool(conn, tool, args, request, server)
+	}
+
+	// gRPC-backed tools bypass the HTTP pipeline entirely
+	if tool.GRPC != nil {
+		s.logger.Info("executing gRPC tool",
+			zap.String("tool", tool.Name),
+			zap.String("target", tool.GRPC.Target),
+			zap.String("grpc_method", tool.GRPC.Method),
+			zap.String("session_id", conn.Meta().ID))
+		return invokeGRPCTool(request.Context(), tool, args)
+	}
+	// So do AWS Lambda tools
+	if tool.Lambda != nil {
+		s.logger.Info("executing lambda tool",
+			zap.String("tool", tool.Name),
+			zap.String("function", tool.Lambda.Function),
+			zap.String("session_id", conn.Meta().ID))
+		return invokeLambdaTool(request.Context(), tool, args)
+	}
+	// So do local command tools
+	if tool.Command != nil {
+		s.logger.Info("executing command tool",
+			zap.String("tool", tool.Name),
+			zap.String("binary", tool.Command.Binary),
+			zap.String("session_id", conn.Meta().ID))
+		return invokeCommandTool(request.Context(), tool, args, s.cfg.CommandTools.AllowedBinaries, s.isUntrustedTenant(conn.Meta().Prefix))
+	}
+	// So do SQL-backed tools
+	if tool.Database != nil {
+		s.logger.Info("executing SQL tool",
+			zap.String("tool", tool.Name),
+			zap.String("driver", tool.Database.Driver),
+			zap.String("session_id", conn.Meta().ID))
+		return invokeSQLTool(request.Context(), tool, args)
+	}
+	// So do GraphQL-backed tools
+	if tool.GraphQL != nil {
+		s.logger.Info("executing GraphQL tool",
+			zap.String("tool", tool.Name),
+			zap.String("endpoint", tool.GraphQL.Endpoint),
+			zap.String("session_id", conn.Meta().ID))
+		return invokeGraphQLTool(request.Context(), tool, args)
+	}
+
 	// Normalize JSON string values in arguments
 	template.NormalizeJSONStringValues(args)
 
@@ -167,6 +244,7 @@ func (s *Server) executeHTTPTool(conn session.Connection, tool *config.ToolConfi
 			zap.Error(err))
 		return nil, err
 	}
+	tmplCtx.Restricted = s.isUntrustedTenant(conn.Meta().Prefix)
 
 	// Prepare HTTP request
 	req, err := prepareRequest(tool, tmplCtx)
@@ -178,6 +256,18 @@ func (s *Server) executeHTTPTool(conn session.Connection, tool *config.ToolConfi
 		return nil, err
 	}
 
+	// Inject an upstream client-credentials token when configured
+	if server.UpstreamAuth != nil {
+		token, err := auth.GetUpstreamTokenSource(server.UpstreamAuth).Token(request.Context())
+		if err != nil {
+			s.logger.Error("failed to fetch upstream token",
+				zap.String("tool", tool.Name),
+				zap.Error(err))
+			return nil, fmt.Errorf("failed to fetch upstream token: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
 	// Log request details at debug level
 	s.logger.Debug("tool request details",
 		zap.String("tool", tool.Name),
@@ -203,8 +293,20 @@ func (s *Server) executeHTTPTool(conn session.Connection, tool *config.ToolConfi
 		zap.String("url", req.URL.String()),
 		zap.String("session_id", conn.Meta().ID))
 
+	// Bound the upstream call with the per-tool timeout when configured
+	callCtx := request.Context()
+	if tool.Timeout > 0 {
+		var cancel context.CancelFunc
+		callCtx, cancel = context.WithTimeout(callCtx, tool.Timeout)
+		defer cancel()
+	}
+	req = req.WithContext(callCtx)
+
 	resp, err := cli.Do(req)
 	if err != nil {
+		if errors.Is(callCtx.Err(), context.DeadlineExceeded) {
+			err = fmt.Errorf("tool call timed out after %s", tool.Timeout)
+		}
 		s.logger.Error("failed to execute HTTP request",
 			zap.String("tool", tool.Name),
 			zap.String("url", req.URL.String()),
@@ -214,6 +316,14 @@ func (s *Server) executeHTTPTool(conn session.Connection, tool *config.ToolConfi
 	}
 	defer resp.Body.Close()
 
+	// Forward streaming upstream bodies incrementally instead of buffering
+	if isStreamingResponse(resp) {
+		s.logger.Info("streaming tool response to session",
+			zap.String("tool", tool.Name),
+			zap.String("session_id", conn.Meta().ID))
+		return s.streamResponseToSession(request.Context(), conn, progressToken, resp)
+	}
+
 	// Read response body for logging in case of error
 	respBodyBytes, err := io.ReadAll(resp.Body)
 	if err != nil {
@@ -260,7 +370,7 @@ func (s *Server) fetchHTTPToolList(conn session.Connection) ([]mcp.ToolSchema, e
 		zap.String("prefix", conn.Meta().Prefix))
 
 	// Get http tools for this prefix
-	tools := s.state.GetToolSchemas(conn.Meta().Prefix)
+	tools := s.currentState().GetToolSchemas(conn.Meta().Prefix)
 	if len(tools) == 0 {
 		s.logger.Warn("no tools found for prefix",
 			zap.String("prefix", conn.Meta().Prefix),
@@ -284,7 +394,7 @@ func (s *Server) callHTTPTool(c *gin.Context, req mcp.JSONRPCRequest, conn sessi
 		zap.String("remote_addr", c.Request.RemoteAddr))
 
 	// Find the tool in the precomputed map
-	tool := s.state.GetTool(conn.Meta().Prefix, params.Name)
+	tool := s.currentState().GetTool(conn.Meta().Prefix, params.Name)
 	if tool == nil {
 		s.logger.Warn("tool not found",
 			zap.String("tool", params.Name),
@@ -305,9 +415,13 @@ func (s *Server) callHTTPTool(c *gin.Context, req mcp.JSONRPCRequest, conn sessi
 		return nil
 	}
 
-	// Log tool arguments at debug level
+	if !s.validateToolArgs(c, req.Id, conn.Meta().Prefix, params.Name, args) {
+		return nil
+	}
+
+	// Log tool arguments at debug level with sensitive values masked
 	if s.logger.Core().Enabled(zap.DebugLevel) {
-		argsJSON, _ := json.Marshal(args)
+		argsJSON, _ := json.Marshal(redactArguments(args, s.redactionFieldsFor(conn.Meta().Prefix, params.Name)))
 		s.logger.Debug("tool arguments",
 			zap.String("tool", params.Name),
 			zap.String("session_id", conn.Meta().ID),
@@ -315,7 +429,7 @@ func (s *Server) callHTTPTool(c *gin.Context, req mcp.JSONRPCRequest, conn sessi
 	}
 
 	// Get server configuration
-	serverCfg := s.state.GetServerConfig(conn.Meta().Prefix)
+	serverCfg := s.currentState().GetServerConfig(conn.Meta().Prefix)
 	if serverCfg == nil {
 		s.logger.Error("server configuration not found",
 			zap.String("tool", params.Name),
@@ -326,7 +440,7 @@ func (s *Server) callHTTPTool(c *gin.Context, req mcp.JSONRPCRequest, conn sessi
 	}
 
 	// Execute the tool
-	result, err := s.executeHTTPTool(conn, tool, args, c.Request, serverCfg.Config)
+	result, err := s.executeHTTPTool(conn, tool, args, c.Request, serverCfg, params.Meta.ProgressToken)
 	if err != nil {
 		s.logger.Error("tool execution failed",
 			zap.String("tool", params.Name),
@@ -0,0 +1,141 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/amoylab/unla/internal/common/config"
+
+	"github.com/amoylab/unla/internal/core/mcpproxy"
+	"github.com/amoylab/unla/pkg/mcp"
+
+	"go.uber.org/zap"
+)
+
+// toolListEntry is one cached upstream tool list
+type toolListEntry struct {
+	tools     []mcp.ToolSchema
+	fetchedAt time.Time
+}
+
+// toolListCache caches proxied backends' tool lists so a slow or briefly
+// unavailable upstream doesn't stall or empty tools/list
+type toolListCache struct {
+	mu         sync.Mutex
+	entries    map[string]*toolListEntry
+	refreshing map[string]bool
+}
+
+func newToolListCache() *toolListCache {
+	return &toolListCache{
+		entries:    make(map[string]*toolListEntry),
+		refreshing: make(map[string]bool),
+	}
+}
+
+// fetchProxiedTools returns the tool list for a proxied prefix, serving
+// from cache within the TTL and serving stale data while a background
+// refresh runs once the TTL has expired
+func (s *Server) fetchProxiedTools(ctx context.Context, prefix string, transport mcpproxy.Transport) ([]mcp.ToolSchema, error) {
+	ttl := s.cfg.ToolListCacheTTL
+	if ttl <= 0 {
+		// Caching disabled
+		tools, err := transport.FetchTools(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return s.overrideProxiedTools(prefix, tools), nil
+	}
+
+	s.toolCache.mu.Lock()
+	entry := s.toolCache.entries[prefix]
+	if entry != nil {
+		age := time.Since(entry.fetchedAt)
+		if age < ttl {
+			tools := entry.tools
+			s.toolCache.mu.Unlock()
+			return tools, nil
+		}
+		// Stale: serve the previous list and refresh in the background
+		if !s.toolCache.refreshing[prefix] {
+			s.toolCache.refreshing[prefix] = true
+			go s.refreshToolList(prefix, transport)
+		}
+		tools := entry.tools
+		s.toolCache.mu.Unlock()
+		return tools, nil
+	}
+	s.toolCache.mu.Unlock()
+
+	// Cold cache: fetch synchronously
+	tools, err := transport.FetchTools(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tools = s.overrideProxiedTools(prefix, tools)
+	s.toolCache.mu.Lock()
+	s.toolCache.entries[prefix] = &toolListEntry{tools: tools, fetchedAt: time.Now()}
+	s.toolCache.mu.Unlock()
+	return tools, nil
+}
+
+// refreshToolList updates one cache entry in the background; on failure the
+// stale entry is kept so the prefix never goes empty
+func (s *Server) refreshToolList(prefix string, transport mcpproxy.Transport) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	tools, err := transport.FetchTools(ctx)
+
+	s.toolCache.mu.Lock()
+	defer s.toolCache.mu.Unlock()
+	s.toolCache.refreshing[prefix] = false
+	if err != nil {
+		s.logger.Warn("background tool list refresh failed, keeping stale list",
+			zap.String("prefix", prefix),
+			zap.Error(err))
+		return
+	}
+	s.toolCache.entries[prefix] = &toolListEntry{tools: s.overrideProxiedTools(prefix, tools), fetchedAt: time.Now()}
+}
+
+// applyToolOverrides replaces configured fields of proxied tool schemas so
+// descriptions and schemas can be improved without patching the upstream
+func applyToolOverrides(overrides map[string]config.ToolOverrideConfig, tools []mcp.ToolSchema) []mcp.ToolSchema {
+	if len(overrides) == 0 {
+		return tools
+	}
+	for i := range tools {
+		override, ok := overrides[tools[i].Name]
+		if !ok {
+			continue
+		}
+		if override.Description != "" {
+			tools[i].Description = override.Description
+		}
+		if override.InputSchema != nil {
+			raw, err := json.Marshal(override.InputSchema)
+			if err == nil {
+				var schema mcp.ToolInputSchema
+				if json.Unmarshal(raw, &schema) == nil {
+					tools[i].InputSchema = schema
+				}
+			}
+		}
+		if override.Annotations != nil {
+			tools[i].Annotations = override.Annotations
+		}
+	}
+	return tools
+}
+
+// overrideProxiedTools applies the prefix's configured tool overrides
+func (s *Server) overrideProxiedTools(prefix string, tools []mcp.ToolSchema) []mcp.ToolSchema {
+	mcpCfg := s.currentState().GetMCPServerConfig(prefix)
+	if mcpCfg == nil {
+		return tools
+	}
+	return applyToolOverrides(mcpCfg.ToolOverrides, tools)
+}
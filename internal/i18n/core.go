@@ -9,8 +9,8 @@ import (
 	"sync"
 
 	"github.com/BurntSushi/toml"
-	"github.com/gin-gonic/gin"
 	"github.com/amoylab/unla/internal/common/cnst"
+	"github.com/gin-gonic/gin"
 	"github.com/nicksnyder/go-i18n/v2/i18n"
 	"golang.org/x/text/language"
 )
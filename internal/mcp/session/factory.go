@@ -23,9 +23,9 @@ func NewStore(logger *zap.Logger, cfg *config.SessionConfig) (Store, error) {
 	logger.Info("Initializing session store", zap.String("type", cfg.Type))
 	switch Type(cfg.Type) {
 	case TypeMemory:
-		return NewMemoryStore(logger), nil
+		return NewMemoryStoreWithConfig(logger, cfg), nil
 	case TypeRedis:
-		return NewRedisStore(logger, cfg.Redis)
+		return NewRedisStore(logger, cfg)
 	default:
 		return nil, fmt.Errorf("unsupported session store type: %s", cfg.Type)
 	}
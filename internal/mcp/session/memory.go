@@ -4,58 +4,150 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"time"
+
+	"github.com/amoylab/unla/internal/common/config"
 
 	"go.uber.org/zap"
 )
 
-// MemoryStore implements Store using in-memory storage
+const (
+	// defaultMemoryMaxSessions bounds the store when no limit is configured
+	defaultMemoryMaxSessions = 10000
+	// defaultMemorySessionTTL expires idle sessions when unconfigured
+	defaultMemorySessionTTL = 24 * time.Hour
+)
+
+// MemoryStore implements Store using in-memory storage with an LRU bound
+// and idle TTL so sessions never accumulate without limit
 type MemoryStore struct {
-	logger *zap.Logger
-	mu     sync.RWMutex
-	conns  map[string]Connection
+	logger      *zap.Logger
+	mu          sync.RWMutex
+	conns       map[string]Connection
+	lastActive  map[string]time.Time
+	maxSessions int
+	ttl         time.Duration
+	lastSweep   time.Time
+	queueSize   int
+	policy      string
 }
 
 var _ Store = (*MemoryStore)(nil)
 
-// NewMemoryStore creates a new in-memory session store
+// NewMemoryStore creates a new in-memory session store with default limits
 func NewMemoryStore(logger *zap.Logger) *MemoryStore {
+	return NewMemoryStoreWithConfig(logger, &config.SessionConfig{})
+}
+
+// NewMemoryStoreWithConfig creates a bounded in-memory session store
+func NewMemoryStoreWithConfig(logger *zap.Logger, sessionCfg *config.SessionConfig) *MemoryStore {
+	cfg := &sessionCfg.Memory
+	maxSessions := cfg.MaxSessions
+	if maxSessions <= 0 {
+		maxSessions = defaultMemoryMaxSessions
+	}
+	ttl := cfg.TTL
+	if ttl <= 0 {
+		ttl = defaultMemorySessionTTL
+	}
+	queueSize := sessionCfg.QueueSize
+	if queueSize <= 0 {
+		queueSize = 100
+	}
 	return &MemoryStore{
-		logger: logger.Named("session.store.memory"),
-		conns:  make(map[string]Connection),
+		logger:      logger.Named("session.store.memory"),
+		conns:       make(map[string]Connection),
+		lastActive:  make(map[string]time.Time),
+		maxSessions: maxSessions,
+		ttl:         ttl,
+		queueSize:   queueSize,
+		policy:      sessionCfg.BackpressurePolicy,
 	}
 }
 
+// sweepLocked drops expired sessions; callers must hold the write lock
+func (s *MemoryStore) sweepLocked(now time.Time) {
+	if now.Sub(s.lastSweep) < time.Minute {
+		return
+	}
+	s.lastSweep = now
+	for id, active := range s.lastActive {
+		if now.Sub(active) > s.ttl {
+			if conn, ok := s.conns[id]; ok {
+				_ = conn.Close(context.Background())
+			}
+			delete(s.conns, id)
+			delete(s.lastActive, id)
+		}
+	}
+}
+
+// evictOldestLocked removes the least recently used session; callers must
+// hold the write lock
+func (s *MemoryStore) evictOldestLocked() {
+	var oldestID string
+	var oldest time.Time
+	for id, active := range s.lastActive {
+		if oldestID == "" || active.Before(oldest) {
+			oldestID, oldest = id, active
+		}
+	}
+	if oldestID == "" {
+		return
+	}
+	s.logger.Warn("session limit reached, evicting least recently used session",
+		zap.String("id", oldestID))
+	if conn, ok := s.conns[oldestID]; ok {
+		_ = conn.Close(context.Background())
+	}
+	delete(s.conns, oldestID)
+	delete(s.lastActive, oldestID)
+}
+
 // Register implements Store.Register
 func (s *MemoryStore) Register(_ context.Context, meta *Meta) (Connection, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	now := time.Now()
+	s.sweepLocked(now)
+
 	// Check if connection already exists
 	if _, exists := s.conns[meta.ID]; exists {
 		return nil, fmt.Errorf("connection already exists: %s", meta.ID)
 	}
 
+	if len(s.conns) >= s.maxSessions {
+		s.evictOldestLocked()
+	}
+
 	// Create new connection
 	conn := &MemoryConnection{
-		meta:  meta,
-		queue: make(chan *Message, 100),
+		meta:   meta,
+		queue:  make(chan *Message, s.queueSize),
+		done:   make(chan struct{}),
+		policy: s.policy,
+		logger: s.logger,
 	}
 
 	// Store connection
 	s.conns[meta.ID] = conn
+	s.lastActive[meta.ID] = now
 
 	return conn, nil
 }
 
 // Get implements Store.Get
 func (s *MemoryStore) Get(_ context.Context, id string) (Connection, error) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
 	conn, ok := s.conns[id]
 	if !ok {
 		return nil, ErrSessionNotFound
 	}
+	// Refresh activity so live sessions aren't expired or evicted
+	s.lastActive[id] = time.Now()
 	return conn, nil
 }
 
@@ -78,6 +170,7 @@ func (s *MemoryStore) Unregister(_ context.Context, id string) error {
 
 	// Remove connection
 	delete(s.conns, id)
+	delete(s.lastActive, id)
 	return nil
 }
 
@@ -93,10 +186,20 @@ func (s *MemoryStore) List(_ context.Context) ([]Connection, error) {
 	return conns, nil
 }
 
+// Count returns the number of live sessions for observability
+func (s *MemoryStore) Count() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.conns)
+}
+
 // MemoryConnection implements Connection using in-memory storage
 type MemoryConnection struct {
-	meta  *Meta
-	queue chan *Message
+	meta   *Meta
+	queue  chan *Message
+	done   chan struct{}
+	policy string
+	logger *zap.Logger
 }
 
 var _ Connection = (*MemoryConnection)(nil)
@@ -109,19 +212,38 @@ func (c *MemoryConnection) EventQueue() <-chan *Message {
 // Send implements Connection.Send
 func (c *MemoryConnection) Send(_ context.Context, msg *Message) error {
 	select {
-	case c.queue <- msg:
-		return nil
+	case <-c.done:
+		// A tool call finishing after termination must not panic on a
+		// closed queue; the queue stays open, only delivery stops
+		return ErrSessionNotFound
 	default:
-		return fmt.Errorf("message queue is full")
 	}
+	full, err := enqueueWithPolicy(c.queue, msg, c.policy)
+	if full && c.logger != nil {
+		c.logger.Warn("slow consumer: session queue full",
+			zap.String("id", c.meta.ID),
+			zap.String("policy", c.policy))
+	}
+	return err
 }
 
-// Close implements Connection.Close
+// Close implements Connection.Close. The queue channel is never closed;
+// termination is signaled through done so concurrent senders and the
+// serving event loop shut down gracefully.
 func (c *MemoryConnection) Close(_ context.Context) error {
-	close(c.queue)
+	select {
+	case <-c.done:
+	default:
+		close(c.done)
+	}
 	return nil
 }
 
+// Done implements Connection.Done
+func (c *MemoryConnection) Done() <-chan struct{} {
+	return c.done
+}
+
 // Meta implements Connection.Meta
 func (c *MemoryConnection) Meta() *Meta {
 	return c.meta
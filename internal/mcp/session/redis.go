@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"os"
+	"strings"
 	"sync"
 	"time"
 
@@ -16,6 +18,14 @@ import (
 	"go.uber.org/zap"
 )
 
+// defaultSessionTTL is applied when no TTL is configured so session keys
+// and the ids set never accumulate without bound
+const defaultSessionTTL = 24 * time.Hour
+
+// streamGroup is the consumer group all gateway instances read session
+// streams through, so each event is delivered to exactly one consumer
+const streamGroup = "gateway"
+
 // RedisStore implements Store using Redis
 type RedisStore struct {
 	logger *zap.Logger
@@ -27,13 +37,20 @@ type RedisStore struct {
 	connections map[string]*RedisConnection
 	mu          sync.RWMutex
 	ttl         time.Duration // TTL for session data
+	// useStreams switches event delivery from pub/sub to Redis Streams
+	useStreams bool
+	// streamConsumer identifies this instance within the consumer group
+	streamConsumer string
+	queueSize      int
+	policy         string
 }
 
 var _ Store = (*RedisStore)(nil)
 
 // NewRedisStore creates a new Redis-based session store
 // func NewRedisStore(logger *zap.Logger, addr, username, password string, db int, topic string) (*RedisStore, error) {
-func NewRedisStore(logger *zap.Logger, cfg config.SessionRedisConfig) (*RedisStore, error) {
+func NewRedisStore(logger *zap.Logger, sessionCfg *config.SessionConfig) (*RedisStore, error) {
+	cfg := sessionCfg.Redis
 	addrs := utils.SplitByMultipleDelimiters(cfg.Addr, ";", ",")
 	redisOptions := &redis.UniversalOptions{
 		Addrs:    addrs,
@@ -61,22 +78,87 @@ func NewRedisStore(logger *zap.Logger, cfg config.SessionRedisConfig) (*RedisSto
 	} else {
 		prefix = prefix + ":"
 	}
+
+	ttl := cfg.TTL
+	if ttl <= 0 {
+		// Sessions must always expire, otherwise the ids set grows forever
+		ttl = defaultSessionTTL
+	}
+
+	queueSize := sessionCfg.QueueSize
+	if queueSize <= 0 {
+		queueSize = 100
+	}
+
 	store := &RedisStore{
 		logger:      logger.Named("session.store.redis"),
 		client:      client,
-		prefix:      cfg.Prefix,
+		prefix:      prefix,
 		topic:       cfg.Topic,
 		connections: make(map[string]*RedisConnection),
-		ttl:         cfg.TTL,
+		ttl:         ttl,
+		useStreams:  cfg.EventDelivery == "streams",
+		queueSize:   queueSize,
+		policy:      sessionCfg.BackpressurePolicy,
+	}
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = "gateway"
 	}
+	store.streamConsumer = fmt.Sprintf("%s-%d", host, os.Getpid())
 
 	// Subscribe to session updates
 	store.pubsub = client.Subscribe(context.Background(), cfg.Topic)
 	go store.handleUpdates()
+	go store.cleanupExpired()
 
 	return store, nil
 }
 
+// cleanupExpired periodically removes ids of expired sessions from the ids
+// set; the set's own TTL is refreshed on every activity, so stale members
+// would otherwise linger indefinitely
+func (s *RedisStore) cleanupExpired() {
+	interval := s.ttl / 4
+	if interval > 10*time.Minute {
+		interval = 10 * time.Minute
+	}
+	if interval < time.Minute {
+		interval = time.Minute
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		ids, err := s.client.SMembers(ctx, s.prefix+"ids").Result()
+		if err != nil {
+			s.logger.Warn("failed to list session ids for cleanup", zap.Error(err))
+			cancel()
+			continue
+		}
+
+		removed := 0
+		for _, id := range ids {
+			exists, err := s.client.Exists(ctx, s.prefix+id).Result()
+			if err != nil {
+				continue
+			}
+			if exists == 0 {
+				if err := s.client.SRem(ctx, s.prefix+"ids", id).Err(); err == nil {
+					removed++
+				}
+			}
+		}
+		cancel()
+
+		if removed > 0 {
+			s.logger.Info("removed expired session ids", zap.Int("count", removed))
+		}
+	}
+}
+
 // handleUpdates handles session update notifications
 func (s *RedisStore) handleUpdates() {
 	ch := s.pubsub.Channel()
@@ -111,15 +193,12 @@ func (s *RedisStore) handleUpdates() {
 			s.mu.RUnlock()
 
 			if exists {
-				select {
-				case conn.queue <- update.Message:
-					s.logger.Info("sent message to connection queue",
-						zap.String("id", update.Meta.ID),
-						zap.String("event", update.Message.Event))
-				default:
-					s.logger.Warn("connection queue is full, dropping message",
+				if full, err := enqueueWithPolicy(conn.queue, update.Message, s.policy); full || err != nil {
+					s.logger.Warn("slow consumer: session queue full",
 						zap.String("id", update.Meta.ID),
-						zap.String("event", update.Message.Event))
+						zap.String("event", update.Message.Event),
+						zap.String("policy", s.policy),
+						zap.Error(err))
 				}
 			} else {
 				s.logger.Warn("received event for non-existent connection",
@@ -130,6 +209,127 @@ func (s *RedisStore) handleUpdates() {
 	}
 }
 
+// sessionTopic returns the targeted channel for one session's events so
+// only the instance holding the live connection receives them
+func (s *RedisStore) sessionTopic(id string) string {
+	return s.topic + ":session:" + id
+}
+
+// sessionStream returns the stream key holding a session's events
+func (s *RedisStore) sessionStream(id string) string {
+	return s.prefix + "stream:" + id
+}
+
+// subscribeSessionStream delivers events from the session's Redis Stream
+// through a consumer group, giving at-least-once delivery: events appended
+// before the reader attached are replayed from the group's backlog, and
+// entries read but never acknowledged are re-delivered on reconnect
+func (s *RedisStore) subscribeSessionStream(conn *RedisConnection) {
+	stream := s.sessionStream(conn.meta.ID)
+
+	setupCtx, setupCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	err := s.client.XGroupCreateMkStream(setupCtx, stream, streamGroup, "0").Err()
+	setupCancel()
+	if err != nil && !strings.Contains(err.Error(), "BUSYGROUP") {
+		s.logger.Warn("failed to create stream consumer group",
+			zap.String("id", conn.meta.ID), zap.Error(err))
+	}
+
+	// Start from this consumer's pending entries, then follow new ones
+	readID := "0"
+
+	for {
+		select {
+		case <-conn.done:
+			return
+		default:
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		results, err := s.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+			Group:    streamGroup,
+			Consumer: s.streamConsumer,
+			Streams:  []string{stream, readID},
+			Block:    25 * time.Second,
+			Count:    100,
+		}).Result()
+		cancel()
+		if err != nil {
+			if errors.Is(err, redis.Nil) || errors.Is(err, context.DeadlineExceeded) {
+				readID = ">"
+				continue
+			}
+			s.logger.Warn("stream read failed", zap.String("id", conn.meta.ID), zap.Error(err))
+			select {
+			case <-conn.done:
+				return
+			case <-time.After(time.Second):
+			}
+			continue
+		}
+
+		for _, result := range results {
+			for _, entry := range result.Messages {
+				payload, _ := entry.Values["payload"].(string)
+				var update struct {
+					Message *Message `json:"message"`
+				}
+				if err := json.Unmarshal([]byte(payload), &update); err == nil && update.Message != nil {
+					if full, err := enqueueWithPolicy(conn.queue, update.Message, s.policy); full || err != nil {
+						s.logger.Warn("slow consumer: session queue full",
+							zap.String("id", conn.meta.ID),
+							zap.String("policy", s.policy),
+							zap.Error(err))
+					}
+				}
+				ackCtx, ackCancel := context.WithTimeout(context.Background(), 5*time.Second)
+				if err := s.client.XAck(ackCtx, stream, streamGroup, entry.ID).Err(); err != nil {
+					s.logger.Warn("failed to ack stream entry",
+						zap.String("id", conn.meta.ID), zap.Error(err))
+				}
+				ackCancel()
+			}
+		}
+		// The pending backlog has been drained; block for new entries
+		readID = ">"
+	}
+}
+
+// subscribeSession delivers targeted events for a locally-owned connection
+// until the connection is closed
+func (s *RedisStore) subscribeSession(conn *RedisConnection) {
+	if s.useStreams {
+		s.subscribeSessionStream(conn)
+		return
+	}
+	pubsub := s.client.Subscribe(context.Background(), s.sessionTopic(conn.meta.ID))
+	defer pubsub.Close()
+
+	ch := pubsub.Channel()
+	for {
+		select {
+		case <-conn.done:
+			return
+		case msg, ok := <-ch:
+			if !ok {
+				return
+			}
+			var update struct {
+				Message *Message `json:"message"`
+			}
+			if err := json.Unmarshal([]byte(msg.Payload), &update); err != nil || update.Message == nil {
+				continue
+			}
+			if full, err := enqueueWithPolicy(conn.queue, update.Message, s.policy); full || err != nil {
+				s.logger.Warn("slow consumer: session queue full",
+					zap.String("id", conn.meta.ID),
+					zap.String("policy", s.policy),
+					zap.Error(err))
+			}
+		}
+	}
+}
+
 // publishUpdate publishes a session update to the topic
 func (s *RedisStore) publishUpdate(ctx context.Context, action string, meta *Meta, msg *Message) error {
 	update := struct {
@@ -176,13 +376,16 @@ func (s *RedisStore) Register(ctx context.Context, meta *Meta) (Connection, erro
 	conn := &RedisConnection{
 		store: s,
 		meta:  meta,
-		queue: make(chan *Message, 100),
+		queue: make(chan *Message, s.queueSize),
+		done:  make(chan struct{}),
 	}
 
-	// Add to active connections
+	// Add to active connections and listen on the session's own channel so
+	// events are delivered only to the owning instance
 	s.mu.Lock()
 	s.connections[meta.ID] = conn
 	s.mu.Unlock()
+	go s.subscribeSession(conn)
 
 	// Publish update
 	if err := s.publishUpdate(ctx, "create", meta, nil); err != nil {
@@ -232,14 +435,21 @@ func (s *RedisStore) Get(ctx context.Context, id string) (Connection, error) {
 	return &RedisConnection{
 		store: s,
 		meta:  &meta,
-		queue: make(chan *Message, 100),
+		queue: make(chan *Message, s.queueSize),
 	}, nil
 }
 
 // Unregister implements Store.Unregister
 func (s *RedisStore) Unregister(ctx context.Context, id string) error {
-	// Remove from active connections
+	// Remove from active connections and stop its targeted subscription
 	s.mu.Lock()
+	if conn, ok := s.connections[id]; ok && conn.done != nil {
+		select {
+		case <-conn.done:
+		default:
+			close(conn.done)
+		}
+	}
 	delete(s.connections, id)
 	s.mu.Unlock()
 
@@ -297,7 +507,7 @@ func (s *RedisStore) List(ctx context.Context) ([]Connection, error) {
 		connections = append(connections, &RedisConnection{
 			store: s,
 			meta:  &meta,
-			queue: make(chan *Message, 100),
+			queue: make(chan *Message, s.queueSize),
 		})
 	}
 
@@ -319,6 +529,8 @@ type RedisConnection struct {
 	store *RedisStore
 	meta  *Meta
 	queue chan *Message
+	// done stops the targeted subscription for locally-owned connections
+	done chan struct{}
 }
 
 var _ Connection = (*RedisConnection)(nil)
@@ -343,14 +555,53 @@ func (c *RedisConnection) Send(ctx context.Context, msg *Message) error {
 			zap.Error(err))
 	}
 
-	return c.store.publishUpdate(ctx, "event", c.meta, msg)
+	update := struct {
+		Action  string   `json:"action"`
+		Meta    *Meta    `json:"meta"`
+		Message *Message `json:"message,omitempty"`
+	}{
+		Action:  "event",
+		Meta:    c.meta,
+		Message: msg,
+	}
+	data, err := json.Marshal(update)
+	if err != nil {
+		return fmt.Errorf("failed to marshal session event: %w", err)
+	}
+	// Deliver to the owning instance only
+	if c.store.useStreams {
+		if err := c.store.client.XAdd(ctx, &redis.XAddArgs{
+			Stream: c.store.sessionStream(c.meta.ID),
+			MaxLen: 1000,
+			Approx: true,
+			Values: map[string]any{"payload": string(data)},
+		}).Err(); err != nil {
+			return fmt.Errorf("failed to append session event to stream: %w", err)
+		}
+		// Bound the stream's lifetime to the session's
+		_ = c.store.client.Expire(ctx, c.store.sessionStream(c.meta.ID), c.store.ttl).Err()
+		return nil
+	}
+	return c.store.client.Publish(ctx, c.store.sessionTopic(c.meta.ID), data).Err()
 }
 
 // Close implements Connection.Close
 func (c *RedisConnection) Close(ctx context.Context) error {
+	if c.done != nil {
+		select {
+		case <-c.done:
+		default:
+			close(c.done)
+		}
+	}
 	return c.store.Unregister(ctx, c.meta.ID)
 }
 
+// Done implements Connection.Done
+func (c *RedisConnection) Done() <-chan struct{} {
+	return c.done
+}
+
 // Meta implements Connection.Meta
 func (c *RedisConnection) Meta() *Meta {
 	return c.meta
@@ -2,6 +2,7 @@ package session
 
 import (
 	"context"
+	"fmt"
 	"time"
 )
 
@@ -26,6 +27,12 @@ type Meta struct {
 	Type      string       `json:"type"`       // Connection type, e.g., "sse", "streamable"
 	Request   *RequestInfo `json:"request"`    // Request information
 	Extra     []byte       `json:"extra"`      // Optional serialized extra data
+
+	// RemoteAddr is the client address that opened the session
+	RemoteAddr string `json:"remote_addr,omitempty"`
+	// ClientName/ClientVersion come from the initialize request
+	ClientName    string `json:"client_name,omitempty"`
+	ClientVersion string `json:"client_version,omitempty"`
 }
 
 // Connection represents an active session connection capable of sending messages.
@@ -39,6 +46,10 @@ type Connection interface {
 	// Close gracefully terminates the session connection.
 	Close(ctx context.Context) error
 
+	// Done is closed when the connection has been terminated, so event
+	// loops can stop without the queue channel ever being closed.
+	Done() <-chan struct{}
+
 	// Meta returns metadata associated with the session.
 	Meta() *Meta
 }
@@ -57,3 +68,50 @@ type Store interface {
 	// List returns all currently active session connections.
 	List(ctx context.Context) ([]Connection, error)
 }
+
+// Backpressure policies for slow consumers
+const (
+	// PolicyDrop drops the new message when the queue is full
+	PolicyDrop = "drop"
+	// PolicyDropOldest evicts the oldest buffered message to make room
+	PolicyDropOldest = "drop_oldest"
+	// PolicyBlock waits up to blockTimeout for space, then fails
+	PolicyBlock = "block"
+
+	blockTimeout = 5 * time.Second
+)
+
+// enqueueWithPolicy applies the configured backpressure policy when pushing
+// a message onto a connection's buffer; it reports whether the queue was
+// full at any point so callers can log slow consumers
+func enqueueWithPolicy(queue chan *Message, msg *Message, policy string) (full bool, err error) {
+	select {
+	case queue <- msg:
+		return false, nil
+	default:
+	}
+
+	switch policy {
+	case PolicyBlock:
+		select {
+		case queue <- msg:
+			return true, nil
+		case <-time.After(blockTimeout):
+			return true, fmt.Errorf("message queue is full")
+		}
+	case PolicyDropOldest:
+		for {
+			select {
+			case queue <- msg:
+				return true, nil
+			default:
+				select {
+				case <-queue:
+				default:
+				}
+			}
+		}
+	default:
+		return true, fmt.Errorf("message queue is full")
+	}
+}
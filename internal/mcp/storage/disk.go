@@ -81,6 +81,7 @@ func (s *DiskStore) Get(_ context.Context, tenant, name string, includeDeleted .
 	}
 
 	var server config.MCPConfig
+	data = config.ResolveEnv(data)
 	if err := yaml.Unmarshal(data, &server); err != nil {
 		return nil, err
 	}
@@ -130,6 +131,7 @@ func (s *DiskStore) List(_ context.Context, _ ...bool) ([]*config.MCPConfig, err
 			}
 
 			var server config.MCPConfig
+			data = config.ResolveEnv(data)
 			if err := yaml.Unmarshal(data, &server); err != nil {
 				s.logger.Error("failed to unmarshal server",
 					zap.String("tenant", tenantEntry.Name()),
@@ -18,6 +18,10 @@ func NewStore(logger *zap.Logger, cfg *config.StorageConfig) (Store, error) {
 		return NewDBStore(logger, cfg)
 	case "api":
 		return NewAPIStore(logger, cfg.API.Url, cfg.API.ConfigJSONPath, cfg.API.Timeout)
+	case "git":
+		return NewGitStore(logger, cfg)
+	case "kubernetes":
+		return NewK8sStore(logger, cfg)
 	default:
 		return nil, fmt.Errorf("unsupported storage type: %s", cfg.Type)
 	}
@@ -0,0 +1,165 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/amoylab/unla/internal/common/config"
+
+	"go.uber.org/zap"
+)
+
+// ErrGitStoreReadOnly is returned for mutating operations on a git-backed
+// store; changes are made by pushing to the tracked repository instead
+var ErrGitStoreReadOnly = errors.New("git storage is read-only; push changes to the tracked repository")
+
+// GitStore implements Store by tracking MCP configs in a git repository.
+// The checkout is refreshed with periodic pulls so configuration changes
+// flow through the normal git review process.
+type GitStore struct {
+	logger *zap.Logger
+	cfg    config.GitStorageConfig
+	// repoDir is the local checkout of the tracked repository
+	repoDir string
+	// inner reads the YAML configs from the checkout
+	inner *DiskStore
+}
+
+var _ Store = (*GitStore)(nil)
+
+// NewGitStore clones the configured repository and serves configs from it
+func NewGitStore(logger *zap.Logger, cfg *config.StorageConfig) (*GitStore, error) {
+	logger = logger.Named("mcp.store.git")
+
+	if cfg.Git.URL == "" {
+		return nil, fmt.Errorf("git storage requires a repository url")
+	}
+
+	repoDir := cfg.Git.LocalDir
+	if repoDir == "" {
+		repoDir = filepath.Join(os.TempDir(), "unla-git-storage")
+	}
+
+	s := &GitStore{
+		logger:  logger,
+		cfg:     cfg.Git,
+		repoDir: repoDir,
+	}
+
+	if err := s.cloneOrPull(context.Background()); err != nil {
+		return nil, err
+	}
+
+	inner, err := NewDiskStore(logger, &config.StorageConfig{
+		Disk: config.DiskStorageConfig{Path: filepath.Join(repoDir, cfg.Git.Path)},
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.inner = inner
+
+	if cfg.Git.PullInterval > 0 {
+		go s.pullLoop(cfg.Git.PullInterval)
+	}
+
+	return s, nil
+}
+
+// cloneOrPull clones the repository on first use and pulls afterwards
+func (s *GitStore) cloneOrPull(ctx context.Context) error {
+	if _, err := os.Stat(filepath.Join(s.repoDir, ".git")); os.IsNotExist(err) {
+		args := []string{"clone", "--depth", "1"}
+		if s.cfg.Branch != "" {
+			args = append(args, "--branch", s.cfg.Branch)
+		}
+		args = append(args, s.cfg.URL, s.repoDir)
+		if out, err := exec.CommandContext(ctx, "git", args...).CombinedOutput(); err != nil {
+			return fmt.Errorf("failed to clone config repository: %w: %s", err, out)
+		}
+		s.logger.Info("cloned config repository",
+			zap.String("url", s.cfg.URL),
+			zap.String("branch", s.cfg.Branch),
+			zap.String("dir", s.repoDir))
+		return nil
+	}
+
+	cmd := exec.CommandContext(ctx, "git", "-C", s.repoDir, "pull", "--ff-only")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to pull config repository: %w: %s", err, out)
+	}
+	return nil
+}
+
+// pullLoop refreshes the checkout on the configured interval
+func (s *GitStore) pullLoop(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		if err := s.cloneOrPull(ctx); err != nil {
+			s.logger.Warn("periodic pull failed", zap.Error(err))
+		}
+		cancel()
+	}
+}
+
+// Sync refreshes the checkout immediately, e.g. from a webhook handler
+func (s *GitStore) Sync(ctx context.Context) error {
+	return s.cloneOrPull(ctx)
+}
+
+// Get implements Store.Get
+func (s *GitStore) Get(ctx context.Context, tenant, name string, includeDeleted ...bool) (*config.MCPConfig, error) {
+	return s.inner.Get(ctx, tenant, name, includeDeleted...)
+}
+
+// List implements Store.List
+func (s *GitStore) List(ctx context.Context, includeDeleted ...bool) ([]*config.MCPConfig, error) {
+	return s.inner.List(ctx, includeDeleted...)
+}
+
+// ListUpdated implements Store.ListUpdated
+func (s *GitStore) ListUpdated(ctx context.Context, since time.Time) ([]*config.MCPConfig, error) {
+	return s.inner.ListUpdated(ctx, since)
+}
+
+// Create implements Store.Create
+func (s *GitStore) Create(_ context.Context, _ *config.MCPConfig) error {
+	return ErrGitStoreReadOnly
+}
+
+// Update implements Store.Update
+func (s *GitStore) Update(_ context.Context, _ *config.MCPConfig) error {
+	return ErrGitStoreReadOnly
+}
+
+// Delete implements Store.Delete
+func (s *GitStore) Delete(_ context.Context, _, _ string) error {
+	return ErrGitStoreReadOnly
+}
+
+// GetVersion implements Store.GetVersion; history lives in git itself
+func (s *GitStore) GetVersion(_ context.Context, _, _ string, _ int) (*config.MCPConfigVersion, error) {
+	return nil, ErrGitStoreReadOnly
+}
+
+// ListVersions implements Store.ListVersions; history lives in git itself
+func (s *GitStore) ListVersions(_ context.Context, _, _ string) ([]*config.MCPConfigVersion, error) {
+	return nil, ErrGitStoreReadOnly
+}
+
+// DeleteVersion implements Store.DeleteVersion; history lives in git itself
+func (s *GitStore) DeleteVersion(_ context.Context, _, _ string, _ int) error {
+	return ErrGitStoreReadOnly
+}
+
+// SetActiveVersion implements Store.SetActiveVersion; history lives in git itself
+func (s *GitStore) SetActiveVersion(_ context.Context, _, _ string, _ int) error {
+	return ErrGitStoreReadOnly
+}
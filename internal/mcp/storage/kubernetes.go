@@ -0,0 +1,276 @@
+package storage
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/amoylab/unla/internal/common/config"
+
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	inClusterTokenFile = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+	inClusterCAFile    = "/var/run/secrets/kubernetes.io/serviceaccount/ca.crt"
+)
+
+// ErrK8sStoreReadOnly is returned for mutating operations on the Kubernetes
+// store; configs are managed by editing the ConfigMaps instead
+var ErrK8sStoreReadOnly = errors.New("kubernetes storage is read-only; edit the ConfigMaps instead")
+
+// K8sStore implements Store by reading MCP configs from Kubernetes
+// ConfigMaps selected by a label selector. Each data entry of a matching
+// ConfigMap is parsed as one MCPConfig YAML document.
+type K8sStore struct {
+	logger    *zap.Logger
+	cfg       config.KubernetesStorageConfig
+	client    *http.Client
+	apiServer string
+	token     string
+}
+
+var _ Store = (*K8sStore)(nil)
+
+// NewK8sStore creates a store backed by the Kubernetes API
+func NewK8sStore(logger *zap.Logger, cfg *config.StorageConfig) (*K8sStore, error) {
+	logger = logger.Named("mcp.store.k8s")
+
+	k8sCfg := cfg.Kubernetes
+	if k8sCfg.Namespace == "" {
+		k8sCfg.Namespace = "default"
+	}
+
+	apiServer := k8sCfg.APIServer
+	if apiServer == "" {
+		host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+		if host == "" {
+			return nil, fmt.Errorf("kubernetes storage requires api_server or an in-cluster environment")
+		}
+		apiServer = "https://" + host + ":" + port
+	}
+
+	tokenFile := k8sCfg.TokenFile
+	if tokenFile == "" {
+		tokenFile = inClusterTokenFile
+	}
+	token := ""
+	if data, err := os.ReadFile(tokenFile); err == nil {
+		token = string(data)
+	}
+
+	caFile := k8sCfg.CAFile
+	if caFile == "" {
+		caFile = inClusterCAFile
+	}
+	transport := &http.Transport{}
+	if caData, err := os.ReadFile(caFile); err == nil {
+		pool := x509.NewCertPool()
+		if pool.AppendCertsFromPEM(caData) {
+			transport.TLSClientConfig = &tls.Config{RootCAs: pool}
+		}
+	}
+
+	return &K8sStore{
+		logger:    logger,
+		cfg:       k8sCfg,
+		client:    &http.Client{Transport: transport, Timeout: 30 * time.Second},
+		apiServer: apiServer,
+		token:     token,
+	}, nil
+}
+
+// configMapList mirrors the fields of a v1.ConfigMapList we consume
+type configMapList struct {
+	Items []struct {
+		Metadata struct {
+			Name              string `json:"name"`
+			CreationTimestamp string `json:"creationTimestamp"`
+		} `json:"metadata"`
+		Data map[string]string `json:"data"`
+	} `json:"items"`
+}
+
+// fetchConfigMaps lists the matching ConfigMaps from the API server
+func (s *K8sStore) fetchConfigMaps(ctx context.Context) (*configMapList, error) {
+	endpoint := fmt.Sprintf("%s/api/v1/namespaces/%s/configmaps", s.apiServer, s.cfg.Namespace)
+	if s.cfg.LabelSelector != "" {
+		endpoint += "?labelSelector=" + url.QueryEscape(s.cfg.LabelSelector)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	if s.token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ConfigMaps: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to list ConfigMaps: status %d", resp.StatusCode)
+	}
+
+	var list configMapList
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		return nil, fmt.Errorf("failed to decode ConfigMap list: %w", err)
+	}
+	return &list, nil
+}
+
+// List implements Store.List
+func (s *K8sStore) List(ctx context.Context, _ ...bool) ([]*config.MCPConfig, error) {
+	list, err := s.fetchConfigMaps(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var configs []*config.MCPConfig
+	for _, item := range list.Items {
+		for key, value := range item.Data {
+			var cfg config.MCPConfig
+			data := config.ResolveEnv([]byte(value))
+			if err := yaml.Unmarshal(data, &cfg); err != nil {
+				s.logger.Warn("skipping invalid MCP config in ConfigMap",
+					zap.String("configmap", item.Metadata.Name),
+					zap.String("key", key),
+					zap.Error(err))
+				continue
+			}
+			configs = append(configs, &cfg)
+		}
+	}
+	return configs, nil
+}
+
+// ListUpdated implements Store.ListUpdated. ConfigMaps carry no reliable
+// per-entry update timestamp, so the full set is returned and merged.
+func (s *K8sStore) ListUpdated(ctx context.Context, _ time.Time) ([]*config.MCPConfig, error) {
+	return s.List(ctx)
+}
+
+// Get implements Store.Get
+func (s *K8sStore) Get(ctx context.Context, tenant, name string, includeDeleted ...bool) (*config.MCPConfig, error) {
+	configs, err := s.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, cfg := range configs {
+		if cfg.Tenant == tenant && cfg.Name == name {
+			return cfg, nil
+		}
+	}
+	return nil, fmt.Errorf("mcp config not found: %s/%s", tenant, name)
+}
+
+// Create implements Store.Create
+func (s *K8sStore) Create(_ context.Context, _ *config.MCPConfig) error {
+	return ErrK8sStoreReadOnly
+}
+
+// Update implements Store.Update
+func (s *K8sStore) Update(_ context.Context, _ *config.MCPConfig) error {
+	return ErrK8sStoreReadOnly
+}
+
+// Delete implements Store.Delete
+func (s *K8sStore) Delete(_ context.Context, _, _ string) error {
+	return ErrK8sStoreReadOnly
+}
+
+// GetVersion implements Store.GetVersion
+func (s *K8sStore) GetVersion(_ context.Context, _, _ string, _ int) (*config.MCPConfigVersion, error) {
+	return nil, ErrK8sStoreReadOnly
+}
+
+// ListVersions implements Store.ListVersions
+func (s *K8sStore) ListVersions(_ context.Context, _, _ string) ([]*config.MCPConfigVersion, error) {
+	return nil, ErrK8sStoreReadOnly
+}
+
+// DeleteVersion implements Store.DeleteVersion
+func (s *K8sStore) DeleteVersion(_ context.Context, _, _ string, _ int) error {
+	return ErrK8sStoreReadOnly
+}
+
+// SetActiveVersion implements Store.SetActiveVersion
+func (s *K8sStore) SetActiveVersion(_ context.Context, _, _ string, _ int) error {
+	return ErrK8sStoreReadOnly
+}
+
+// Watch streams ConfigMap change events from the API server, invoking
+// onChange for every add/update/delete until the context is canceled.
+// The watch is re-established with backoff after connection loss.
+func (s *K8sStore) Watch(ctx context.Context, onChange func()) {
+	backoff := time.Second
+	for {
+		if err := s.watchOnce(ctx, onChange); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			s.logger.Warn("ConfigMap watch disconnected, retrying",
+				zap.Duration("backoff", backoff),
+				zap.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		if backoff < time.Minute {
+			backoff *= 2
+		}
+	}
+}
+
+func (s *K8sStore) watchOnce(ctx context.Context, onChange func()) error {
+	endpoint := fmt.Sprintf("%s/api/v1/namespaces/%s/configmaps?watch=true", s.apiServer, s.cfg.Namespace)
+	if s.cfg.LabelSelector != "" {
+		endpoint += "&labelSelector=" + url.QueryEscape(s.cfg.LabelSelector)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	if s.token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	}
+
+	// Watches are long-lived streams, so bypass the list client's timeout
+	watchClient := &http.Client{Transport: s.client.Transport}
+	resp, err := watchClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("watch request failed: status %d", resp.StatusCode)
+	}
+
+	decoder := json.NewDecoder(resp.Body)
+	for {
+		var event struct {
+			Type string `json:"type"`
+		}
+		if err := decoder.Decode(&event); err != nil {
+			return err
+		}
+		switch event.Type {
+		case "ADDED", "MODIFIED", "DELETED":
+			onChange()
+		}
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/amoylab/unla/internal/common/config"
+	"github.com/amoylab/unla/internal/mcp/storage"
 )
 
 // Type represents the type of notifier
@@ -19,6 +20,10 @@ const (
 	TypeAPI Type = "api"
 	// TypeRedis represents Redis-based notifier
 	TypeRedis Type = "redis"
+	// TypeKubernetes represents Kubernetes watch-based notifier
+	TypeKubernetes Type = "kubernetes"
+	// TypeFsnotify represents the file watcher notifier
+	TypeFsnotify Type = "fsnotify"
 	// TypeComposite represents composite notifier
 	TypeComposite Type = "composite"
 )
@@ -37,7 +42,33 @@ func NewNotifier(ctx context.Context, logger *zap.Logger, cfg *config.NotifierCo
 		return NewAPINotifier(logger, cfg.API.Port, role, cfg.API.TargetURL), nil
 	case TypeRedis:
 		return NewRedisNotifier(logger, cfg.Redis.ClusterType, cfg.Redis.Addr, cfg.Redis.MasterName, cfg.Redis.Username, cfg.Redis.Password, cfg.Redis.DB, cfg.Redis.Topic, role)
+	case TypeFsnotify:
+		return NewFsnotifyNotifier(ctx, logger, cfg.Fsnotify.Path, role)
+	case TypeKubernetes:
+		store, err := storage.NewK8sStore(logger, &config.StorageConfig{Kubernetes: cfg.Kubernetes})
+		if err != nil {
+			return nil, err
+		}
+		return NewK8sNotifier(ctx, logger, store, role), nil
 	case TypeComposite:
+		// An explicit member list overrides the legacy default set
+		if len(cfg.Composite.Types) > 0 {
+			notifiers := make([]Notifier, 0, len(cfg.Composite.Types))
+			for _, memberType := range cfg.Composite.Types {
+				if Type(memberType) == TypeComposite {
+					return nil, fmt.Errorf("composite notifier cannot contain itself")
+				}
+				memberCfg := *cfg
+				memberCfg.Type = memberType
+				member, err := NewNotifier(ctx, logger, &memberCfg)
+				if err != nil {
+					return nil, fmt.Errorf("failed to create composite member %q: %w", memberType, err)
+				}
+				notifiers = append(notifiers, member)
+			}
+			return NewCompositeNotifier(ctx, logger, notifiers...), nil
+		}
+
 		notifiers := make([]Notifier, 0)
 		// Add signal notifier
 		signalNotifier := NewSignalNotifier(ctx, logger, cfg.Signal.PID, role)
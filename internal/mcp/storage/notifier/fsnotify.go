@@ -0,0 +1,160 @@
+package notifier
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/amoylab/unla/internal/common/cnst"
+	"github.com/amoylab/unla/internal/common/config"
+
+	"github.com/fsnotify/fsnotify"
+	"go.uber.org/zap"
+)
+
+// fsnotifyDebounce coalesces bursts of file events (editors, ConfigMap
+// symlink swaps) into one reload
+const fsnotifyDebounce = 500 * time.Millisecond
+
+// FsnotifyNotifier implements Notifier by watching the config directory
+type FsnotifyNotifier struct {
+	logger   *zap.Logger
+	path     string
+	mu       sync.RWMutex
+	watchers map[chan *config.MCPConfig]struct{}
+	role     config.NotifierRole
+}
+
+// NewFsnotifyNotifier creates a notifier that watches a directory tree of
+// config files and triggers a full reload on changes
+func NewFsnotifyNotifier(ctx context.Context, logger *zap.Logger, path string, role config.NotifierRole) (*FsnotifyNotifier, error) {
+	n := &FsnotifyNotifier{
+		logger:   logger.Named("notifier.fsnotify"),
+		path:     path,
+		watchers: make(map[chan *config.MCPConfig]struct{}),
+		role:     role,
+	}
+
+	if n.CanReceive() {
+		if err := n.watch(ctx); err != nil {
+			return nil, err
+		}
+	}
+	return n, nil
+}
+
+// watch starts the directory watcher with debounced notifications
+func (n *FsnotifyNotifier) watch(ctx context.Context) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+
+	// Watch the base directory plus existing tenant subdirectories
+	addDirs := func() {
+		_ = watcher.Add(n.path)
+		entries, err := os.ReadDir(n.path)
+		if err != nil {
+			return
+		}
+		for _, entry := range entries {
+			if entry.IsDir() {
+				_ = watcher.Add(filepath.Join(n.path, entry.Name()))
+			}
+		}
+	}
+	addDirs()
+
+	go func() {
+		defer watcher.Close()
+		var timer *time.Timer
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				n.logger.Debug("config file event",
+					zap.String("file", event.Name),
+					zap.String("op", event.Op.String()))
+				// New tenant directories must be watched as well
+				if event.Op.Has(fsnotify.Create) {
+					if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+						_ = watcher.Add(event.Name)
+					}
+				}
+				// Debounce: one reload per burst of events
+				if timer != nil {
+					timer.Stop()
+				}
+				timer = time.AfterFunc(fsnotifyDebounce, func() {
+					addDirs()
+					n.notifyWatchers()
+				})
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				n.logger.Warn("config watcher error", zap.Error(err))
+			}
+		}
+	}()
+	return nil
+}
+
+// notifyWatchers signals a full reload to all registered watchers
+func (n *FsnotifyNotifier) notifyWatchers() {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	n.logger.Info("config directory changed, triggering reload")
+	for watcher := range n.watchers {
+		select {
+		case watcher <- nil: // nil means full reload
+		default:
+			n.logger.Warn("watcher channel is full, skipping notification")
+		}
+	}
+}
+
+// Watch implements Notifier.Watch
+func (n *FsnotifyNotifier) Watch(ctx context.Context) (<-chan *config.MCPConfig, error) {
+	if !n.CanReceive() {
+		return nil, cnst.ErrNotReceiver
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	ch := make(chan *config.MCPConfig, 10)
+	n.watchers[ch] = struct{}{}
+
+	go func() {
+		<-ctx.Done()
+		n.mu.Lock()
+		defer n.mu.Unlock()
+		delete(n.watchers, ch)
+		close(ch)
+	}()
+
+	return ch, nil
+}
+
+// NotifyUpdate implements Notifier.NotifyUpdate
+func (n *FsnotifyNotifier) NotifyUpdate(_ context.Context, _ *config.MCPConfig) error {
+	return cnst.ErrNotSender
+}
+
+// CanReceive returns true if the notifier can receive updates
+func (n *FsnotifyNotifier) CanReceive() bool {
+	return n.role == config.RoleReceiver || n.role == config.RoleBoth
+}
+
+// CanSend returns true if the notifier can send updates
+func (n *FsnotifyNotifier) CanSend() bool {
+	return false
+}
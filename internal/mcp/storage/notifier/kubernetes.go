@@ -0,0 +1,91 @@
+package notifier
+
+import (
+	"context"
+	"sync"
+
+	"github.com/amoylab/unla/internal/common/cnst"
+	"github.com/amoylab/unla/internal/common/config"
+	"github.com/amoylab/unla/internal/mcp/storage"
+
+	"go.uber.org/zap"
+)
+
+// K8sNotifier implements Notifier by watching the ConfigMaps that back the
+// Kubernetes storage; every change triggers a full reload
+type K8sNotifier struct {
+	logger   *zap.Logger
+	store    *storage.K8sStore
+	mu       sync.RWMutex
+	watchers map[chan *config.MCPConfig]struct{}
+	role     config.NotifierRole
+}
+
+// NewK8sNotifier creates a new Kubernetes watch-based notifier
+func NewK8sNotifier(ctx context.Context, logger *zap.Logger, store *storage.K8sStore, role config.NotifierRole) *K8sNotifier {
+	n := &K8sNotifier{
+		logger:   logger.Named("notifier.k8s"),
+		store:    store,
+		watchers: make(map[chan *config.MCPConfig]struct{}),
+		role:     role,
+	}
+
+	if n.CanReceive() {
+		go store.Watch(ctx, n.notifyWatchers)
+	}
+
+	return n
+}
+
+// notifyWatchers signals a full reload to all registered watchers
+func (n *K8sNotifier) notifyWatchers() {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	n.logger.Info("ConfigMap change detected, triggering reload")
+	for watcher := range n.watchers {
+		select {
+		case watcher <- nil: // nil means full reload
+		default:
+			n.logger.Warn("watcher channel is full, skipping notification")
+		}
+	}
+}
+
+// Watch implements Notifier.Watch
+func (n *K8sNotifier) Watch(ctx context.Context) (<-chan *config.MCPConfig, error) {
+	if !n.CanReceive() {
+		return nil, cnst.ErrNotReceiver
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	ch := make(chan *config.MCPConfig, 10)
+	n.watchers[ch] = struct{}{}
+
+	go func() {
+		<-ctx.Done()
+		n.mu.Lock()
+		defer n.mu.Unlock()
+		delete(n.watchers, ch)
+		close(ch)
+	}()
+
+	return ch, nil
+}
+
+// NotifyUpdate implements Notifier.NotifyUpdate
+func (n *K8sNotifier) NotifyUpdate(_ context.Context, _ *config.MCPConfig) error {
+	return cnst.ErrNotSender
+}
+
+// CanReceive returns true if the notifier can receive updates
+func (n *K8sNotifier) CanReceive() bool {
+	return n.role == config.RoleReceiver || n.role == config.RoleBoth
+}
+
+// CanSend returns true if the notifier can send updates
+func (n *K8sNotifier) CanSend() bool {
+	return false
+}
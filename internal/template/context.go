@@ -10,6 +10,9 @@ type (
 		Request  RequestWrapper      `json:"request"`
 		Response ResponseWrapper     `json:"response"`
 		Env      func(string) string `json:"-"` // Function to get environment variables
+		// Restricted disables the env and secret functions for configs
+		// from less-trusted tenants
+		Restricted bool `json:"-"`
 	}
 	RequestWrapper struct {
 		Headers map[string]string `json:"headers"`
@@ -21,6 +24,10 @@ type (
 	ResponseWrapper struct {
 		Data any `json:"data"`
 		Body any `json:"body"`
+		// StatusCode is the upstream HTTP status
+		StatusCode int `json:"statusCode"`
+		// Headers are the upstream response headers (first value each)
+		Headers map[string]string `json:"headers"`
 	}
 )
 
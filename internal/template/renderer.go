@@ -8,10 +8,47 @@ import (
 	"fmt"
 	"net/http"
 	"text/template"
+	"time"
 
 	"github.com/amoylab/unla/internal/mcp/session"
 )
 
+// Template execution limits; configured once at startup
+var (
+	// maxTemplateOutputBytes aborts rendering past this many bytes
+	maxTemplateOutputBytes int64 = 4 << 20
+	// maxTemplateDuration aborts rendering past this wall-clock budget
+	maxTemplateDuration = 5 * time.Second
+)
+
+// SetExecutionLimits configures the template output and time budgets;
+// non-positive values keep the current defaults
+func SetExecutionLimits(maxBytes int64, maxDuration time.Duration) {
+	if maxBytes > 0 {
+		maxTemplateOutputBytes = maxBytes
+	}
+	if maxDuration > 0 {
+		maxTemplateDuration = maxDuration
+	}
+}
+
+// limitedBuffer aborts template execution when the output or time budget
+// is exhausted; Execute stops at the first write error
+type limitedBuffer struct {
+	buf      bytes.Buffer
+	deadline time.Time
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if time.Now().After(b.deadline) {
+		return 0, fmt.Errorf("template execution exceeded the %s time budget", maxTemplateDuration)
+	}
+	if int64(b.buf.Len()+len(p)) > maxTemplateOutputBytes {
+		return 0, fmt.Errorf("template output exceeds the %d byte limit", maxTemplateOutputBytes)
+	}
+	return b.buf.Write(p)
+}
+
 // Renderer is responsible for rendering templates
 type Renderer struct {
 	templates map[string]*template.Template
@@ -24,36 +61,57 @@ func NewRenderer() *Renderer {
 	}
 }
 
-// generateTemplateName generates a unique name for a template based on its content
-func generateTemplateName(tmpl string) string {
+// generateTemplateName generates a unique name for a template based on its
+// content and render mode. The function map is baked in at parse time, so
+// restricted and unrestricted renders of the same content must not share a
+// cache entry.
+func generateTemplateName(tmpl string, restricted bool) string {
 	hash := sha256.Sum256([]byte(tmpl))
+	if restricted {
+		return fmt.Sprintf("tmpl_r_%s", hex.EncodeToString(hash[:8]))
+	}
 	return fmt.Sprintf("tmpl_%s", hex.EncodeToString(hash[:8]))
 }
 
 // Render renders a template with the given context
 func (r *Renderer) Render(tmpl string, ctx *Context) (string, error) {
-	name := generateTemplateName(tmpl)
+	name := generateTemplateName(tmpl, ctx.Restricted)
 	t, ok := r.templates[name]
 	if !ok {
 		var err error
-		t, err = template.New(name).Funcs(template.FuncMap{
-			"env":      ctx.Env,
-			"add":      func(a, b int) int { return a + b },
-			"fromJSON": fromJSON,
-			"toJSON":   toJSON,
-		}).Parse(tmpl)
+		t, err = template.New(name).Funcs(templateFuncs(ctx)).Parse(tmpl)
 		if err != nil {
 			return "", err
 		}
 		r.templates[name] = t
 	}
 
-	var buf bytes.Buffer
-	if err := t.Execute(&buf, ctx); err != nil {
+	buf := &limitedBuffer{deadline: time.Now().Add(maxTemplateDuration)}
+	if err := t.Execute(buf, ctx); err != nil {
 		return "", err
 	}
 
-	return buf.String(), nil
+	return buf.buf.String(), nil
+}
+
+// templateFuncs builds the function map; restricted contexts (untrusted
+// tenants) lose access to the environment and secret store
+func templateFuncs(ctx *Context) template.FuncMap {
+	funcs := template.FuncMap{
+		"add":      func(a, b int) int { return a + b },
+		"fromJSON": fromJSON,
+		"toJSON":   toJSON,
+		"env":      ctx.Env,
+		"secret":   resolveSecret,
+	}
+	if ctx.Restricted {
+		denied := func(string) (string, error) {
+			return "", fmt.Errorf("function disabled for restricted configs")
+		}
+		funcs["env"] = denied
+		funcs["secret"] = denied
+	}
+	return funcs
 }
 
 // RenderTemplate renders a template with the given context
@@ -62,6 +120,20 @@ func RenderTemplate(tmpl string, ctx *Context) (string, error) {
 	return renderer.Render(tmpl, ctx)
 }
 
+// ValidateTemplate parses a template without executing it, returning any
+// syntax error; used by config linting
+func ValidateTemplate(tmpl string) error {
+	ctx := NewContext()
+	_, err := template.New("validate").Funcs(template.FuncMap{
+		"env":      ctx.Env,
+		"add":      func(a, b int) int { return a + b },
+		"fromJSON": fromJSON,
+		"toJSON":   toJSON,
+		"secret":   resolveSecret,
+	}).Parse(tmpl)
+	return err
+}
+
 func AssembleTemplateContext(req *RequestWrapper, args map[string]any, serverCfg map[string]string) (*Context, error) {
 	tmplCtx := NewContext()
 	tmplCtx.Args = preprocessArgs(args)
@@ -0,0 +1,118 @@
+package template
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// secretCacheTTL bounds how long resolved secrets are reused so rotations
+// propagate without a restart
+const secretCacheTTL = 5 * time.Minute
+
+type cachedSecret struct {
+	value     string
+	fetchedAt time.Time
+}
+
+var (
+	secretMu    sync.Mutex
+	secretCache = make(map[string]cachedSecret)
+
+	vaultHTTPClient = &http.Client{Timeout: 10 * time.Second}
+)
+
+// resolveSecret resolves a secret reference of the form
+// "env:NAME", "file:/path" or "vault:secret/data/foo#key" so API keys never
+// need to live in stored configs
+func resolveSecret(ref string) (string, error) {
+	secretMu.Lock()
+	if cached, ok := secretCache[ref]; ok && time.Since(cached.fetchedAt) < secretCacheTTL {
+		secretMu.Unlock()
+		return cached.value, nil
+	}
+	secretMu.Unlock()
+
+	scheme, rest, found := strings.Cut(ref, ":")
+	if !found {
+		return "", fmt.Errorf("invalid secret reference %q, want scheme:location", ref)
+	}
+
+	var (
+		value string
+		err   error
+	)
+	switch scheme {
+	case "env":
+		value = os.Getenv(rest)
+		if value == "" {
+			err = fmt.Errorf("environment variable %s is not set", rest)
+		}
+	case "file":
+		var data []byte
+		data, err = os.ReadFile(rest)
+		value = strings.TrimSpace(string(data))
+	case "vault":
+		value, err = resolveVaultSecret(rest)
+	default:
+		err = fmt.Errorf("unsupported secret scheme %q", scheme)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	secretMu.Lock()
+	secretCache[ref] = cachedSecret{value: value, fetchedAt: time.Now()}
+	secretMu.Unlock()
+	return value, nil
+}
+
+// resolveVaultSecret reads "path#key" from the KV engine using the standard
+// VAULT_ADDR and VAULT_TOKEN environment variables
+func resolveVaultSecret(location string) (string, error) {
+	path, key, found := strings.Cut(location, "#")
+	if !found {
+		return "", fmt.Errorf("vault reference needs the form path#key")
+	}
+
+	addr := os.Getenv("VAULT_ADDR")
+	token := os.Getenv("VAULT_TOKEN")
+	if addr == "" || token == "" {
+		return "", fmt.Errorf("VAULT_ADDR and VAULT_TOKEN must be set for vault secrets")
+	}
+
+	req, err := http.NewRequest(http.MethodGet, strings.TrimSuffix(addr, "/")+"/v1/"+path, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("X-Vault-Token", token)
+
+	resp, err := vaultHTTPClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("vault request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("vault request failed: status %d", resp.StatusCode)
+	}
+
+	var doc struct {
+		Data struct {
+			Data map[string]any `json:"data"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&doc); err != nil {
+		return "", fmt.Errorf("failed to decode vault response: %w", err)
+	}
+
+	// KV v2 nests under data.data; KV v1 would be directly under data
+	values := doc.Data.Data
+	if value, ok := values[key]; ok {
+		return fmt.Sprint(value), nil
+	}
+	return "", fmt.Errorf("key %q not found at vault path %s", key, path)
+}
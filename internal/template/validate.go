@@ -0,0 +1,43 @@
+package template
+
+import (
+	"fmt"
+
+	"github.com/amoylab/unla/internal/common/config"
+)
+
+// ValidateToolTemplates compiles every templated tool field across the
+// given configs, returning a combined report of all syntax errors so a
+// reload can be rejected before any state is swapped in
+func ValidateToolTemplates(cfgs []*config.MCPConfig) error {
+	var failures []string
+	check := func(cfgName, toolName, field, tmpl string) {
+		if tmpl == "" {
+			return
+		}
+		if err := ValidateTemplate(tmpl); err != nil {
+			failures = append(failures,
+				fmt.Sprintf("config %q, tool %q, %s: %v", cfgName, toolName, field, err))
+		}
+	}
+
+	for _, cfg := range cfgs {
+		for _, tool := range cfg.Tools {
+			check(cfg.Name, tool.Name, "endpoint", tool.Endpoint)
+			check(cfg.Name, tool.Name, "requestBody", tool.RequestBody)
+			check(cfg.Name, tool.Name, "responseBody", tool.ResponseBody)
+			for header, value := range tool.Headers {
+				check(cfg.Name, tool.Name, "header "+header, value)
+			}
+		}
+	}
+
+	if len(failures) > 0 {
+		msg := "template validation errors:"
+		for _, failure := range failures {
+			msg += "\n  " + failure
+		}
+		return fmt.Errorf("%s", msg)
+	}
+	return nil
+}
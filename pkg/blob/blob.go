@@ -0,0 +1,111 @@
+package blob
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Store persists binary attachments
+type Store interface {
+	// Put stores a blob under the given id
+	Put(ctx context.Context, id string, contentType string, data io.Reader) error
+	// Get opens a stored blob and reports its content type
+	Get(ctx context.Context, id string) (io.ReadCloser, string, error)
+	// Delete removes a stored blob
+	Delete(ctx context.Context, id string) error
+}
+
+// LocalStore keeps blobs in a directory; the content type is stored in a
+// sidecar file next to the blob
+type LocalStore struct {
+	dir string
+}
+
+var _ Store = (*LocalStore)(nil)
+
+// NewLocalStore creates a directory-backed blob store
+func NewLocalStore(dir string) (*LocalStore, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create blob directory: %w", err)
+	}
+	return &LocalStore{dir: dir}, nil
+}
+
+// path guards against traversal by allowing only simple ids
+func (s *LocalStore) path(id string) (string, error) {
+	if id == "" || strings.ContainsAny(id, "/\\.") {
+		return "", fmt.Errorf("invalid blob id")
+	}
+	return filepath.Join(s.dir, id), nil
+}
+
+// Put implements Store.Put
+func (s *LocalStore) Put(_ context.Context, id string, contentType string, data io.Reader) error {
+	path, err := s.path(id)
+	if err != nil {
+		return err
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	if _, err := io.Copy(file, data); err != nil {
+		return err
+	}
+	return os.WriteFile(path+".meta", []byte(contentType), 0644)
+}
+
+// Get implements Store.Get
+func (s *LocalStore) Get(_ context.Context, id string) (io.ReadCloser, string, error) {
+	path, err := s.path(id)
+	if err != nil {
+		return nil, "", err
+	}
+	contentType := "application/octet-stream"
+	if meta, err := os.ReadFile(path + ".meta"); err == nil {
+		contentType = string(meta)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, "", err
+	}
+	return file, contentType, nil
+}
+
+// Delete implements Store.Delete
+func (s *LocalStore) Delete(_ context.Context, id string) error {
+	path, err := s.path(id)
+	if err != nil {
+		return err
+	}
+	_ = os.Remove(path + ".meta")
+	return os.Remove(path)
+}
+
+// SignDownload produces the HMAC query parameters for a time-limited
+// download URL
+func SignDownload(secret, id string, expires time.Time) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%s:%d", id, expires.Unix())
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyDownload checks a download signature and its expiry
+func VerifyDownload(secret, id, expiresUnix, signature string) bool {
+	expires, err := strconv.ParseInt(expiresUnix, 10, 64)
+	if err != nil || time.Now().Unix() > expires {
+		return false
+	}
+	expected := SignDownload(secret, id, time.Unix(expires, 0))
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
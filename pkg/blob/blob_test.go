@@ -0,0 +1,92 @@
+package blob
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignDownloadVerify(t *testing.T) {
+	const (
+		secret = "test-secret"
+		id     = "9df28d4a-343e-4a00-8f8a-f9cd5bd42986"
+	)
+	future := time.Now().Add(time.Hour)
+	futureUnix := strconv.FormatInt(future.Unix(), 10)
+	sig := SignDownload(secret, id, future)
+
+	tests := []struct {
+		name      string
+		secret    string
+		id        string
+		expires   string
+		signature string
+		expected  bool
+	}{
+		{
+			name:      "valid signature",
+			secret:    secret,
+			id:        id,
+			expires:   futureUnix,
+			signature: sig,
+			expected:  true,
+		},
+		{
+			name:      "expired",
+			secret:    secret,
+			id:        id,
+			expires:   strconv.FormatInt(time.Now().Add(-time.Minute).Unix(), 10),
+			signature: SignDownload(secret, id, time.Now().Add(-time.Minute)),
+			expected:  false,
+		},
+		{
+			name:      "wrong secret",
+			secret:    "other-secret",
+			id:        id,
+			expires:   futureUnix,
+			signature: sig,
+			expected:  false,
+		},
+		{
+			name:      "different id",
+			secret:    secret,
+			id:        "other-id",
+			expires:   futureUnix,
+			signature: sig,
+			expected:  false,
+		},
+		{
+			name:      "tampered expiry",
+			secret:    secret,
+			id:        id,
+			expires:   strconv.FormatInt(future.Add(time.Hour).Unix(), 10),
+			signature: sig,
+			expected:  false,
+		},
+		{
+			name:      "malformed expiry",
+			secret:    secret,
+			id:        id,
+			expires:   "not-a-number",
+			signature: sig,
+			expected:  false,
+		},
+		{
+			name:      "empty signature",
+			secret:    secret,
+			id:        id,
+			expires:   futureUnix,
+			signature: "",
+			expected:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected,
+				VerifyDownload(tt.secret, tt.id, tt.expires, tt.signature))
+		})
+	}
+}
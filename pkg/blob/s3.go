@@ -0,0 +1,217 @@
+package blob
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// S3Store keeps blobs as objects in an S3-compatible bucket; the content
+// type is stored as the object's Content-Type
+type S3Store struct {
+	client       *http.Client
+	endpoint     string
+	bucket       string
+	prefix       string
+	region       string
+	accessKey    string
+	secretKey    string
+	sessionToken string
+	// pathStyle addresses the bucket in the path instead of the host,
+	// as most S3-compatible stores (MinIO etc.) require
+	pathStyle bool
+}
+
+var _ Store = (*S3Store)(nil)
+
+// S3Config configures an S3-backed blob store
+type S3Config struct {
+	Region       string
+	Bucket       string
+	Endpoint     string // empty uses the AWS endpoint for the region
+	Prefix       string // optional key prefix inside the bucket
+	AccessKey    string
+	SecretKey    string
+	SessionToken string
+	PathStyle    bool
+}
+
+// NewS3Store creates an S3-backed blob store
+func NewS3Store(cfg S3Config) (*S3Store, error) {
+	if cfg.Bucket == "" {
+		return nil, fmt.Errorf("s3 blob store requires a bucket")
+	}
+	if cfg.Region == "" {
+		return nil, fmt.Errorf("s3 blob store requires a region")
+	}
+
+	endpoint := cfg.Endpoint
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", cfg.Region)
+	}
+
+	return &S3Store{
+		client:       &http.Client{Timeout: 30 * time.Second},
+		endpoint:     strings.TrimSuffix(endpoint, "/"),
+		bucket:       cfg.Bucket,
+		prefix:       strings.Trim(cfg.Prefix, "/"),
+		region:       cfg.Region,
+		accessKey:    cfg.AccessKey,
+		secretKey:    cfg.SecretKey,
+		sessionToken: cfg.SessionToken,
+		pathStyle:    cfg.PathStyle,
+	}, nil
+}
+
+// objectURL builds the request URL for an object key
+func (s *S3Store) objectURL(id string) (string, error) {
+	if id == "" || strings.ContainsAny(id, "/\\.") {
+		return "", fmt.Errorf("invalid blob id")
+	}
+	key := id
+	if s.prefix != "" {
+		key = s.prefix + "/" + id
+	}
+	if s.pathStyle {
+		return s.endpoint + "/" + s.bucket + "/" + key, nil
+	}
+	u, err := url.Parse(s.endpoint)
+	if err != nil {
+		return "", err
+	}
+	u.Host = s.bucket + "." + u.Host
+	return u.String() + "/" + key, nil
+}
+
+// do signs and executes one S3 request, returning the response on 2xx
+func (s *S3Store) do(ctx context.Context, method, objectURL, contentType string, payload []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, objectURL, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	s.sign(req, payload, time.Now().UTC())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		resp.Body.Close()
+		return nil, fmt.Errorf("s3 request failed: status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return resp, nil
+}
+
+// Put implements Store.Put
+func (s *S3Store) Put(ctx context.Context, id string, contentType string, data io.Reader) error {
+	objectURL, err := s.objectURL(id)
+	if err != nil {
+		return err
+	}
+	// Attachments are size-capped by the caller, so buffering for the
+	// payload hash is acceptable
+	payload, err := io.ReadAll(data)
+	if err != nil {
+		return err
+	}
+	resp, err := s.do(ctx, http.MethodPut, objectURL, contentType, payload)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// Get implements Store.Get
+func (s *S3Store) Get(ctx context.Context, id string) (io.ReadCloser, string, error) {
+	objectURL, err := s.objectURL(id)
+	if err != nil {
+		return nil, "", err
+	}
+	resp, err := s.do(ctx, http.MethodGet, objectURL, "", nil)
+	if err != nil {
+		return nil, "", err
+	}
+	return resp.Body, resp.Header.Get("Content-Type"), nil
+}
+
+// Delete implements Store.Delete
+func (s *S3Store) Delete(ctx context.Context, id string) error {
+	objectURL, err := s.objectURL(id)
+	if err != nil {
+		return err
+	}
+	resp, err := s.do(ctx, http.MethodDelete, objectURL, "", nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// sign signs an S3 request with AWS Signature Version 4
+func (s *S3Store) sign(req *http.Request, payload []byte, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	dateStamp := now.Format("20060102")
+
+	payloadHash := sha256.Sum256(payload)
+	payloadHashHex := hex.EncodeToString(payloadHash[:])
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHashHex)
+	req.Header.Set("Host", req.URL.Host)
+
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+	canonicalHeaders := "host:" + req.URL.Host + "\n" +
+		"x-amz-content-sha256:" + payloadHashHex + "\n" +
+		"x-amz-date:" + amzDate + "\n"
+	if contentType := req.Header.Get("Content-Type"); contentType != "" {
+		signedHeaders = "content-type;" + signedHeaders
+		canonicalHeaders = "content-type:" + contentType + "\n" + canonicalHeaders
+	}
+	if s.sessionToken != "" {
+		// Temporary credentials must sign the security token as well
+		req.Header.Set("X-Amz-Security-Token", s.sessionToken)
+		signedHeaders += ";x-amz-security-token"
+		canonicalHeaders += "x-amz-security-token:" + s.sessionToken + "\n"
+	}
+
+	canonicalRequest := req.Method + "\n" +
+		req.URL.EscapedPath() + "\n" +
+		req.URL.RawQuery + "\n" +
+		canonicalHeaders + "\n" +
+		signedHeaders + "\n" +
+		payloadHashHex
+
+	credentialScope := dateStamp + "/" + s.region + "/s3/aws4_request"
+	canonicalHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + credentialScope + "\n" +
+		hex.EncodeToString(canonicalHash[:])
+
+	hmacSHA256 := func(key, data []byte) []byte {
+		mac := hmac.New(sha256.New, key)
+		mac.Write(data)
+		return mac.Sum(nil)
+	}
+	kDate := hmacSHA256([]byte("AWS4"+s.secretKey), []byte(dateStamp))
+	kRegion := hmacSHA256(kDate, []byte(s.region))
+	kService := hmacSHA256(kRegion, []byte("s3"))
+	kSigning := hmacSHA256(kService, []byte("aws4_request"))
+	signature := hex.EncodeToString(hmacSHA256(kSigning, []byte(stringToSign)))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.accessKey, credentialScope, signedHeaders, signature))
+}
@@ -0,0 +1,149 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/amoylab/unla/internal/common/config"
+	"github.com/amoylab/unla/pkg/mcp"
+)
+
+// anthropicProvider implements Provider over the native Messages API
+type anthropicProvider struct {
+	apiKey  string
+	baseURL string
+	model   string
+	client  *http.Client
+}
+
+func newAnthropicProvider(cfg *config.OpenAIConfig) *anthropicProvider {
+	baseURL := cfg.BaseURL
+	if baseURL == "" {
+		baseURL = "https://api.anthropic.com"
+	}
+	return &anthropicProvider{
+		apiKey:  cfg.APIKey,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		model:   cfg.Model,
+		client:  &http.Client{Timeout: 120 * time.Second},
+	}
+}
+
+// Complete implements Provider.Complete
+func (p *anthropicProvider) Complete(ctx context.Context, messages []Message, tools []mcp.ToolSchema) (*Completion, error) {
+	var system string
+	apiMessages := make([]map[string]any, 0, len(messages))
+	for _, msg := range messages {
+		switch msg.Role {
+		case "system":
+			system = msg.Content
+		case "tool":
+			apiMessages = append(apiMessages, map[string]any{
+				"role": "user",
+				"content": []map[string]any{{
+					"type":        "tool_result",
+					"tool_use_id": msg.ToolCallID,
+					"content":     msg.Content,
+				}},
+			})
+		case "assistant":
+			if len(msg.ToolCalls) > 0 {
+				content := make([]map[string]any, 0, len(msg.ToolCalls))
+				for _, call := range msg.ToolCalls {
+					var input map[string]any
+					_ = json.Unmarshal([]byte(call.Arguments), &input)
+					content = append(content, map[string]any{
+						"type":  "tool_use",
+						"id":    call.ID,
+						"name":  call.Name,
+						"input": input,
+					})
+				}
+				apiMessages = append(apiMessages, map[string]any{"role": "assistant", "content": content})
+			} else {
+				apiMessages = append(apiMessages, map[string]any{"role": "assistant", "content": msg.Content})
+			}
+		default:
+			apiMessages = append(apiMessages, map[string]any{"role": "user", "content": msg.Content})
+		}
+	}
+
+	body := map[string]any{
+		"model":      p.model,
+		"max_tokens": 4096,
+		"messages":   apiMessages,
+	}
+	if system != "" {
+		body["system"] = system
+	}
+	if len(tools) > 0 {
+		toolDefs := make([]map[string]any, 0, len(tools))
+		for _, tool := range tools {
+			var schema map[string]any
+			raw, err := json.Marshal(tool.InputSchema)
+			if err == nil {
+				_ = json.Unmarshal(raw, &schema)
+			}
+			toolDefs = append(toolDefs, map[string]any{
+				"name":         tool.Name,
+				"description":  tool.Description,
+				"input_schema": schema,
+			})
+		}
+		body["tools"] = toolDefs
+	}
+
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+"/v1/messages", bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-api-key", p.apiKey)
+	req.Header.Set("anthropic-version", "2023-06-01")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("anthropic request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("anthropic request failed: status %d", resp.StatusCode)
+	}
+
+	var apiResp struct {
+		Content []struct {
+			Type  string          `json:"type"`
+			Text  string          `json:"text"`
+			ID    string          `json:"id"`
+			Name  string          `json:"name"`
+			Input json.RawMessage `json:"input"`
+		} `json:"content"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&apiResp); err != nil {
+		return nil, fmt.Errorf("failed to decode anthropic response: %w", err)
+	}
+
+	completion := &Completion{}
+	for _, block := range apiResp.Content {
+		switch block.Type {
+		case "text":
+			completion.Content += block.Text
+		case "tool_use":
+			completion.ToolCalls = append(completion.ToolCalls, ToolCall{
+				ID:        block.ID,
+				Name:      block.Name,
+				Arguments: string(block.Input),
+			})
+		}
+	}
+	return completion, nil
+}
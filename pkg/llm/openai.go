@@ -0,0 +1,95 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/amoylab/unla/internal/common/config"
+	"github.com/amoylab/unla/pkg/mcp"
+
+	"github.com/openai/openai-go"
+	"github.com/openai/openai-go/option"
+)
+
+// openaiProvider implements Provider over any OpenAI-compatible API
+type openaiProvider struct {
+	client openai.Client
+	model  string
+}
+
+func newOpenAIProvider(cfg *config.OpenAIConfig) *openaiProvider {
+	client := openai.NewClient(
+		option.WithAPIKey(cfg.APIKey),
+		option.WithBaseURL(cfg.BaseURL),
+	)
+	return &openaiProvider{client: client, model: cfg.Model}
+}
+
+// Complete implements Provider.Complete
+func (p *openaiProvider) Complete(ctx context.Context, messages []Message, tools []mcp.ToolSchema) (*Completion, error) {
+	params := openai.ChatCompletionNewParams{
+		Model:    p.model,
+		Messages: make([]openai.ChatCompletionMessageParamUnion, 0, len(messages)),
+	}
+
+	for _, msg := range messages {
+		switch msg.Role {
+		case "system":
+			params.Messages = append(params.Messages, openai.SystemMessage(msg.Content))
+		case "assistant":
+			if len(msg.ToolCalls) > 0 {
+				assistant := openai.ChatCompletionAssistantMessageParam{}
+				for _, call := range msg.ToolCalls {
+					assistant.ToolCalls = append(assistant.ToolCalls, openai.ChatCompletionMessageToolCallParam{
+						ID: call.ID,
+						Function: openai.ChatCompletionMessageToolCallFunctionParam{
+							Name:      call.Name,
+							Arguments: call.Arguments,
+						},
+					})
+				}
+				params.Messages = append(params.Messages, openai.ChatCompletionMessageParamUnion{OfAssistant: &assistant})
+			} else {
+				params.Messages = append(params.Messages, openai.AssistantMessage(msg.Content))
+			}
+		case "tool":
+			params.Messages = append(params.Messages, openai.ToolMessage(msg.Content, msg.ToolCallID))
+		default:
+			params.Messages = append(params.Messages, openai.UserMessage(msg.Content))
+		}
+	}
+
+	for _, tool := range tools {
+		var schema map[string]any
+		raw, err := json.Marshal(tool.InputSchema)
+		if err == nil {
+			_ = json.Unmarshal(raw, &schema)
+		}
+		params.Tools = append(params.Tools, openai.ChatCompletionToolParam{
+			Function: openai.FunctionDefinitionParam{
+				Name:        tool.Name,
+				Description: openai.String(tool.Description),
+				Parameters:  schema,
+			},
+		})
+	}
+
+	resp, err := p.client.Chat.Completions.New(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Choices) == 0 {
+		return &Completion{}, nil
+	}
+
+	choice := resp.Choices[0].Message
+	completion := &Completion{Content: choice.Content}
+	for _, call := range choice.ToolCalls {
+		completion.ToolCalls = append(completion.ToolCalls, ToolCall{
+			ID:        call.ID,
+			Name:      call.Function.Name,
+			Arguments: call.Function.Arguments,
+		})
+	}
+	return completion, nil
+}
@@ -0,0 +1,50 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/amoylab/unla/internal/common/config"
+	"github.com/amoylab/unla/pkg/mcp"
+)
+
+// Message is the provider-neutral chat message shape
+type Message struct {
+	Role       string     `json:"role"` // system, user, assistant or tool
+	Content    string     `json:"content"`
+	ToolCallID string     `json:"toolCallId,omitempty"` // for role tool
+	ToolCalls  []ToolCall `json:"toolCalls,omitempty"`  // for role assistant
+}
+
+// ToolCall is a tool invocation requested by the model
+type ToolCall struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"` // JSON-encoded
+}
+
+// Completion is one model response turn
+type Completion struct {
+	Content   string     `json:"content"`
+	ToolCalls []ToolCall `json:"toolCalls,omitempty"`
+}
+
+// Provider abstracts a chat completion backend
+type Provider interface {
+	// Complete runs one model turn with optional tool definitions
+	Complete(ctx context.Context, messages []Message, tools []mcp.ToolSchema) (*Completion, error)
+}
+
+// NewProvider creates a provider from the configuration. The "openai"
+// provider also covers Azure OpenAI and Ollama through base_url; the
+// "anthropic" provider speaks the native Messages API.
+func NewProvider(cfg *config.OpenAIConfig) (Provider, error) {
+	switch cfg.Provider {
+	case "", "openai", "azure", "ollama":
+		return newOpenAIProvider(cfg), nil
+	case "anthropic":
+		return newAnthropicProvider(cfg), nil
+	default:
+		return nil, fmt.Errorf("unsupported LLM provider: %s", cfg.Provider)
+	}
+}
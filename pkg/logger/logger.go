@@ -36,6 +36,9 @@ func NewLogger(cfg *config.LoggerConfig) (*zap.Logger, error) {
 			return nil, err
 		}
 		syncer = getLogWriter(cfg)
+	} else if cfg.Output == "stderr" {
+		// Used when stdout carries protocol traffic, e.g. stdio serving mode
+		syncer = zapcore.AddSync(os.Stderr)
 	} else {
 		syncer = zapcore.AddSync(os.Stdout)
 	}
@@ -44,12 +47,13 @@ func NewLogger(cfg *config.LoggerConfig) (*zap.Logger, error) {
 	if level < zapcore.DebugLevel || level > zapcore.FatalLevel {
 		level = zapcore.InfoLevel
 	}
+	atomicLevel.SetLevel(level)
 
 	logger := zap.New(
 		zapcore.NewCore(
 			encoder,
 			syncer,
-			level,
+			atomicLevel,
 		),
 		defaultZapOpts...,
 	)
@@ -172,3 +176,21 @@ func getLogLevel(level string) zapcore.Level {
 	}
 	return levelInt
 }
+
+// atomicLevel lets the log level be changed at runtime
+var atomicLevel = zap.NewAtomicLevel()
+
+// SetLevel changes the runtime log level of loggers created by NewLogger
+func SetLevel(level string) error {
+	parsed, err := zapcore.ParseLevel(level)
+	if err != nil {
+		return err
+	}
+	atomicLevel.SetLevel(parsed)
+	return nil
+}
+
+// Level reports the current runtime log level
+func Level() string {
+	return atomicLevel.Level().String()
+}
@@ -8,6 +8,31 @@ const (
 	JSPNRPCVersion          = "2.0"
 )
 
+// SupportedProtocolVersions lists the protocol revisions this server can
+// speak, newest first
+var SupportedProtocolVersions = []string{
+	ProtocolVersion20250326,
+	ProtocolVersion20241105,
+}
+
+// NegotiateProtocolVersion picks the protocol version to answer an
+// initialize request with: the client's version when supported, otherwise
+// the latest version this server speaks.
+//
+// Only the advertised version is negotiated; responses are not reshaped
+// per revision. The revisions listed above share the same wire format for
+// everything this gateway emits, so that is currently safe, but adding a
+// revision with incompatible response shapes will require per-version
+// handling in the response path.
+func NegotiateProtocolVersion(requested string) string {
+	for _, version := range SupportedProtocolVersions {
+		if version == requested {
+			return version
+		}
+	}
+	return LatestProtocolVersion
+}
+
 // Methods
 const (
 	Initialize              = "initialize"
@@ -16,7 +41,7 @@ const (
 	ToolsList               = "tools/list"
 	ToolsCall               = "tools/call"
 	PromptsList             = "prompts/list"
-	PromptsGet              = "prompts/get"		
+	PromptsGet              = "prompts/get"
 )
 
 // Response
@@ -56,6 +56,24 @@ type (
 		Description string `json:"description"`
 		// A JSON Schema object defining the expected parameters for the tool
 		InputSchema ToolInputSchema `json:"inputSchema"`
+		// An optional JSON Schema describing structured results
+		OutputSchema map[string]any `json:"outputSchema,omitempty"`
+		// Optional behavior hints for clients
+		Annotations *ToolAnnotationsSchema `json:"annotations,omitempty"`
+	}
+
+	// ToolAnnotationsSchema carries the MCP tool behavior hints
+	ToolAnnotationsSchema struct {
+		// Human-readable title for the tool
+		Title string `json:"title,omitempty" yaml:"title,omitempty"`
+		// If true, the tool does not modify its environment
+		ReadOnlyHint *bool `json:"readOnlyHint,omitempty" yaml:"readOnlyHint,omitempty"`
+		// If true, the tool may perform destructive updates
+		DestructiveHint *bool `json:"destructiveHint,omitempty" yaml:"destructiveHint,omitempty"`
+		// If true, repeated calls with the same args have no additional effect
+		IdempotentHint *bool `json:"idempotentHint,omitempty" yaml:"idempotentHint,omitempty"`
+		// If true, the tool interacts with external entities
+		OpenWorldHint *bool `json:"openWorldHint,omitempty" yaml:"openWorldHint,omitempty"`
 	}
 
 	ToolInputSchema struct {
@@ -69,6 +87,15 @@ type (
 	// ListToolsResult represents the result of a tools/list request
 	ListToolsResult struct {
 		Tools []ToolSchema `json:"tools"`
+		// NextCursor is an opaque token for fetching the next page; empty
+		// when there are no further results
+		NextCursor string `json:"nextCursor,omitempty"`
+	}
+
+	// PaginatedRequestParams represents parameters for paginated list requests
+	PaginatedRequestParams struct {
+		// Cursor is the opaque pagination token from a previous response
+		Cursor string `json:"cursor"`
 	}
 
 	// CallToolParams represents parameters for a tools/call request
@@ -121,7 +148,10 @@ type (
 	// CallToolResult represents the result of a tools/call request
 	CallToolResult struct {
 		Content []Content `json:"content"`
-		IsError bool      `json:"isError"`
+		// StructuredContent carries the parsed result for tools declaring
+		// an outputSchema (2025-03-26 revision and later)
+		StructuredContent any  `json:"structuredContent,omitempty"`
+		IsError           bool `json:"isError"`
 	}
 
 	// ImplementationSchema describes the name and version of an MCP implementation
@@ -229,12 +259,69 @@ type (
 		Data any `json:"data,omitempty"`
 	}
 
+	// ResourceSchema represents a resource definition
+	ResourceSchema struct {
+		// The URI of the resource
+		URI string `json:"uri" yaml:"uri"`
+		// A human-readable name for the resource
+		Name string `json:"name" yaml:"name"`
+		// A description of what the resource contains
+		Description string `json:"description,omitempty" yaml:"description,omitempty"`
+		// The MIME type of the resource, if known
+		MimeType string `json:"mimeType,omitempty" yaml:"mimeType,omitempty"`
+	}
+
+	// ResourceTemplateSchema represents a parameterized resource definition
+	ResourceTemplateSchema struct {
+		// A URI template (RFC 6570) that can be expanded into a resource URI
+		URITemplate string `json:"uriTemplate" yaml:"uriTemplate"`
+		// A human-readable name for the type of resource this template refers to
+		Name string `json:"name" yaml:"name"`
+		// A description of what this template is for
+		Description string `json:"description,omitempty" yaml:"description,omitempty"`
+		// The MIME type for all resources that match this template, if uniform
+		MimeType string `json:"mimeType,omitempty" yaml:"mimeType,omitempty"`
+	}
+
+	// ResourceContents represents the contents of a single resource
+	ResourceContents struct {
+		// The URI of the resource
+		URI string `json:"uri"`
+		// The MIME type of the resource, if known
+		MimeType string `json:"mimeType,omitempty"`
+		// The text content of the resource, for text resources
+		Text string `json:"text,omitempty"`
+		// The base64-encoded content of the resource, for binary resources
+		Blob string `json:"blob,omitempty"`
+	}
+
+	// ListResourcesResult represents the result of a resources/list request
+	ListResourcesResult struct {
+		Resources []ResourceSchema `json:"resources"`
+	}
+
+	// ListResourceTemplatesResult represents the result of a resources/templates/list request
+	ListResourceTemplatesResult struct {
+		ResourceTemplates []ResourceTemplateSchema `json:"resourceTemplates"`
+	}
+
+	// ReadResourceResult represents the result of a resources/read request
+	ReadResourceResult struct {
+		Contents []ResourceContents `json:"contents"`
+	}
+
+	// ReadResourceRequestParams represents parameters for a resources/read request
+	ReadResourceRequestParams struct {
+		// The URI of the resource to read
+		URI string `json:"uri"`
+	}
+
 	// PromptSchema and related types
 
 	PromptSchema struct {
-		Name        string                 `json:"name" yaml:"name"`
-		Description string                 `json:"description" yaml:"description"`
-		Arguments   []PromptArgumentSchema `json:"arguments" yaml:"arguments"`
+		Name           string                 `json:"name" yaml:"name"`
+		Description    string                 `json:"description" yaml:"description"`
+		Arguments      []PromptArgumentSchema `json:"arguments" yaml:"arguments"`
 		PromptResponse []PromptResponseSchema `json:"promptResponse,omitempty" yaml:"promptResponse,omitempty"`
 	}
 
@@ -245,14 +332,33 @@ type (
 	}
 
 	PromptResponseSchema struct {
-		Role    string                `json:"role" yaml:"role"`
+		Role    string                      `json:"role" yaml:"role"`
 		Content PromptResponseContentSchema `json:"content" yaml:"content"`
 	}
 
 	PromptResponseContentSchema struct {
 		Type string `json:"type" yaml:"type"`
 		Text string `json:"text" yaml:"text"`
-	}	
+	}
+
+	// ListPromptsResult represents the result of a prompts/list request
+	ListPromptsResult struct {
+		Prompts []PromptSchema `json:"prompts"`
+	}
+
+	// GetPromptRequestParams represents parameters for a prompts/get request
+	GetPromptRequestParams struct {
+		// The name of the prompt to retrieve
+		Name string `json:"name"`
+		// Arguments to substitute into the prompt template
+		Arguments map[string]string `json:"arguments"`
+	}
+
+	// GetPromptResult represents the result of a prompts/get request
+	GetPromptResult struct {
+		Description string                 `json:"description"`
+		Messages    []PromptResponseSchema `json:"messages"`
+	}
 )
 
 // NewInitializeRequest creates a new initialize request
@@ -377,4 +483,3 @@ func NewCallToolResultError(text string) *CallToolResult {
 		IsError: true,
 	}
 }
-
@@ -102,7 +102,7 @@ func (c *Converter) Convert(specData []byte) (*config.MCPConfig, error) {
 		CORS: &config.CORSConfig{
 			AllowOrigins:     []string{"*"},
 			AllowMethods:     []string{"GET", "POST", "OPTIONS"},
-			AllowHeaders:     []string{"Content-Type", "Authorization", "Mcp-Session-Id" ,"mcp-protocol-version"},
+			AllowHeaders:     []string{"Content-Type", "Authorization", "Mcp-Session-Id", "mcp-protocol-version"},
 			ExposeHeaders:    []string{"Mcp-Session-Id", "mcp-protocol-version"},
 			AllowCredentials: true,
 		},
@@ -336,13 +336,13 @@ func (c *Converter) ConvertWithOptions(specData []byte, tenant, prefix string) (
 	if err != nil {
 		return nil, err
 	}
-    // Remove leading / from tenant if present
+	// Remove leading / from tenant if present
 	cleanTenant := tenant
 	if strings.HasPrefix(cleanTenant, "/") {
 		cleanTenant = strings.TrimPrefix(cleanTenant, "/")
-	}	
+	}
 	if tenant != "" && prefix != "" {
-		config.Tenant = cleanTenant 
+		config.Tenant = cleanTenant
 		if len(config.Routers) > 0 {
 			config.Routers[0].Prefix = cleanTenant + "/" + prefix
 		}